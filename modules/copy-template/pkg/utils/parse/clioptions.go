@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"go.uber.org/zap/zapcore"
@@ -22,8 +23,9 @@ type CLIOptions struct {
 	TargetTemplateName      string `arg:"--target-template-name,env:TARGET_TEMPLATE_NAME" placeholder:"NAME" help:"Name of a target template"`
 	TargetTemplateNamespace string `arg:"--target-template-namespace,env:TARGET_TEMPLATE_NAMESPACE" placeholder:"NAMESPACE" help:"Namespace of a target template"`
 
-	Output output.OutputType `arg:"-o" placeholder:"FORMAT" help:"Output format. One of: yaml|json"`
-	Debug  bool              `arg:"--debug" help:"Sets DEBUG log level"`
+	Output    output.OutputType `arg:"-o" placeholder:"FORMAT" help:"Output format. One of: yaml|json"`
+	Debug     bool              `arg:"--debug" help:"Sets DEBUG log level"`
+	LogFormat string            `arg:"--log-format,env:LOG_FORMAT" placeholder:"json|console" help:"Log format. One of: json|console. Defaults to json."`
 }
 
 func (c *CLIOptions) GetDebugLevel() zapcore.Level {
@@ -33,6 +35,10 @@ func (c *CLIOptions) GetDebugLevel() zapcore.Level {
 	return zapcore.InfoLevel
 }
 
+func (c *CLIOptions) GetLogFormat() log.LogFormat {
+	return log.LogFormat(c.LogFormat)
+}
+
 func (c *CLIOptions) GetSourceTemplateNamespace() string {
 	return c.SourceTemplateNamespace
 }
@@ -100,5 +106,9 @@ func (c *CLIOptions) assertValidTypes() error {
 	if !output.IsOutputType(string(c.Output)) {
 		return zerrors.NewMissingRequiredError("%v is not a valid output type", c.Output)
 	}
+
+	if !log.IsLogFormat(c.LogFormat) {
+		return zerrors.NewMissingRequiredError("%v is not a valid log format", c.LogFormat)
+	}
 	return nil
 }
@@ -34,6 +34,14 @@ var _ = Describe("CLIOptions", func() {
 					TargetTemplateNamespace: testStringTargetNamespace,
 					Output:                  "non-existing",
 				}),
+			table.Entry("wrong log format", "text is not a valid log format",
+				&parse.CLIOptions{
+					SourceTemplateName:      testStringSourceName,
+					SourceTemplateNamespace: testStringSourceNamespace,
+					TargetTemplateName:      testStringTargetName,
+					TargetTemplateNamespace: testStringTargetNamespace,
+					LogFormat:               "text",
+				}),
 		)
 	})
 	Context("correct cli options", func() {
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+
+	goarg "github.com/alexflint/go-arg"
+	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/dvcreator"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
+	res "github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/results"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"go.uber.org/zap"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+func main() {
+	defer exit.HandleExit()
+
+	cliOptions := &parse.CLIOptions{}
+	goarg.MustParse(cliOptions)
+
+	logger := log.InitLoggerWithFormat(cliOptions.GetDebugLevel(), cliOptions.GetLogFormat())
+	defer logger.Sync()
+
+	if err := cliOptions.Init(); err != nil {
+		exit.ExitOrDieFromError(InvalidCLIInputExitCode, err)
+	}
+	log.Logger().Debug("parsed arguments", zap.Reflect("cliOptions", cliOptions))
+
+	dvCreator, err := dvcreator.NewDataVolumeCreator(cliOptions)
+
+	if err != nil {
+		exit.ExitOrDieFromError(GenericExitCode, err)
+	}
+
+	var dv *datavolumev1beta1.DataVolume
+	err = log.Phase("CreateDataVolume", func() (err error) {
+		dv, err = dvCreator.CreateDataVolume()
+		return err
+	})
+
+	if err != nil {
+		exit.ExitOrDieFromError(CreateDataVolumeErrorExitCode, err,
+			zerrors.IsStatusError(err, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+		)
+	}
+	log.AddTarget("DataVolume", dv.Namespace, dv.Name)
+	log.Logger().Info("Created DataVolume", zap.String("name", dv.Name), zap.String("namespace", dv.Namespace))
+
+	recordResults(dv.Name, dv.Namespace)
+
+	if cliOptions.GetWaitForSuccess() {
+		log.Logger().Debug("waiting for DataVolume to succeed", zap.String("name", dv.Name), zap.String("namespace", dv.Namespace))
+		if err := log.Phase("WaitForSuccess", func() error { return dvCreator.WaitForSuccess(dv) }); err != nil {
+			exit.ExitOrDieFromError(WaitForSuccessErrorExitCode, err)
+		}
+	}
+
+	output.PrettyPrint(dv, cliOptions.Output)
+}
+
+func recordResults(name, namespace string) {
+	results := map[string]string{
+		NameResultName:      name,
+		NamespaceResultName: namespace,
+	}
+
+	log.Logger().Debug("recording results", zap.Reflect("results", results))
+	if err := res.RecordResults(results); err != nil {
+		exit.ExitOrDieFromError(WriteResultsExitCode, err)
+	}
+}
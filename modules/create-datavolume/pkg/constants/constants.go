@@ -0,0 +1,33 @@
+package constants
+
+import "time"
+
+// Exit codes
+const (
+	GenericExitCode               = 1
+	InvalidCLIInputExitCode       = 2
+	CreateDataVolumeErrorExitCode = 3
+	WriteResultsExitCode          = 4
+	DataVolumeFailedExitCode      = 5
+	PVCNotBoundExitCode           = 6
+	WaitForSuccessTimeoutExitCode = 7
+	WaitForSuccessErrorExitCode   = 8
+	ImporterPodRestartsExitCode   = 9
+)
+
+// Result names
+const (
+	NameResultName      = "name"
+	NamespaceResultName = "namespace"
+)
+
+const DefaultWaitForSuccessTimeout = 720 * time.Hour
+const DefaultImporterPodMaxRestarts = 3
+
+type CreationMode string
+
+const (
+	DataVolumeManifestCreationMode CreationMode = "DataVolumeManifestCreationMode"
+	SourceCreationMode             CreationMode = "SourceCreationMode"
+	SnapshotCreationMode           CreationMode = "SnapshotCreationMode"
+)
@@ -0,0 +1,72 @@
+package datavolume
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	datavolumeclientv1beta1 "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/typed/core/v1beta1"
+)
+
+type dataVolumeProvider struct {
+	client datavolumeclientv1beta1.CdiV1beta1Interface
+}
+
+type DataVolumeProvider interface {
+	Create(namespace string, dv *datavolumev1beta1.DataVolume) (*datavolumev1beta1.DataVolume, error)
+	CreateFromSnapshot(namespace string, dv *SnapshotDataVolume) (*datavolumev1beta1.DataVolume, error)
+	Get(namespace, name string) (*datavolumev1beta1.DataVolume, error)
+	NewListWatch(namespace, name string) cache.ListerWatcher
+}
+
+func NewDataVolumeProvider(client datavolumeclientv1beta1.CdiV1beta1Interface) DataVolumeProvider {
+	return &dataVolumeProvider{
+		client: client,
+	}
+}
+
+func (d *dataVolumeProvider) Create(namespace string, dv *datavolumev1beta1.DataVolume) (*datavolumev1beta1.DataVolume, error) {
+	return d.client.DataVolumes(namespace).Create(context.TODO(), dv, metav1.CreateOptions{})
+}
+
+// CreateFromSnapshot posts the DataVolume with the REST client, because the typed client cannot send the snapshot source
+func (d *dataVolumeProvider) CreateFromSnapshot(namespace string, dv *SnapshotDataVolume) (*datavolumev1beta1.DataVolume, error) {
+	body, err := json.Marshal(dv)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &datavolumev1beta1.DataVolume{}
+	err = d.client.RESTClient().Post().
+		Namespace(namespace).
+		Resource("datavolumes").
+		Body(body).
+		Do(context.TODO()).
+		Into(result)
+
+	return result, err
+}
+
+func (d *dataVolumeProvider) Get(namespace, name string) (*datavolumev1beta1.DataVolume, error) {
+	return d.client.DataVolumes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (d *dataVolumeProvider) NewListWatch(namespace, name string) cache.ListerWatcher {
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return d.client.DataVolumes(namespace).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return d.client.DataVolumes(namespace).Watch(context.TODO(), options)
+		},
+	}
+}
@@ -0,0 +1,155 @@
+package datavolume
+
+import (
+	"strings"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	dataVolumeKind   = "DataVolume"
+	noFailureMessage = "no details available"
+)
+
+func DecodeDataVolume(manifest string) (*datavolumev1beta1.DataVolume, error) {
+	var typeMeta metav1.TypeMeta
+
+	if err := yaml.Unmarshal([]byte(manifest), &typeMeta); err != nil {
+		return nil, zerrors.NewMissingRequiredError("could not read DataVolume manifest: %v", err.Error())
+	}
+
+	if typeMeta.Kind != dataVolumeKind {
+		return nil, zerrors.NewMissingRequiredError("manifest does not contain %v kind", dataVolumeKind)
+	}
+
+	if apiVersion := datavolumev1beta1.SchemeGroupVersion.String(); typeMeta.APIVersion != apiVersion {
+		return nil, zerrors.NewMissingRequiredError("unsupported %v apiVersion %v: only %v is supported", dataVolumeKind, typeMeta.APIVersion, apiVersion)
+	}
+
+	var dv datavolumev1beta1.DataVolume
+	if err := yaml.Unmarshal([]byte(manifest), &dv); err != nil {
+		return nil, zerrors.NewMissingRequiredError("could not read DataVolume manifest: %v", err.Error())
+	}
+
+	return &dv, nil
+}
+
+// returns nil if the DataVolume does not have a condition of the given type
+func GetCondition(dv *datavolumev1beta1.DataVolume, conditionType datavolumev1beta1.DataVolumeConditionType) *datavolumev1beta1.DataVolumeCondition {
+	for i := 0; i < len(dv.Status.Conditions); i++ {
+		if dv.Status.Conditions[i].Type == conditionType {
+			return &dv.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func IsSucceeded(dv *datavolumev1beta1.DataVolume) bool {
+	if dv.Status.Phase == datavolumev1beta1.Succeeded {
+		return true
+	}
+	ready := GetCondition(dv, datavolumev1beta1.DataVolumeReady)
+	return ready != nil && ready.Status == v1.ConditionTrue
+}
+
+func IsFailed(dv *datavolumev1beta1.DataVolume) bool {
+	return dv.Status.Phase == datavolumev1beta1.Failed
+}
+
+func IsBound(dv *datavolumev1beta1.DataVolume) bool {
+	bound := GetCondition(dv, datavolumev1beta1.DataVolumeBound)
+	return bound != nil && bound.Status == v1.ConditionTrue
+}
+
+// GetFailureMessage collects messages of conditions which are not satisfied
+func GetFailureMessage(dv *datavolumev1beta1.DataVolume) string {
+	var messages []string
+	seen := make(map[string]bool)
+
+	for _, conditionType := range []datavolumev1beta1.DataVolumeConditionType{datavolumev1beta1.DataVolumeRunning, datavolumev1beta1.DataVolumeReady, datavolumev1beta1.DataVolumeBound} {
+		if condition := GetCondition(dv, conditionType); condition != nil && condition.Status != v1.ConditionTrue {
+			message := strings.TrimSpace(condition.Message)
+			if message == "" {
+				message = condition.Reason
+			}
+			if message != "" && !seen[message] {
+				seen[message] = true
+				messages = append(messages, message)
+			}
+		}
+	}
+
+	if len(messages) == 0 {
+		return noFailureMessage
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// DataVolumeSourceSnapshot provides the parameters to create a DataVolume from a VolumeSnapshot.
+// It mirrors the snapshot source of CDI v1.55, which the vendored CDI v1beta1 API does not have.
+type DataVolumeSourceSnapshot struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// SnapshotDataVolume is a v1beta1 DataVolume which is populated from a VolumeSnapshot
+type SnapshotDataVolume struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SnapshotDataVolumeSpec `json:"spec"`
+}
+
+// SnapshotDataVolumeSpec is a v1beta1 DataVolumeSpec with the snapshot source
+type SnapshotDataVolumeSpec struct {
+	datavolumev1beta1.DataVolumeSpec `json:",inline"`
+
+	Source SnapshotDataVolumeSource `json:"source"`
+}
+
+type SnapshotDataVolumeSource struct {
+	Snapshot *DataVolumeSourceSnapshot `json:"snapshot"`
+}
+
+func NewDataVolume(name, namespace string, source *datavolumev1beta1.DataVolumeSource, pvcSpec *v1.PersistentVolumeClaimSpec) *datavolumev1beta1.DataVolume {
+	return &datavolumev1beta1.DataVolume{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       dataVolumeKind,
+			APIVersion: datavolumev1beta1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: datavolumev1beta1.DataVolumeSpec{
+			Source: *source,
+			PVC:    pvcSpec,
+		},
+	}
+}
+
+func NewSnapshotDataVolume(name, namespace string, snapshot *DataVolumeSourceSnapshot, pvcSpec *v1.PersistentVolumeClaimSpec) *SnapshotDataVolume {
+	return &SnapshotDataVolume{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       dataVolumeKind,
+			APIVersion: datavolumev1beta1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: SnapshotDataVolumeSpec{
+			DataVolumeSpec: datavolumev1beta1.DataVolumeSpec{
+				PVC: pvcSpec,
+			},
+			Source: SnapshotDataVolumeSource{
+				Snapshot: snapshot,
+			},
+		},
+	}
+}
@@ -0,0 +1,16 @@
+package datavolume_test
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utilstest"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDatavolume(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Datavolume Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
@@ -0,0 +1,110 @@
+package datavolume_test
+
+import (
+	"encoding/json"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/datavolume"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+func newDataVolume(phase datavolumev1beta1.DataVolumePhase, conditions ...datavolumev1beta1.DataVolumeCondition) *datavolumev1beta1.DataVolume {
+	return &datavolumev1beta1.DataVolume{
+		Status: datavolumev1beta1.DataVolumeStatus{
+			Phase:      phase,
+			Conditions: conditions,
+		},
+	}
+}
+
+func newCondition(conditionType datavolumev1beta1.DataVolumeConditionType, status v1.ConditionStatus, reason, message string) datavolumev1beta1.DataVolumeCondition {
+	return datavolumev1beta1.DataVolumeCondition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+var _ = Describe("DataVolume", func() {
+	It("decodes DataVolume manifest", func() {
+		dv, err := datavolume.DecodeDataVolume("apiVersion: cdi.kubevirt.io/v1beta1\nkind: DataVolume\nmetadata:\n  name: test-dv\n  namespace: test-ns\n")
+		Expect(err).Should(Succeed())
+		Expect(dv.Name).To(Equal("test-dv"))
+		Expect(dv.Namespace).To(Equal("test-ns"))
+	})
+
+	It("creates DataVolume", func() {
+		source := &datavolumev1beta1.DataVolumeSource{HTTP: &datavolumev1beta1.DataVolumeSourceHTTP{URL: "https://example.com/disk.qcow2"}}
+		pvcSpec := &v1.PersistentVolumeClaimSpec{AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}}
+
+		dv := datavolume.NewDataVolume("test-dv", "test-ns", source, pvcSpec)
+		Expect(dv.Kind).To(Equal("DataVolume"))
+		Expect(dv.APIVersion).To(Equal("cdi.kubevirt.io/v1beta1"))
+		Expect(dv.Name).To(Equal("test-dv"))
+		Expect(dv.Namespace).To(Equal("test-ns"))
+		Expect(dv.Spec.Source).To(Equal(*source))
+		Expect(dv.Spec.PVC).To(Equal(pvcSpec))
+	})
+
+	It("creates DataVolume from snapshot", func() {
+		snapshot := &datavolume.DataVolumeSourceSnapshot{Namespace: "snapshot-ns", Name: "snapshot"}
+		pvcSpec := &v1.PersistentVolumeClaimSpec{AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}}
+
+		dv := datavolume.NewSnapshotDataVolume("test-dv", "test-ns", snapshot, pvcSpec)
+		body, err := json.Marshal(dv)
+		Expect(err).Should(Succeed())
+
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal(body, &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("kind", "DataVolume"))
+		Expect(decoded).To(HaveKeyWithValue("apiVersion", "cdi.kubevirt.io/v1beta1"))
+		Expect(decoded["metadata"]).To(HaveKeyWithValue("name", "test-dv"))
+		Expect(decoded["metadata"]).To(HaveKeyWithValue("namespace", "test-ns"))
+		Expect(decoded["spec"]).To(HaveKeyWithValue("source", map[string]interface{}{
+			"snapshot": map[string]interface{}{"namespace": "snapshot-ns", "name": "snapshot"},
+		}))
+		Expect(decoded["spec"]).To(HaveKeyWithValue("pvc", map[string]interface{}{
+			"accessModes": []interface{}{"ReadWriteOnce"},
+			"resources":   map[string]interface{}{},
+		}))
+	})
+
+	table.DescribeTable("resolves state", func(dv *datavolumev1beta1.DataVolume, succeeded, failed, bound bool) {
+		Expect(datavolume.IsSucceeded(dv)).To(Equal(succeeded))
+		Expect(datavolume.IsFailed(dv)).To(Equal(failed))
+		Expect(datavolume.IsBound(dv)).To(Equal(bound))
+	},
+		table.Entry("empty", newDataVolume(""), false, false, false),
+		table.Entry("pending", newDataVolume(datavolumev1beta1.Pending,
+			newCondition(datavolumev1beta1.DataVolumeBound, v1.ConditionFalse, "Pending", ""),
+		), false, false, false),
+		table.Entry("importing", newDataVolume(datavolumev1beta1.ImportInProgress,
+			newCondition(datavolumev1beta1.DataVolumeBound, v1.ConditionTrue, "Bound", ""),
+		), false, false, true),
+		table.Entry("succeeded", newDataVolume(datavolumev1beta1.Succeeded,
+			newCondition(datavolumev1beta1.DataVolumeBound, v1.ConditionTrue, "Bound", ""),
+		), true, false, true),
+		table.Entry("ready", newDataVolume(datavolumev1beta1.WaitForFirstConsumer,
+			newCondition(datavolumev1beta1.DataVolumeReady, v1.ConditionTrue, "", ""),
+		), true, false, false),
+		table.Entry("failed", newDataVolume(datavolumev1beta1.Failed), false, true, false),
+	)
+
+	table.DescribeTable("collects failure message", func(dv *datavolumev1beta1.DataVolume, expectedMessage string) {
+		Expect(datavolume.GetFailureMessage(dv)).To(Equal(expectedMessage))
+	},
+		table.Entry("no conditions", newDataVolume(datavolumev1beta1.Failed), "no details available"),
+		table.Entry("satisfied conditions", newDataVolume(datavolumev1beta1.Failed,
+			newCondition(datavolumev1beta1.DataVolumeBound, v1.ConditionTrue, "Bound", "PVC bound"),
+		), "no details available"),
+		table.Entry("uses messages and reasons", newDataVolume(datavolumev1beta1.Failed,
+			newCondition(datavolumev1beta1.DataVolumeRunning, v1.ConditionFalse, "Error", "Unable to connect to http data source"),
+			newCondition(datavolumev1beta1.DataVolumeReady, v1.ConditionFalse, "Error", "Unable to connect to http data source"),
+			newCondition(datavolumev1beta1.DataVolumeBound, v1.ConditionUnknown, "Pending", ""),
+		), "Unable to connect to http data source; Pending"),
+	)
+})
@@ -0,0 +1,126 @@
+package dvcreator
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/datavolume"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/dvwaiter"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/importer"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/pvc"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/events"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	datavolumeclientv1beta1 "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/typed/core/v1beta1"
+)
+
+type DataVolumeCreator struct {
+	targetNamespace    string
+	cliOptions         *parse.CLIOptions
+	dataVolumeProvider datavolume.DataVolumeProvider
+	pvcProvider        pvc.PersistentVolumeClaimProvider
+	dataVolumeWaiter   *dvwaiter.DataVolumeWaiter
+	eventRecorder      events.Recorder
+}
+
+func NewDataVolumeCreator(cliOptions *parse.CLIOptions) (*DataVolumeCreator, error) {
+	log.Logger().Debug("initialized clients and providers")
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	cdiClient := datavolumeclientv1beta1.NewForConfigOrDie(config)
+
+	dataVolumeProvider := datavolume.NewDataVolumeProvider(cdiClient)
+	pvcProvider := pvc.NewPersistentVolumeClaimProvider(kubeClient.CoreV1())
+	importerPodProvider := importer.NewImporterPodProvider(kubeClient.CoreV1())
+
+	return &DataVolumeCreator{
+		targetNamespace:    cliOptions.GetDataVolumeNamespace(),
+		cliOptions:         cliOptions,
+		dataVolumeProvider: dataVolumeProvider,
+		pvcProvider:        pvcProvider,
+		dataVolumeWaiter:   dvwaiter.NewDataVolumeWaiter(cliOptions, dataVolumeProvider, importerPodProvider),
+		eventRecorder:      events.NewRecorder(kubeClient.CoreV1()),
+	}, nil
+}
+
+func (d *DataVolumeCreator) CreateDataVolume() (*datavolumev1beta1.DataVolume, error) {
+	var dv *datavolumev1beta1.DataVolume
+	var err error
+
+	if d.cliOptions.GetCreationMode() == constants.SnapshotCreationMode {
+		dv, err = d.createDataVolumeFromSnapshot()
+	} else {
+		dv, err = d.createDataVolume()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.eventRecorder.Eventf(datavolumev1beta1.SchemeGroupVersion.WithKind("DataVolume"), dv, events.CreatedByTektonTask, "DataVolume was created by a Tekton task")
+	return dv, nil
+}
+
+func (d *DataVolumeCreator) WaitForSuccess(dv *datavolumev1beta1.DataVolume) error {
+	return d.dataVolumeWaiter.WaitForSuccess(dv)
+}
+
+func (d *DataVolumeCreator) createDataVolume() (*datavolumev1beta1.DataVolume, error) {
+	var dv *datavolumev1beta1.DataVolume
+	var err error
+
+	switch d.cliOptions.GetCreationMode() {
+	case constants.DataVolumeManifestCreationMode:
+		dv, err = datavolume.DecodeDataVolume(d.cliOptions.GetDataVolumeManifest())
+	case constants.SourceCreationMode:
+		dv, err = d.dataVolumeFromSource()
+	default:
+		return nil, zerrors.NewMissingRequiredError("unknown creation mode: %v", d.cliOptions.GetCreationMode())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	dv.Namespace = d.targetNamespace
+
+	log.Logger().Debug("creating DataVolume", zap.Reflect("dv", dv))
+	return d.dataVolumeProvider.Create(d.targetNamespace, dv)
+}
+
+func (d *DataVolumeCreator) createDataVolumeFromSnapshot() (*datavolumev1beta1.DataVolume, error) {
+	snapshotNamespace, snapshotName := d.cliOptions.GetSourceSnapshot()
+	dv := datavolume.NewSnapshotDataVolume(d.cliOptions.GetDataVolumeName(), d.targetNamespace,
+		&datavolume.DataVolumeSourceSnapshot{Namespace: snapshotNamespace, Name: snapshotName},
+		pvc.NewPVCSpec(*d.cliOptions.GetSize(), d.cliOptions.GetStorageClass()))
+
+	log.Logger().Debug("creating DataVolume", zap.Reflect("dv", dv))
+	return d.dataVolumeProvider.CreateFromSnapshot(d.targetNamespace, dv)
+}
+
+func (d *DataVolumeCreator) dataVolumeFromSource() (*datavolumev1beta1.DataVolume, error) {
+	size := d.cliOptions.GetSize()
+
+	if size == nil {
+		// size can be omitted only when cloning
+		sourceNamespace, sourceName := d.cliOptions.GetSourcePVC()
+		sourcePVC, err := d.pvcProvider.Get(sourceNamespace, sourceName)
+		if err != nil {
+			return nil, err
+		}
+		if size = pvc.GetRequestedSize(sourcePVC); size == nil {
+			return nil, zerrors.NewMissingRequiredError("could not detect size of %v/%v PersistentVolumeClaim: size option is required", sourceNamespace, sourceName)
+		}
+	}
+
+	return datavolume.NewDataVolume(d.cliOptions.GetDataVolumeName(), d.targetNamespace, d.cliOptions.GetDataVolumeSource(),
+		pvc.NewPVCSpec(*size, d.cliOptions.GetStorageClass())), nil
+}
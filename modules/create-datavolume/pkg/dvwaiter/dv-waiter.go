@@ -0,0 +1,184 @@
+package dvwaiter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/datavolume"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/importer"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/cache"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+type DataVolumeWaiter struct {
+	cliOptions          *parse.CLIOptions
+	dataVolumeProvider  datavolume.DataVolumeProvider
+	importerPodProvider importer.ImporterPodProvider
+}
+
+// waitState holds the last observed state of the DataVolume, it is shared between the informer and the waiting goroutine
+type waitState struct {
+	lock         sync.Mutex
+	lastSeen     *datavolumev1beta1.DataVolume
+	restartCount int32
+}
+
+func NewDataVolumeWaiter(cliOptions *parse.CLIOptions, dataVolumeProvider datavolume.DataVolumeProvider, importerPodProvider importer.ImporterPodProvider) *DataVolumeWaiter {
+	return &DataVolumeWaiter{
+		cliOptions:          cliOptions,
+		dataVolumeProvider:  dataVolumeProvider,
+		importerPodProvider: importerPodProvider,
+	}
+}
+
+func (w *DataVolumeWaiter) WaitForSuccess(dv *datavolumev1beta1.DataVolume) error {
+	state := &waitState{lastSeen: dv, restartCount: dv.Status.RestartCount}
+
+	stop := make(chan struct{})
+	result := make(chan error, 1)
+	var once sync.Once
+
+	finish := func(err error) {
+		once.Do(func() {
+			result <- err
+			close(stop)
+		})
+	}
+
+	eventHandler := func(obj interface{}) {
+		if current, ok := obj.(*datavolumev1beta1.DataVolume); ok {
+			if done, err := w.evaluate(state, current); done {
+				finish(err)
+			}
+		}
+	}
+
+	_, controller := cache.NewInformer(w.dataVolumeProvider.NewListWatch(dv.Namespace, dv.Name), &datavolumev1beta1.DataVolume{}, time.Second*0, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			log.Logger().Debug("dv added", zap.Reflect("dv", obj))
+			eventHandler(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			log.Logger().Debug("dv deleted", zap.Reflect("dv", obj))
+			finish(exit.Exit{
+				Code: constants.DataVolumeFailedExitCode,
+				Msg:  fmt.Sprintf("%v DataVolume was deleted before it succeeded", dv.Name),
+				Soft: true,
+			})
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			log.Logger().Debug("dv changed", zap.Reflect("dv", newObj))
+			eventHandler(newObj)
+		},
+	})
+
+	go controller.Run(stop)
+
+	timer := time.NewTimer(w.cliOptions.GetTimeout())
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		finish(w.getTimeoutError(state))
+		return <-result
+	}
+}
+
+// evaluate returns true when the waiting should end, together with the resulting error
+func (w *DataVolumeWaiter) evaluate(state *waitState, current *datavolumev1beta1.DataVolume) (bool, error) {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	previous := state.lastSeen
+	state.lastSeen = current
+
+	if previous.Status.Phase != current.Status.Phase {
+		log.Logger().Info("DataVolume phase changed", zap.String("name", current.Name),
+			zap.String("from", string(previous.Status.Phase)), zap.String("to", string(current.Status.Phase)))
+	}
+
+	if current.Status.Progress != "" && previous.Status.Progress != current.Status.Progress {
+		log.Logger().Info("DataVolume progress", zap.String("name", current.Name), zap.String("progress", string(current.Status.Progress)))
+	}
+
+	if datavolume.IsFailed(current) {
+		msg := fmt.Sprintf("%v DataVolume failed", current.Name)
+		if previous.Status.Phase != "" && previous.Status.Phase != current.Status.Phase {
+			msg = fmt.Sprintf("%v DataVolume transitioned from %v to %v phase", current.Name, previous.Status.Phase, current.Status.Phase)
+		}
+		return true, exit.Exit{
+			Code: constants.DataVolumeFailedExitCode,
+			Msg:  fmt.Sprintf("%v: %v", msg, datavolume.GetFailureMessage(current)),
+			Soft: true,
+		}
+	}
+
+	if current.Status.RestartCount > state.restartCount {
+		state.restartCount = current.Status.RestartCount
+		if err := w.checkImporterPodRestarts(current); err != nil {
+			return true, err
+		}
+	}
+
+	return datavolume.IsSucceeded(current), nil
+}
+
+func (w *DataVolumeWaiter) checkImporterPodRestarts(dv *datavolumev1beta1.DataVolume) error {
+	restartCount := dv.Status.RestartCount
+	terminationMessage := ""
+
+	if pod, err := w.importerPodProvider.GetImporterPod(dv.Namespace, dv.Name); err == nil {
+		if podRestartCount := importer.GetRestartCount(pod); podRestartCount > restartCount {
+			restartCount = podRestartCount
+		}
+		terminationMessage = importer.GetLastTerminationMessage(pod)
+	} else {
+		log.Logger().Debug("could not get importer pod", zap.String("dv", dv.Name), zap.Error(err))
+	}
+
+	if terminationMessage == "" {
+		terminationMessage = datavolume.GetFailureMessage(dv)
+	}
+
+	log.Logger().Warn("importer pod restarted", zap.String("name", dv.Name),
+		zap.Int32("restartCount", restartCount), zap.String("lastTerminationMessage", terminationMessage))
+
+	if maxRestarts := w.cliOptions.GetImporterPodMaxRestarts(); restartCount > maxRestarts {
+		return exit.Exit{
+			Code: constants.ImporterPodRestartsExitCode,
+			Msg:  fmt.Sprintf("importer pod of %v DataVolume restarted %v times which exceeds the limit of %v restarts: %v", dv.Name, restartCount, maxRestarts, terminationMessage),
+			Soft: true,
+		}
+	}
+
+	return nil
+}
+
+func (w *DataVolumeWaiter) getTimeoutError(state *waitState) error {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	lastSeen := state.lastSeen
+
+	if !datavolume.IsBound(lastSeen) {
+		return exit.Exit{
+			Code: constants.PVCNotBoundExitCode,
+			Msg:  fmt.Sprintf("timed out waiting for PersistentVolumeClaim of %v DataVolume to be bound: %v", lastSeen.Name, datavolume.GetFailureMessage(lastSeen)),
+			Soft: true,
+		}
+	}
+
+	return exit.Exit{
+		Code: constants.WaitForSuccessTimeoutExitCode,
+		Msg:  fmt.Sprintf("timed out waiting for %v DataVolume to succeed in %v phase: %v", lastSeen.Name, lastSeen.Status.Phase, datavolume.GetFailureMessage(lastSeen)),
+		Soft: true,
+	}
+}
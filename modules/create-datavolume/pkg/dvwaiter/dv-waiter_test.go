@@ -0,0 +1,183 @@
+package dvwaiter_test
+
+import (
+	"context"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/datavolume"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/dvwaiter"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/importer"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	cdifake "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
+)
+
+const (
+	testNamespace = "test-ns"
+	testName      = "test-dv"
+)
+
+func newDataVolume(phase datavolumev1beta1.DataVolumePhase, restartCount int32, conditions ...datavolumev1beta1.DataVolumeCondition) *datavolumev1beta1.DataVolume {
+	return &datavolumev1beta1.DataVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Status: datavolumev1beta1.DataVolumeStatus{
+			Phase:        phase,
+			Progress:     "N/A",
+			RestartCount: restartCount,
+			Conditions:   conditions,
+		},
+	}
+}
+
+func newBoundCondition() datavolumev1beta1.DataVolumeCondition {
+	return datavolumev1beta1.DataVolumeCondition{
+		Type:   datavolumev1beta1.DataVolumeBound,
+		Status: v1.ConditionTrue,
+		Reason: "Bound",
+	}
+}
+
+func expectExitCode(err error, code int, msgSubstring string) {
+	Expect(err).Should(HaveOccurred())
+	Expect(err).To(BeAssignableToTypeOf(exit.Exit{}))
+	Expect(err.(exit.Exit).Code).To(Equal(code))
+	Expect(err.Error()).To(ContainSubstring(msgSubstring))
+}
+
+var _ = Describe("DataVolumeWaiter", func() {
+	var cdiClient *cdifake.Clientset
+	var kubeClient *kubefake.Clientset
+	var cliOptions *parse.CLIOptions
+
+	BeforeEach(func() {
+		cdiClient = cdifake.NewSimpleClientset()
+		kubeClient = kubefake.NewSimpleClientset()
+		cliOptions = &parse.CLIOptions{Timeout: "10s"}
+	})
+
+	waitAsync := func(dv *datavolumev1beta1.DataVolume) chan error {
+		created, err := cdiClient.CdiV1beta1().DataVolumes(testNamespace).Create(context.TODO(), dv, metav1.CreateOptions{})
+		Expect(err).Should(Succeed())
+
+		waiter := dvwaiter.NewDataVolumeWaiter(cliOptions,
+			datavolume.NewDataVolumeProvider(cdiClient.CdiV1beta1()),
+			importer.NewImporterPodProvider(kubeClient.CoreV1()),
+		)
+
+		result := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			result <- waiter.WaitForSuccess(created)
+		}()
+		return result
+	}
+
+	update := func(dv *datavolumev1beta1.DataVolume) {
+		_, err := cdiClient.CdiV1beta1().DataVolumes(testNamespace).Update(context.TODO(), dv, metav1.UpdateOptions{})
+		Expect(err).Should(Succeed())
+	}
+
+	It("succeeds when DataVolume already succeeded", func() {
+		result := waitAsync(newDataVolume(datavolumev1beta1.Succeeded, 0, newBoundCondition()))
+		Eventually(result).Should(Receive(BeNil()))
+	})
+
+	It("succeeds after import progress", func() {
+		result := waitAsync(newDataVolume(datavolumev1beta1.ImportScheduled, 0, newBoundCondition()))
+
+		inProgress := newDataVolume(datavolumev1beta1.ImportInProgress, 0, newBoundCondition())
+		inProgress.Status.Progress = "45.00%"
+		update(inProgress)
+		Consistently(result).ShouldNot(Receive())
+
+		update(newDataVolume(datavolumev1beta1.Succeeded, 0, newBoundCondition()))
+		Eventually(result).Should(Receive(BeNil()))
+	})
+
+	It("fails when DataVolume fails", func() {
+		result := waitAsync(newDataVolume(datavolumev1beta1.ImportScheduled, 0, newBoundCondition()))
+
+		update(newDataVolume(datavolumev1beta1.Failed, 0, newBoundCondition(), datavolumev1beta1.DataVolumeCondition{
+			Type:    datavolumev1beta1.DataVolumeRunning,
+			Status:  v1.ConditionFalse,
+			Reason:  "Error",
+			Message: "Unable to connect to http data source",
+		}))
+
+		var err error
+		Eventually(result).Should(Receive(&err))
+		expectExitCode(err, constants.DataVolumeFailedExitCode, "test-dv DataVolume transitioned from ImportScheduled to Failed phase: Unable to connect to http data source")
+	})
+
+	It("fails when importer pod restarts too many times", func() {
+		_, err := kubeClient.CoreV1().PersistentVolumeClaims(testNamespace).Create(context.TODO(), &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace},
+		}, metav1.CreateOptions{})
+		Expect(err).Should(Succeed())
+		pod, err := kubeClient.CoreV1().Pods(testNamespace).Create(context.TODO(), &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "importer-" + testName, Namespace: testNamespace},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{
+					RestartCount: 1,
+					LastTerminationState: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{ExitCode: 1, Message: "Unable to process data: unexpected EOF"},
+					},
+				}},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).Should(Succeed())
+
+		cliOptions.ImporterPodMaxRestarts = "1"
+		result := waitAsync(newDataVolume(datavolumev1beta1.ImportInProgress, 0, newBoundCondition()))
+
+		update(newDataVolume(datavolumev1beta1.ImportInProgress, 1, newBoundCondition()))
+		Consistently(result).ShouldNot(Receive())
+
+		pod.Status.ContainerStatuses[0].RestartCount = 2
+		_, err = kubeClient.CoreV1().Pods(testNamespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
+		Expect(err).Should(Succeed())
+		update(newDataVolume(datavolumev1beta1.ImportInProgress, 2, newBoundCondition()))
+
+		Eventually(result).Should(Receive(&err))
+		expectExitCode(err, constants.ImporterPodRestartsExitCode, "importer pod of test-dv DataVolume restarted 2 times which exceeds the limit of 1 restarts: Unable to process data: unexpected EOF")
+	})
+
+	It("fails when DataVolume is deleted", func() {
+		result := waitAsync(newDataVolume(datavolumev1beta1.ImportScheduled, 0, newBoundCondition()))
+
+		Consistently(result).ShouldNot(Receive())
+
+		Expect(cdiClient.CdiV1beta1().DataVolumes(testNamespace).Delete(context.TODO(), testName, metav1.DeleteOptions{})).Should(Succeed())
+
+		var err error
+		Eventually(result).Should(Receive(&err))
+		expectExitCode(err, constants.DataVolumeFailedExitCode, "test-dv DataVolume was deleted before it succeeded")
+	})
+
+	It("times out when PVC is not bound", func() {
+		cliOptions.Timeout = "1s"
+		result := waitAsync(newDataVolume(datavolumev1beta1.Pending, 0))
+
+		var err error
+		Eventually(result, "3s").Should(Receive(&err))
+		expectExitCode(err, constants.PVCNotBoundExitCode, "timed out waiting for PersistentVolumeClaim of test-dv DataVolume to be bound")
+	})
+
+	It("times out when DataVolume does not succeed", func() {
+		cliOptions.Timeout = "1s"
+		result := waitAsync(newDataVolume(datavolumev1beta1.ImportInProgress, 0, newBoundCondition()))
+
+		var err error
+		Eventually(result, "3s").Should(Receive(&err))
+		expectExitCode(err, constants.WaitForSuccessTimeoutExitCode, "timed out waiting for test-dv DataVolume to succeed in ImportInProgress phase")
+	})
+})
@@ -0,0 +1,16 @@
+package dvwaiter_test
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utilstest"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDvwaiter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dvwaiter Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
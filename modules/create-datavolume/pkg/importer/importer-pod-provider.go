@@ -0,0 +1,32 @@
+package importer
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type importerPodProvider struct {
+	client clientv1.CoreV1Interface
+}
+
+type ImporterPodProvider interface {
+	GetImporterPod(namespace, pvcName string) (*v1.Pod, error)
+}
+
+func NewImporterPodProvider(client clientv1.CoreV1Interface) ImporterPodProvider {
+	return &importerPodProvider{
+		client: client,
+	}
+}
+
+func (i *importerPodProvider) GetImporterPod(namespace, pvcName string) (*v1.Pod, error) {
+	pvc, err := i.client.PersistentVolumeClaims(namespace).Get(context.TODO(), pvcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return i.client.Pods(namespace).Get(context.TODO(), GetImporterPodName(pvc), metav1.GetOptions{})
+}
@@ -0,0 +1,43 @@
+package importer
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	importPodNameAnnotation = "cdi.kubevirt.io/storage.import.importPodName"
+	importerPodNamePrefix   = "importer-"
+)
+
+// GetImporterPodName resolves the name of the pod CDI uses to populate the PVC
+func GetImporterPodName(pvc *v1.PersistentVolumeClaim) string {
+	if podName, ok := pvc.Annotations[importPodNameAnnotation]; ok && podName != "" {
+		return podName
+	}
+	return importerPodNamePrefix + pvc.Name
+}
+
+func GetRestartCount(pod *v1.Pod) int32 {
+	var restartCount int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restartCount += status.RestartCount
+	}
+	return restartCount
+}
+
+// GetLastTerminationMessage returns the message of the last terminated container or an empty string
+func GetLastTerminationMessage(pod *v1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			if message := strings.TrimSpace(terminated.Message); message != "" {
+				return message
+			}
+			if terminated.Reason != "" {
+				return terminated.Reason
+			}
+		}
+	}
+	return ""
+}
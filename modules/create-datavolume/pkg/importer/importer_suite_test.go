@@ -0,0 +1,16 @@
+package importer_test
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utilstest"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestImporter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Importer Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
@@ -0,0 +1,83 @@
+package importer_test
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/importer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testNamespace = "test-ns"
+
+func newPVC(name string, annotations map[string]string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   testNamespace,
+			Annotations: annotations,
+		},
+	}
+}
+
+func newPod(name string, statuses ...v1.ContainerStatus) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Status: v1.PodStatus{
+			ContainerStatuses: statuses,
+		},
+	}
+}
+
+func newTerminatedStatus(restartCount int32, reason, message string) v1.ContainerStatus {
+	return v1.ContainerStatus{
+		RestartCount: restartCount,
+		LastTerminationState: v1.ContainerState{
+			Terminated: &v1.ContainerStateTerminated{
+				ExitCode: 1,
+				Reason:   reason,
+				Message:  message,
+			},
+		},
+	}
+}
+
+var _ = Describe("Importer", func() {
+	It("resolves importer pod name", func() {
+		Expect(importer.GetImporterPodName(newPVC("disk", nil))).To(Equal("importer-disk"))
+		Expect(importer.GetImporterPodName(newPVC("disk", map[string]string{
+			"cdi.kubevirt.io/storage.import.importPodName": "importer-disk-custom",
+		}))).To(Equal("importer-disk-custom"))
+	})
+
+	It("gets restart count", func() {
+		Expect(importer.GetRestartCount(newPod("importer-disk"))).To(Equal(int32(0)))
+		Expect(importer.GetRestartCount(newPod("importer-disk",
+			newTerminatedStatus(2, "Error", ""),
+			newTerminatedStatus(1, "Error", ""),
+		))).To(Equal(int32(3)))
+	})
+
+	It("gets last termination message", func() {
+		Expect(importer.GetLastTerminationMessage(newPod("importer-disk", v1.ContainerStatus{}))).To(BeEmpty())
+		Expect(importer.GetLastTerminationMessage(newPod("importer-disk", newTerminatedStatus(1, "Error", "")))).To(Equal("Error"))
+		Expect(importer.GetLastTerminationMessage(newPod("importer-disk", newTerminatedStatus(1, "Error", " Unable to connect to http data source\n")))).To(Equal("Unable to connect to http data source"))
+	})
+
+	It("gets importer pod of a PVC", func() {
+		client := fake.NewSimpleClientset(
+			newPVC("disk", map[string]string{"cdi.kubevirt.io/storage.import.importPodName": "importer-disk-custom"}),
+			newPod("importer-disk-custom", newTerminatedStatus(1, "Error", "")),
+		)
+		pod, err := importer.NewImporterPodProvider(client.CoreV1()).GetImporterPod(testNamespace, "disk")
+		Expect(err).Should(Succeed())
+		Expect(pod.Name).To(Equal("importer-disk-custom"))
+
+		_, err = importer.NewImporterPodProvider(client.CoreV1()).GetImporterPod(testNamespace, "missing")
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,27 @@
+package pvc
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type pvcProvider struct {
+	client clientv1.CoreV1Interface
+}
+
+type PersistentVolumeClaimProvider interface {
+	Get(namespace, name string) (*v1.PersistentVolumeClaim, error)
+}
+
+func NewPersistentVolumeClaimProvider(client clientv1.CoreV1Interface) PersistentVolumeClaimProvider {
+	return &pvcProvider{
+		client: client,
+	}
+}
+
+func (p *pvcProvider) Get(namespace, name string) (*v1.PersistentVolumeClaim, error) {
+	return p.client.PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
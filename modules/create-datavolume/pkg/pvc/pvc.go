@@ -0,0 +1,31 @@
+package pvc
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func NewPVCSpec(size resource.Quantity, storageClass string) *v1.PersistentVolumeClaimSpec {
+	spec := &v1.PersistentVolumeClaimSpec{
+		AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceStorage: size,
+			},
+		},
+	}
+
+	if storageClass != "" {
+		spec.StorageClassName = &storageClass
+	}
+
+	return spec
+}
+
+// GetRequestedSize returns nil if the PersistentVolumeClaim does not request any storage
+func GetRequestedSize(pvc *v1.PersistentVolumeClaim) *resource.Quantity {
+	if size, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+		return &size
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package pvc_test
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utilstest"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPvc(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Pvc Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
@@ -0,0 +1,25 @@
+package pvc_test
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/pvc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("PVC", func() {
+	It("creates PVC spec", func() {
+		spec := pvc.NewPVCSpec(resource.MustParse("1Gi"), "")
+		Expect(spec.AccessModes).To(ConsistOf(v1.ReadWriteOnce))
+		Expect(spec.Resources.Requests).To(HaveKeyWithValue(v1.ResourceStorage, resource.MustParse("1Gi")))
+		Expect(spec.StorageClassName).To(BeNil())
+
+		spec = pvc.NewPVCSpec(resource.MustParse("1Gi"), "fast")
+		Expect(*spec.StorageClassName).To(Equal("fast"))
+	})
+
+	It("gets requested size", func() {
+		Expect(pvc.GetRequestedSize(&v1.PersistentVolumeClaim{})).To(BeNil())
+	})
+})
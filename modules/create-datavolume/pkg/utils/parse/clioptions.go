@@ -0,0 +1,189 @@
+package parse
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/resource"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	dvManifestOptionName             = "dv-manifest"
+	dvNameOptionName                 = "dv-name"
+	dvNamespaceOptionName            = "dv-namespace"
+	sourcePVCOptionName              = "source-pvc"
+	sourceSnapshotOptionName         = "source-snapshot"
+	sourceRegistryURLOptionName      = "source-registry-url"
+	sourceHTTPURLOptionName          = "source-http-url"
+	sourceUploadOptionName           = "source-upload"
+	sizeOptionName                   = "size"
+	storageClassOptionName           = "storage-class"
+	waitForSuccessOptionName         = "wait-for-success"
+	timeoutOptionName                = "timeout"
+	importerPodMaxRestartsOptionName = "importer-pod-max-restarts"
+)
+
+const namespaceSeparator = "/"
+
+type CLIOptions struct {
+	DataVolumeManifest     string            `arg:"--dv-manifest,env:DV_MANIFEST" placeholder:"MANIFEST" help:"YAML manifest of a DataVolume resource to be created (can be set by DV_MANIFEST env variable)."`
+	DataVolumeName         string            `arg:"--dv-name,env:DV_NAME" placeholder:"NAME" help:"Name of the DataVolume to be created from one of the source options."`
+	DataVolumeNamespace    string            `arg:"--dv-namespace,env:DV_NAMESPACE" placeholder:"NAMESPACE" help:"Namespace where to create the DataVolume (defaults to manifest namespace or active namespace)"`
+	SourcePVC              string            `arg:"--source-pvc,env:SOURCE_PVC" placeholder:"[NAMESPACE/]NAME" help:"Clone the DataVolume from a PersistentVolumeClaim. Namespace defaults to dv-namespace."`
+	SourceSnapshot         string            `arg:"--source-snapshot,env:SOURCE_SNAPSHOT" placeholder:"[NAMESPACE/]NAME" help:"Restore the DataVolume from a VolumeSnapshot. Namespace defaults to dv-namespace."`
+	SourceRegistryURL      string            `arg:"--source-registry-url,env:SOURCE_REGISTRY_URL" placeholder:"URL" help:"Import the DataVolume from a container image (docker://registry/image:tag)."`
+	SourceHTTPURL          string            `arg:"--source-http-url,env:SOURCE_HTTP_URL" placeholder:"URL" help:"Import the DataVolume from a http or https URL."`
+	SourceUpload           string            `arg:"--source-upload,env:SOURCE_UPLOAD" placeholder:"true|false" help:"Create a DataVolume which waits for an upload through the CDI upload proxy."`
+	Size                   string            `arg:"--size,env:SIZE" placeholder:"QUANTITY" help:"Requested storage size (e.g. 10Gi). Defaults to the size of source-pvc when cloning."`
+	StorageClass           string            `arg:"--storage-class,env:STORAGE_CLASS" placeholder:"NAME" help:"Storage class of the created PersistentVolumeClaim. Defaults to the cluster default storage class."`
+	WaitForSuccess         string            `arg:"--wait-for-success,env:WAIT_FOR_SUCCESS" placeholder:"true|false" help:"Wait until the DataVolume is ready to be consumed"`
+	Timeout                string            `arg:"--timeout,env:TIMEOUT" placeholder:"DURATION" help:"Timeout for waiting for the DataVolume to succeed. Should be in a 3h2m1s format. Defaults to 720h."`
+	ImporterPodMaxRestarts string            `arg:"--importer-pod-max-restarts,env:IMPORTER_POD_MAX_RESTARTS" placeholder:"COUNT" help:"Fail when the importer pod of the DataVolume restarts more times than COUNT while waiting for success. Defaults to 3."`
+	Output                 output.OutputType `arg:"-o" placeholder:"FORMAT" help:"Output format. One of: yaml|json"`
+	Debug                  bool              `arg:"--debug" help:"Sets DEBUG log level"`
+	LogFormat              string            `arg:"--log-format,env:LOG_FORMAT" placeholder:"json|console" help:"Log format. One of: json|console. Defaults to json."`
+}
+
+func (c *CLIOptions) GetDebugLevel() zapcore.Level {
+	if c.Debug {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+func (c *CLIOptions) GetLogFormat() log.LogFormat {
+	return log.LogFormat(c.LogFormat)
+}
+
+func (c *CLIOptions) GetCreationMode() constants.CreationMode {
+	if len(c.getSpecifiedSourceOptionNames()) != 1 {
+		return ""
+	}
+
+	if c.DataVolumeManifest != "" {
+		return constants.DataVolumeManifestCreationMode
+	}
+
+	if c.SourceSnapshot != "" {
+		return constants.SnapshotCreationMode
+	}
+
+	return constants.SourceCreationMode
+}
+
+func (c *CLIOptions) GetDataVolumeManifest() string {
+	return c.DataVolumeManifest
+}
+
+func (c *CLIOptions) GetDataVolumeName() string {
+	return c.DataVolumeName
+}
+
+func (c *CLIOptions) GetDataVolumeNamespace() string {
+	return c.DataVolumeNamespace
+}
+
+// GetSourcePVC returns namespace and name of the source PersistentVolumeClaim
+func (c *CLIOptions) GetSourcePVC() (string, string) {
+	return c.splitNamespacedName(c.SourcePVC)
+}
+
+// GetSourceSnapshot returns namespace and name of the source VolumeSnapshot
+func (c *CLIOptions) GetSourceSnapshot() (string, string) {
+	return c.splitNamespacedName(c.SourceSnapshot)
+}
+
+func (c *CLIOptions) GetSourceRegistryURL() string {
+	return c.SourceRegistryURL
+}
+
+func (c *CLIOptions) GetSourceHTTPURL() string {
+	return c.SourceHTTPURL
+}
+
+func (c *CLIOptions) GetSourceUpload() bool {
+	return zutils.IsTrue(c.SourceUpload)
+}
+
+// GetDataVolumeSource returns nil if the DataVolume should not be built from a source
+func (c *CLIOptions) GetDataVolumeSource() *datavolumev1beta1.DataVolumeSource {
+	switch {
+	case c.SourcePVC != "":
+		namespace, name := c.GetSourcePVC()
+		return &datavolumev1beta1.DataVolumeSource{PVC: &datavolumev1beta1.DataVolumeSourcePVC{Namespace: namespace, Name: name}}
+	case c.SourceRegistryURL != "":
+		return &datavolumev1beta1.DataVolumeSource{Registry: &datavolumev1beta1.DataVolumeSourceRegistry{URL: c.GetSourceRegistryURL()}}
+	case c.SourceHTTPURL != "":
+		return &datavolumev1beta1.DataVolumeSource{HTTP: &datavolumev1beta1.DataVolumeSourceHTTP{URL: c.GetSourceHTTPURL()}}
+	case c.GetSourceUpload():
+		return &datavolumev1beta1.DataVolumeSource{Upload: &datavolumev1beta1.DataVolumeSourceUpload{}}
+	}
+	return nil
+}
+
+// GetSize returns nil if the size was not specified
+func (c *CLIOptions) GetSize() *resource.Quantity {
+	if c.Size != "" {
+		size, err := resource.ParseQuantity(c.Size)
+		if err == nil {
+			return &size
+		}
+	}
+	return nil
+}
+
+func (c *CLIOptions) GetStorageClass() string {
+	return c.StorageClass
+}
+
+func (c *CLIOptions) GetWaitForSuccess() bool {
+	return zutils.IsTrue(c.WaitForSuccess)
+}
+
+func (c *CLIOptions) GetTimeout() time.Duration {
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err == nil {
+			return timeout
+		}
+	}
+	return constants.DefaultWaitForSuccessTimeout
+}
+
+func (c *CLIOptions) GetImporterPodMaxRestarts() int32 {
+	if c.ImporterPodMaxRestarts != "" {
+		maxRestarts, err := strconv.ParseInt(c.ImporterPodMaxRestarts, 10, 32)
+		if err == nil {
+			return int32(maxRestarts)
+		}
+	}
+	return constants.DefaultImporterPodMaxRestarts
+}
+
+func (c *CLIOptions) Init() error {
+	c.trimSpaces()
+
+	if err := c.assertValidTypes(); err != nil {
+		return err
+	}
+
+	if err := c.assertValidMode(); err != nil {
+		return err
+	}
+
+	if err := c.resolveNamespaceAndManifest(); err != nil {
+		return err
+	}
+
+	if err := c.assertValidSource(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package parse_test
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/resource"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+const (
+	defaultNS      = "default"
+	testDVManifest = `apiVersion: cdi.kubevirt.io/v1beta1
+kind: DataVolume
+metadata:
+  name: test-dv
+spec:
+  source:
+    blank: {}
+  pvc:
+    accessModes:
+      - ReadWriteOnce
+    resources:
+      requests:
+        storage: 100Mi
+`
+	testDVManifestWithNamespace = `apiVersion: cdi.kubevirt.io/v1beta1
+kind: DataVolume
+metadata:
+  name: test-dv
+  namespace: dv-ns
+`
+)
+
+const (
+	testHTTPURL     = "https://example.com/disk.qcow2"
+	testRegistryURL = "docker://quay.io/disk"
+)
+
+var _ = Describe("CLIOptions", func() {
+	table.DescribeTable("Init return correct assertion errors", func(expectedErrMessage string, options *parse.CLIOptions) {
+		err := options.Init()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expectedErrMessage))
+	},
+		table.Entry("no source", "one of dv-manifest, source-pvc, source-snapshot, source-registry-url, source-http-url, source-upload should be specified", &parse.CLIOptions{}),
+		table.Entry("blank manifest", "one of dv-manifest, source-pvc, source-snapshot, source-registry-url, source-http-url, source-upload should be specified", &parse.CLIOptions{
+			DataVolumeManifest: "  ",
+		}),
+		table.Entry("disabled upload", "one of dv-manifest, source-pvc, source-snapshot, source-registry-url, source-http-url, source-upload should be specified", &parse.CLIOptions{
+			DataVolumeName: "test",
+			SourceUpload:   "false",
+		}),
+		table.Entry("manifest and source", "only one of dv-manifest, source-http-url should be specified", &parse.CLIOptions{
+			DataVolumeManifest: testDVManifest,
+			SourceHTTPURL:      testHTTPURL,
+		}),
+		table.Entry("multiple sources", "only one of source-pvc, source-snapshot, source-registry-url, source-upload should be specified", &parse.CLIOptions{
+			DataVolumeName:    "test",
+			SourcePVC:         "disk",
+			SourceSnapshot:    "snapshot",
+			SourceRegistryURL: testRegistryURL,
+			SourceUpload:      "true",
+		}),
+		table.Entry("useless source options for manifest", "dv-name, size, storage-class options are not applicable for dv-manifest", &parse.CLIOptions{
+			DataVolumeManifest: testDVManifest,
+			Size:               "1Gi",
+		}),
+		table.Entry("missing name", "dv-name option is required", &parse.CLIOptions{
+			SourceHTTPURL: testHTTPURL,
+			Size:          "1Gi",
+		}),
+		table.Entry("missing size", "size option is required", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceHTTPURL:       testHTTPURL,
+		}),
+		table.Entry("missing snapshot size", "size option is required for source-snapshot", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceSnapshot:      "snapshot",
+		}),
+		table.Entry("invalid size", "could not parse size", &parse.CLIOptions{
+			DataVolumeName: "test",
+			SourceHTTPURL:  testHTTPURL,
+			Size:           "ten gigs",
+		}),
+		table.Entry("zero size", "size should be a positive quantity", &parse.CLIOptions{
+			DataVolumeName: "test",
+			SourceHTTPURL:  testHTTPURL,
+			Size:           "0",
+		}),
+		table.Entry("invalid upload", "invalid option source-upload yes, only true|false is allowed", &parse.CLIOptions{
+			DataVolumeName: "test",
+			SourceUpload:   "yes",
+		}),
+		table.Entry("invalid source pvc", "invalid source-pvc ns/: should be in [NAMESPACE/]NAME format", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourcePVC:           "ns/",
+		}),
+		table.Entry("invalid source snapshot", "invalid source-snapshot /snapshot: should be in [NAMESPACE/]NAME format", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceSnapshot:      "/snapshot",
+			Size:                "1Gi",
+		}),
+		table.Entry("invalid http url", "invalid source-http-url docker://quay.io/disk: only http|https schemes are supported", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceHTTPURL:       testRegistryURL,
+			Size:                "1Gi",
+		}),
+		table.Entry("invalid registry url", "invalid source-registry-url https://example.com/disk.qcow2: only docker|oci-archive schemes are supported", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceRegistryURL:   testHTTPURL,
+			Size:                "1Gi",
+		}),
+		table.Entry("invalid manifest", "could not read DataVolume manifest", &parse.CLIOptions{
+			DataVolumeManifest: "blabla",
+		}),
+		table.Entry("wrong kind", "manifest does not contain DataVolume kind", &parse.CLIOptions{
+			DataVolumeManifest: "apiVersion: v1\nkind: PersistentVolumeClaim\n",
+		}),
+		table.Entry("wrong apiVersion", "unsupported DataVolume apiVersion cdi.kubevirt.io/v1alpha1", &parse.CLIOptions{
+			DataVolumeManifest: "apiVersion: cdi.kubevirt.io/v1alpha1\nkind: DataVolume\n",
+		}),
+		table.Entry("invalid output", "not a valid output type", &parse.CLIOptions{
+			DataVolumeManifest:  testDVManifest,
+			DataVolumeNamespace: defaultNS,
+			Output:              "incorrect-fmt",
+		}),
+		table.Entry("invalid log format", "text is not a valid log format", &parse.CLIOptions{
+			DataVolumeManifest:  testDVManifest,
+			DataVolumeNamespace: defaultNS,
+			LogFormat:           "text",
+		}),
+		table.Entry("invalid wait-for-success", "invalid option wait-for-success yes, only true|false is allowed", &parse.CLIOptions{
+			DataVolumeManifest:  testDVManifest,
+			DataVolumeNamespace: defaultNS,
+			WaitForSuccess:      "yes",
+		}),
+		table.Entry("invalid timeout", "could not parse timeout", &parse.CLIOptions{
+			DataVolumeManifest:  testDVManifest,
+			DataVolumeNamespace: defaultNS,
+			Timeout:             "1 hour",
+		}),
+		table.Entry("invalid importer-pod-max-restarts", "importer-pod-max-restarts should be a non-negative integer", &parse.CLIOptions{
+			DataVolumeManifest:     testDVManifest,
+			DataVolumeNamespace:    defaultNS,
+			ImporterPodMaxRestarts: "many",
+		}),
+		table.Entry("negative importer-pod-max-restarts", "importer-pod-max-restarts should be a non-negative integer", &parse.CLIOptions{
+			DataVolumeManifest:     testDVManifest,
+			DataVolumeNamespace:    defaultNS,
+			ImporterPodMaxRestarts: "-1",
+		}),
+	)
+
+	table.DescribeTable("Parses and returns correct values", func(options *parse.CLIOptions, expectedOptions map[string]interface{}) {
+		Expect(options.Init()).Should(Succeed())
+
+		for methodName, expectedValue := range expectedOptions {
+			results := reflect.ValueOf(options).MethodByName(methodName).Call([]reflect.Value{})
+			Expect(results[0].Interface()).To(Equal(expectedValue))
+		}
+	},
+		table.Entry("returns valid defaults", &parse.CLIOptions{
+			DataVolumeManifest:  testDVManifest,
+			DataVolumeNamespace: defaultNS,
+		}, map[string]interface{}{
+			"GetDataVolumeManifest":     testDVManifest,
+			"GetDataVolumeNamespace":    defaultNS,
+			"GetWaitForSuccess":         false,
+			"GetTimeout":                720 * time.Hour,
+			"GetImporterPodMaxRestarts": int32(3),
+			"GetCreationMode":           constants.DataVolumeManifestCreationMode,
+			"GetDataVolumeName":         "",
+			"GetSize":                   (*resource.Quantity)(nil),
+			"GetStorageClass":           "",
+			"GetSourceUpload":           false,
+			"GetDataVolumeSource":       (*datavolumev1beta1.DataVolumeSource)(nil),
+			"GetDebugLevel":             zapcore.InfoLevel,
+		}),
+		table.Entry("uses manifest namespace", &parse.CLIOptions{
+			DataVolumeManifest: testDVManifestWithNamespace,
+		}, map[string]interface{}{
+			"GetDataVolumeNamespace": "dv-ns",
+		}),
+		table.Entry("handles trim", &parse.CLIOptions{
+			DataVolumeManifest:     testDVManifestWithNamespace,
+			DataVolumeNamespace:    " " + defaultNS + " ",
+			WaitForSuccess:         " true ",
+			Timeout:                " 1h30m ",
+			ImporterPodMaxRestarts: " 0 ",
+			Output:                 output.JsonOutput,
+			Debug:                  true,
+		}, map[string]interface{}{
+			"GetDataVolumeNamespace":    defaultNS,
+			"GetWaitForSuccess":         true,
+			"GetTimeout":                90 * time.Minute,
+			"GetImporterPodMaxRestarts": int32(0),
+			"GetDebugLevel":             zapcore.DebugLevel,
+		}),
+		table.Entry("clone mode", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourcePVC:           "source-ns/disk",
+			StorageClass:        "fast",
+		}, map[string]interface{}{
+			"GetCreationMode":   constants.SourceCreationMode,
+			"GetDataVolumeName": "test",
+			"GetSize":           (*resource.Quantity)(nil),
+			"GetStorageClass":   "fast",
+			"GetDataVolumeSource": &datavolumev1beta1.DataVolumeSource{
+				PVC: &datavolumev1beta1.DataVolumeSourcePVC{Namespace: "source-ns", Name: "disk"},
+			},
+		}),
+		table.Entry("clone mode defaults source namespace", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourcePVC:           "disk",
+		}, map[string]interface{}{
+			"GetDataVolumeSource": &datavolumev1beta1.DataVolumeSource{
+				PVC: &datavolumev1beta1.DataVolumeSourcePVC{Namespace: defaultNS, Name: "disk"},
+			},
+		}),
+		table.Entry("snapshot mode", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceSnapshot:      "other-ns/snapshot",
+			Size:                "10Gi",
+		}, map[string]interface{}{
+			"GetCreationMode":     constants.SnapshotCreationMode,
+			"GetDataVolumeSource": (*datavolumev1beta1.DataVolumeSource)(nil),
+		}),
+		table.Entry("registry mode", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceRegistryURL:   testRegistryURL,
+			Size:                "10Gi",
+		}, map[string]interface{}{
+			"GetCreationMode": constants.SourceCreationMode,
+			"GetDataVolumeSource": &datavolumev1beta1.DataVolumeSource{
+				Registry: &datavolumev1beta1.DataVolumeSourceRegistry{URL: testRegistryURL},
+			},
+		}),
+		table.Entry("http mode", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceHTTPURL:       testHTTPURL,
+			Size:                "10Gi",
+		}, map[string]interface{}{
+			"GetCreationMode": constants.SourceCreationMode,
+			"GetDataVolumeSource": &datavolumev1beta1.DataVolumeSource{
+				HTTP: &datavolumev1beta1.DataVolumeSourceHTTP{URL: testHTTPURL},
+			},
+		}),
+		table.Entry("upload mode", &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceUpload:        "true",
+			Size:                "10Gi",
+		}, map[string]interface{}{
+			"GetCreationMode": constants.SourceCreationMode,
+			"GetSourceUpload": true,
+			"GetDataVolumeSource": &datavolumev1beta1.DataVolumeSource{
+				Upload: &datavolumev1beta1.DataVolumeSourceUpload{},
+			},
+		}),
+	)
+
+	It("parses size", func() {
+		options := &parse.CLIOptions{
+			DataVolumeName:      "test",
+			DataVolumeNamespace: defaultNS,
+			SourceSnapshot:      "snapshot",
+			Size:                "10Gi",
+		}
+		Expect(options.Init()).Should(Succeed())
+		Expect(options.GetSize().Cmp(resource.MustParse("10Gi"))).To(Equal(0))
+	})
+})
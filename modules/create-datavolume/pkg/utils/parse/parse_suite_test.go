@@ -0,0 +1,16 @@
+package parse_test
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/utilstest"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestParse(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Parse Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
@@ -0,0 +1,38 @@
+package parse
+
+import (
+	"strings"
+)
+
+func (c *CLIOptions) getSpecifiedSourceOptionNames() []string {
+	var result []string
+
+	for _, option := range []struct {
+		name      string
+		specified bool
+	}{
+		{dvManifestOptionName, c.DataVolumeManifest != ""},
+		{sourcePVCOptionName, c.SourcePVC != ""},
+		{sourceSnapshotOptionName, c.SourceSnapshot != ""},
+		{sourceRegistryURLOptionName, c.SourceRegistryURL != ""},
+		{sourceHTTPURLOptionName, c.SourceHTTPURL != ""},
+		{sourceUploadOptionName, c.GetSourceUpload()},
+	} {
+		if option.specified {
+			result = append(result, option.name)
+		}
+	}
+
+	return result
+}
+
+// splitNamespacedName defaults the namespace to the DataVolume namespace
+func (c *CLIOptions) splitNamespacedName(input string) (string, string) {
+	split := strings.SplitN(input, namespaceSeparator, 2)
+
+	if len(split) == 2 {
+		return strings.TrimSpace(split[0]), strings.TrimSpace(split[1])
+	}
+
+	return c.DataVolumeNamespace, strings.TrimSpace(input)
+}
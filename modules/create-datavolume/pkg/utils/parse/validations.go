@@ -0,0 +1,166 @@
+package parse
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-datavolume/pkg/datavolume"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zconstants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var sourceOptionNames = strings.Join([]string{
+	dvManifestOptionName,
+	sourcePVCOptionName,
+	sourceSnapshotOptionName,
+	sourceRegistryURLOptionName,
+	sourceHTTPURLOptionName,
+	sourceUploadOptionName,
+}, ", ")
+
+func (c *CLIOptions) trimSpaces() {
+	for _, strVariablePtr := range []*string{&c.DataVolumeName, &c.DataVolumeNamespace, &c.SourcePVC, &c.SourceSnapshot, &c.SourceRegistryURL,
+		&c.SourceHTTPURL, &c.SourceUpload, &c.Size, &c.StorageClass, &c.WaitForSuccess, &c.Timeout, &c.ImporterPodMaxRestarts} {
+		*strVariablePtr = strings.TrimSpace(*strVariablePtr)
+	}
+	if strings.TrimSpace(c.DataVolumeManifest) == "" {
+		c.DataVolumeManifest = ""
+	}
+}
+
+func (c *CLIOptions) assertValidMode() error {
+	specifiedSources := c.getSpecifiedSourceOptionNames()
+
+	if len(specifiedSources) == 0 {
+		return zerrors.NewSoftError("one of %v should be specified", sourceOptionNames)
+	}
+
+	if len(specifiedSources) > 1 {
+		return zerrors.NewSoftError("only one of %v should be specified", strings.Join(specifiedSources, ", "))
+	}
+
+	if c.GetCreationMode() == constants.DataVolumeManifestCreationMode {
+		if c.DataVolumeName != "" || c.Size != "" || c.StorageClass != "" {
+			return zerrors.NewMissingRequiredError("%v, %v, %v options are not applicable for %v", dvNameOptionName, sizeOptionName, storageClassOptionName, dvManifestOptionName)
+		}
+	} else if c.DataVolumeName == "" {
+		return zerrors.NewMissingRequiredError("%v option is required", dvNameOptionName)
+	}
+
+	return nil
+}
+
+func (c *CLIOptions) assertValidTypes() error {
+	if !output.IsOutputType(string(c.Output)) {
+		return zerrors.NewMissingRequiredError("%v is not a valid output type", c.Output)
+	}
+
+	if !log.IsLogFormat(c.LogFormat) {
+		return zerrors.NewMissingRequiredError("%v is not a valid log format", c.LogFormat)
+	}
+
+	for optionName, value := range map[string]string{waitForSuccessOptionName: c.WaitForSuccess, sourceUploadOptionName: c.SourceUpload} {
+		switch value {
+		case "", zconstants.True, zconstants.False:
+		default:
+			return zerrors.NewMissingRequiredError("invalid option %v %v, only true|false is allowed", optionName, value)
+		}
+	}
+
+	if c.Timeout != "" {
+		if _, err := time.ParseDuration(c.Timeout); err != nil {
+			return zerrors.NewMissingRequiredError("could not parse %v: %v", timeoutOptionName, err.Error())
+		}
+	}
+
+	if c.ImporterPodMaxRestarts != "" {
+		if maxRestarts, err := strconv.ParseInt(c.ImporterPodMaxRestarts, 10, 32); err != nil || maxRestarts < 0 {
+			return zerrors.NewMissingRequiredError("%v should be a non-negative integer", importerPodMaxRestartsOptionName)
+		}
+	}
+
+	if c.Size != "" {
+		if size, err := resource.ParseQuantity(c.Size); err != nil {
+			return zerrors.NewMissingRequiredError("could not parse %v: %v", sizeOptionName, err.Error())
+		} else if size.Sign() <= 0 {
+			return zerrors.NewMissingRequiredError("%v should be a positive quantity", sizeOptionName)
+		}
+	}
+
+	return nil
+}
+
+func (c *CLIOptions) resolveNamespaceAndManifest() error {
+	if c.GetCreationMode() == constants.DataVolumeManifestCreationMode {
+		dv, err := datavolume.DecodeDataVolume(c.DataVolumeManifest)
+		if err != nil {
+			return err
+		}
+
+		if c.DataVolumeNamespace == "" && dv.Namespace != "" {
+			c.DataVolumeNamespace = dv.Namespace
+		}
+	}
+
+	if c.DataVolumeNamespace == "" {
+		activeNamespace, err := env.GetActiveNamespace()
+		if err != nil {
+			return zerrors.NewMissingRequiredError("%v: %v option is empty", err.Error(), dvNamespaceOptionName)
+		}
+		c.DataVolumeNamespace = activeNamespace
+	}
+
+	return nil
+}
+
+func (c *CLIOptions) assertValidSource() error {
+	switch {
+	case c.SourcePVC != "":
+		if namespace, name := c.GetSourcePVC(); namespace == "" || name == "" {
+			return zerrors.NewMissingRequiredError("invalid %v %v: should be in [NAMESPACE/]NAME format", sourcePVCOptionName, c.SourcePVC)
+		}
+	case c.SourceSnapshot != "":
+		if namespace, name := c.GetSourceSnapshot(); namespace == "" || name == "" {
+			return zerrors.NewMissingRequiredError("invalid %v %v: should be in [NAMESPACE/]NAME format", sourceSnapshotOptionName, c.SourceSnapshot)
+		}
+		if c.Size == "" {
+			return zerrors.NewMissingRequiredError("%v option is required for %v", sizeOptionName, sourceSnapshotOptionName)
+		}
+	case c.SourceRegistryURL != "":
+		if err := assertURLScheme(sourceRegistryURLOptionName, c.SourceRegistryURL, "docker", "oci-archive"); err != nil {
+			return err
+		}
+	case c.SourceHTTPURL != "":
+		if err := assertURLScheme(sourceHTTPURLOptionName, c.SourceHTTPURL, "http", "https"); err != nil {
+			return err
+		}
+	}
+
+	if c.GetCreationMode() == constants.SourceCreationMode && c.SourcePVC == "" && c.Size == "" {
+		return zerrors.NewMissingRequiredError("%v option is required", sizeOptionName)
+	}
+
+	return nil
+}
+
+func assertURLScheme(optionName, value string, schemes ...string) error {
+	parsedURL, err := url.Parse(value)
+	if err != nil {
+		return zerrors.NewMissingRequiredError("could not parse %v: %v", optionName, err.Error())
+	}
+
+	for _, scheme := range schemes {
+		if parsedURL.Scheme == scheme {
+			return nil
+		}
+	}
+
+	return zerrors.NewMissingRequiredError("invalid %v %v: only %v schemes are supported", optionName, value, strings.Join(schemes, "|"))
+}
@@ -1,18 +1,23 @@
 package main
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
 
 	goarg "github.com/alexflint/go-arg"
 	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vmi"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vmcreator"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/metrics"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
 	res "github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/results"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"go.uber.org/zap"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
 )
 
 func main() {
@@ -21,13 +26,14 @@ func main() {
 	cliOptions := &parse.CLIOptions{}
 	goarg.MustParse(cliOptions)
 
-	logger := log.InitLogger(cliOptions.GetDebugLevel())
+	logger := log.InitLoggerWithFormat(cliOptions.GetDebugLevel(), cliOptions.GetLogFormat())
 	defer logger.Sync()
 
 	if err := cliOptions.Init(); err != nil {
 		exit.ExitOrDieFromError(InvalidCLIInputExitCode, err)
 	}
 	log.Logger().Debug("parsed arguments", zap.Reflect("cliOptions", cliOptions))
+	defer exportMetrics(cliOptions)
 
 	vmCreator, err := vmcreator.NewVMCreator(cliOptions)
 
@@ -39,34 +45,162 @@ func main() {
 		exit.ExitFromError(VolumesNotPresentExitCode, err)
 	}
 
-	vm, err := vmCreator.CreateVM()
+	rollbackOnFailure := func() {
+		if !cliOptions.GetRollbackOnFailure() {
+			return
+		}
+		if err := vmCreator.Rollback(); err != nil {
+			log.Logger().Error("could not roll back all changes", zap.Error(err))
+		}
+	}
 
-	if err != nil {
-		exit.ExitOrDieFromError(CreateVMErrorExitCode, err,
-			zerrors.IsStatusError(err, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+	var vms []*kubevirtv1.VirtualMachine
+	createStart := time.Now()
+	createErr := log.Phase("CreateVMs", func() (err error) {
+		vms, err = vmCreator.CreateVMs()
+		return err
+	})
+	metrics.RecordDuration("vm_creation_duration_seconds", "Duration of the VM creation", createStart, nil)
+	metrics.RecordOutcome("vm_creation_success", "Whether all VMs were created", createErr, nil)
+
+	if len(vms) == 0 || (createErr != nil && cliOptions.GetRollbackOnFailure()) {
+		rollbackOnFailure()
+		exit.ExitOrDieFromError(CreateVMErrorExitCode, createErr,
+			zerrors.IsStatusError(createErr, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
 		)
 	}
+	if createErr != nil {
+		log.Logger().Error("some VMs could not be created", zap.Error(createErr))
+	}
+	log.AddTarget(kubevirtv1.VirtualMachineGroupVersionKind.Kind, vms[0].Namespace, vms[0].Name)
+
+	for _, vm := range vms {
+		if cliOptions.IsDryRun() {
+			log.Logger().Info("VM was not created because of dry run", zap.String("name", vm.Name), zap.String("dryRun", string(cliOptions.GetDryRun())))
+		} else if vmCreator.SkippedExistingVM(vm) {
+			log.Logger().Info("using existing VM", zap.String("name", vm.Name), zap.String("namespace", vm.Namespace))
+		} else {
+			if err := log.Phase("OwnVolumes", func() error { return vmCreator.OwnVolumes(vm) }); err != nil {
+				rollbackOnFailure()
+				exit.ExitFromError(OwnVolumesErrorExitCode, err)
+			}
+
+			if cliOptions.GetStartVMFlag() {
+				err := log.Phase("StartVM", func() error { return vmCreator.StartVM(vm) })
+				if err != nil {
+					rollbackOnFailure()
+					exit.ExitFromError(StartVMErrorExitCode, err)
+				}
+			}
+		}
+	}
 
-	if err := vmCreator.OwnVolumes(vm); err != nil {
-		exit.ExitFromError(OwnVolumesErrorExitCode, err)
+	vm := vms[0]
+	names := make([]string, 0, len(vms))
+	for _, createdVM := range vms {
+		names = append(names, createdVM.Name)
+	}
+	namesJSON, err := json.Marshal(names)
+	if err != nil {
+		rollbackOnFailure()
+		exit.ExitOrDieFromError(WriteResultsExitCode, err)
 	}
 
-	if cliOptions.GetStartVMFlag() {
-		err := vmCreator.StartVM(vm.Namespace, vm.Name)
-		if err != nil {
-			exit.ExitFromError(StartVMErrorExitCode, err)
+	results := map[string]string{
+		NameResultName:        vm.Name,
+		NamespaceResultName:   vm.Namespace,
+		NamesResultName:       string(namesJSON),
+		IPAddressResultName:   "",
+		IPAddressesResultName: "",
+		NodeNameResultName:    "",
+		NodeNamesResultName:   "",
+		GuestOSInfoResultName: "",
+		OutputResultName:      "",
+	}
+
+	if cliOptions.GetWaitForReady() {
+		ipAddresses := make([]string, 0, len(vms))
+		nodeNames := make([]string, 0, len(vms))
+
+		for idx, createdVM := range vms {
+			var virtualMachineInstance *kubevirtv1.VirtualMachineInstance
+			readyStart := time.Now()
+			err := log.Phase("WaitForReady", func() (err error) {
+				virtualMachineInstance, err = vmCreator.WaitForReady(createdVM)
+				return err
+			})
+			metrics.RecordDuration("vm_ready_duration_seconds", "Duration until the VM was ready", readyStart, metrics.Labels{"vm": createdVM.Name})
+			metrics.RecordOutcome("vm_ready_success", "Whether the VM became ready", err, metrics.Labels{"vm": createdVM.Name})
+			if err != nil {
+				rollbackOnFailure()
+				exit.ExitOrDieFromError(VMIFailedExitCode, err)
+			}
+			log.Logger().Info("VMI is ready", zap.String("name", virtualMachineInstance.Name), zap.String("ipAddress", vmi.GetIPAddress(virtualMachineInstance)),
+				zap.String("nodeName", vmi.GetNodeName(virtualMachineInstance)))
+			ipAddresses = append(ipAddresses, vmi.GetIPAddress(virtualMachineInstance))
+			nodeNames = append(nodeNames, vmi.GetNodeName(virtualMachineInstance))
+
+			if idx != 0 {
+				continue
+			}
+
+			guestOSInfo, err := vmi.GetGuestOSInfo(virtualMachineInstance)
+			if err != nil {
+				log.Logger().Warn("could not read guest OS info", zap.Error(err))
+			}
+
+			results[IPAddressResultName] = vmi.GetIPAddress(virtualMachineInstance)
+			results[NodeNameResultName] = vmi.GetNodeName(virtualMachineInstance)
+			results[GuestOSInfoResultName] = guestOSInfo
+		}
+
+		for resultName, values := range map[string][]string{IPAddressesResultName: ipAddresses, NodeNamesResultName: nodeNames} {
+			valuesJSON, err := json.Marshal(values)
+			if err != nil {
+				rollbackOnFailure()
+				exit.ExitOrDieFromError(WriteResultsExitCode, err)
+			}
+			results[resultName] = string(valuesJSON)
 		}
 	}
 
-	results := map[string]string{
-		NameResultName:      vm.Name,
-		NamespaceResultName: vm.Namespace,
+	var outputObject interface{} = vm
+	if cliOptions.IsBatch() {
+		outputObject = vms
+	}
+
+	if outputFile := cliOptions.GetOutputFile(); outputFile != "" {
+		log.Logger().Debug("writing output file", zap.String("path", outputFile))
+		if err := output.WriteToFile(outputObject, output.GetOutputTypeFromPath(outputFile), outputFile); err != nil {
+			rollbackOnFailure()
+			exit.ExitOrDieFromError(WriteResultsExitCode, err)
+		}
+	}
+
+	if outputJSONPath := cliOptions.GetOutputJSONPath(); outputJSONPath != "" {
+		projectedOutput, err := output.JSONPath(outputObject, outputJSONPath)
+		if err != nil {
+			rollbackOnFailure()
+			exit.ExitOrDieFromError(WriteResultsExitCode, err)
+		}
+		results[OutputResultName] = projectedOutput
 	}
 
 	log.Logger().Debug("recording results", zap.Reflect("results", results))
 	if err := res.RecordResults(results); err != nil {
+		rollbackOnFailure()
 		exit.ExitOrDieFromError(WriteResultsExitCode, err)
 	}
 
-	output.PrettyPrint(vm, cliOptions.Output)
+	output.PrettyPrint(outputObject, cliOptions.Output)
+
+	if createErr != nil {
+		exit.ExitOrDieFromError(CreateVMErrorExitCode, createErr)
+	}
+}
+
+func exportMetrics(cliOptions *parse.CLIOptions) {
+	if err := metrics.Export(cliOptions.GetMetricsFile(), cliOptions.GetMetricsPushgatewayURL()); err != nil {
+		log.Logger().Warn("could not export metrics", zap.Error(err))
+	}
 }
@@ -1,25 +1,107 @@
 package constants
 
+import "time"
+
 // Exit codes
 const (
-	GenericExitCode           = 1
-	InvalidCLIInputExitCode   = 2
-	VolumesNotPresentExitCode = 3
-	CreateVMErrorExitCode     = 4
-	OwnVolumesErrorExitCode   = 5
-	WriteResultsExitCode      = 6
-	StartVMErrorExitCode      = 7
+	GenericExitCode             = 1
+	InvalidCLIInputExitCode     = 2
+	VolumesNotPresentExitCode   = 3
+	CreateVMErrorExitCode       = 4
+	OwnVolumesErrorExitCode     = 5
+	WriteResultsExitCode        = 6
+	StartVMErrorExitCode        = 7
+	VMIFailedExitCode           = 8
+	WaitForReadyTimeoutExitCode = 9
+)
+
+const DefaultWaitForReadyTimeout = time.Hour
+
+const (
+	DefaultConcurrency = 10
+	// IndexPlaceholder is replaced by the index of the created VM in the name pattern and template params
+	IndexPlaceholder = "{index}"
 )
 
 // Result names
 const (
-	NameResultName      = "name"
-	NamespaceResultName = "namespace"
+	NameResultName        = "name"
+	NamespaceResultName   = "namespace"
+	NamesResultName       = "names"
+	IPAddressResultName   = "ipAddress"
+	IPAddressesResultName = "ipAddresses"
+	NodeNameResultName    = "nodeName"
+	NodeNamesResultName   = "nodeNames"
+	GuestOSInfoResultName = "guestOSInfo"
+	OutputResultName      = "output"
 )
 
 type CreationMode string
 
 const (
-	TemplateCreationMode   CreationMode = "TemplateCreationMode"
-	VMManifestCreationMode CreationMode = "VMManifestCreationMode"
+	TemplateCreationMode     CreationMode = "TemplateCreationMode"
+	VMManifestCreationMode   CreationMode = "VMManifestCreationMode"
+	InstancetypeCreationMode CreationMode = "InstancetypeCreationMode"
+)
+
+// Kinds of instancetypes and preferences referenced by spec.instancetype and spec.preference of a VM
+const (
+	VirtualMachineInstancetypeKind        = "VirtualMachineInstancetype"
+	VirtualMachineClusterInstancetypeKind = "VirtualMachineClusterInstancetype"
+	VirtualMachinePreferenceKind          = "VirtualMachinePreference"
+	VirtualMachineClusterPreferenceKind   = "VirtualMachineClusterPreference"
+)
+
+type DryRunMode string
+
+const (
+	DryRunNone   DryRunMode = "none"
+	DryRunClient DryRunMode = "client"
+	DryRunServer DryRunMode = "server"
+)
+
+type CloudInitType string
+
+const (
+	CloudInitNoCloud     CloudInitType = "nocloud"
+	CloudInitConfigDrive CloudInitType = "configdrive"
+)
+
+type SSHPropagationMethod string
+
+const (
+	SSHPropagationCloudInit      SSHPropagationMethod = "cloud-init"
+	SSHPropagationQemuGuestAgent SSHPropagationMethod = "qemu-guest-agent"
+)
+
+type NetworkType string
+
+const (
+	PodNetworkType    NetworkType = "pod"
+	MultusNetworkType NetworkType = "multus"
+)
+
+type InterfaceBinding string
+
+const (
+	BridgeInterfaceBinding     InterfaceBinding = "bridge"
+	MasqueradeInterfaceBinding InterfaceBinding = "masquerade"
+	SRIOVInterfaceBinding      InterfaceBinding = "sriov"
+)
+
+type IfExistsMode string
+
+const (
+	IfExistsFail    IfExistsMode = "fail"
+	IfExistsSkip    IfExistsMode = "skip"
+	IfExistsReplace IfExistsMode = "replace"
+	IfExistsPatch   IfExistsMode = "patch"
+)
+
+type BootSourceKind string
+
+const (
+	DataSourceBootSourceKind BootSourceKind = "DataSource"
+	DataVolumeBootSourceKind BootSourceKind = "DataVolume"
+	PVCBootSourceKind        BootSourceKind = "PVC"
 )
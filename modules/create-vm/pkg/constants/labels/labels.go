@@ -10,6 +10,9 @@ const (
 	// TemplateFlavorLabel is a label that specifies the flavor of the template
 	TemplateFlavorLabel = "flavor.template.kubevirt.io"
 
+	// TemplateVersionLabel is a label that specifies the version of the template
+	TemplateVersionLabel = "template.kubevirt.io/version"
+
 	// TemplateNameOsAnnotation is an annotation that specifies human readable os name
 	TemplateNameOsAnnotation = "name.os.template.kubevirt.io"
 
@@ -0,0 +1,67 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	datavolumeclientv1beta1 "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/typed/core/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	dataSourcesResource = "datasources"
+)
+
+// dataSource is a subset of cdi.kubevirt.io/v1beta1 DataSource which is not available in the vendored CDI API
+type dataSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Source struct {
+			PVC *datavolumev1beta1.DataVolumeSourcePVC `json:"pvc,omitempty"`
+		} `json:"source"`
+	} `json:"spec"`
+}
+
+type dataSourceProvider struct {
+	client datavolumeclientv1beta1.CdiV1beta1Interface
+}
+
+type DataSourceProvider interface {
+	GetSourcePVC(namespace string, name string) (*datavolumev1beta1.DataVolumeSourcePVC, error)
+}
+
+func NewDataSourceProvider(client datavolumeclientv1beta1.CdiV1beta1Interface) DataSourceProvider {
+	return &dataSourceProvider{
+		client: client,
+	}
+}
+
+// GetSourcePVC returns the PVC the DataSource points to
+func (d *dataSourceProvider) GetSourcePVC(namespace string, name string) (*datavolumev1beta1.DataVolumeSourcePVC, error) {
+	raw, err := d.client.RESTClient().Get().
+		Namespace(namespace).
+		Resource(dataSourcesResource).
+		Name(name).
+		Do(context.TODO()).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	var result dataSource
+	if err := yaml.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("could not read %v DataSource: %v", name, err.Error())
+	}
+
+	sourcePVC := result.Spec.Source.PVC
+	if sourcePVC == nil || sourcePVC.Name == "" {
+		return nil, fmt.Errorf("%v DataSource does not have a PVC source", name)
+	}
+	if sourcePVC.Namespace == "" {
+		sourcePVC.Namespace = namespace
+	}
+	return sourcePVC, nil
+}
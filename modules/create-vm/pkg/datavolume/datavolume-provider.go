@@ -18,6 +18,7 @@ type dataVolumeProvider struct {
 type DataVolumeProvider interface {
 	GetByName(namespace string, names ...string) ([]*datavolumev1beta1.DataVolume, error)
 	AddOwnerReferences(dv *datavolumev1beta1.DataVolume, newOwnerRefs ...metav1.OwnerReference) (*datavolumev1beta1.DataVolume, error)
+	RemoveOwnerReferences(dv *datavolumev1beta1.DataVolume, ownerUID types.UID) (*datavolumev1beta1.DataVolume, error)
 }
 
 func NewDataVolumeProvider(client datavolumeclientv1beta1.CdiV1beta1Interface) DataVolumeProvider {
@@ -62,3 +63,24 @@ func (d *dataVolumeProvider) AddOwnerReferences(dv *datavolumev1beta1.DataVolume
 
 	return d.client.DataVolumes(dv.Namespace).Patch(context.TODO(), dv.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
 }
+
+func (d *dataVolumeProvider) RemoveOwnerReferences(dv *datavolumev1beta1.DataVolume, ownerUID types.UID) (*datavolumev1beta1.DataVolume, error) {
+	if dv == nil {
+		return nil, errors.New("did not receive any DataVolume to remove reference from")
+	}
+
+	result := dv.DeepCopy()
+	result.SetOwnerReferences(k8s.RemoveOwnerReferences(result.GetOwnerReferences(), ownerUID))
+
+	if len(result.GetOwnerReferences()) == len(dv.GetOwnerReferences()) {
+		return dv, nil
+	}
+
+	patch, err := k8s.CreatePatch(dv, result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.client.DataVolumes(dv.Namespace).Patch(context.TODO(), dv.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+}
@@ -1,14 +1,41 @@
 package k8s
 
-import "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
 
+// AppendOwnerReferences skips owner references which are already present
 func AppendOwnerReferences(ownerRefs []v1.OwnerReference, newOwnerRefs []v1.OwnerReference) []v1.OwnerReference {
 	if ownerRefs == nil {
 		ownerRefs = []v1.OwnerReference{}
 	}
 
 	for _, newOwnerRef := range newOwnerRefs {
+		if newOwnerRef.UID != "" && hasOwnerReference(ownerRefs, newOwnerRef.UID) {
+			continue
+		}
 		ownerRefs = append(ownerRefs, newOwnerRef)
 	}
 	return ownerRefs
 }
+
+func RemoveOwnerReferences(ownerRefs []v1.OwnerReference, ownerUID types.UID) []v1.OwnerReference {
+	result := []v1.OwnerReference{}
+
+	for _, ownerRef := range ownerRefs {
+		if ownerRef.UID != ownerUID {
+			result = append(result, ownerRef)
+		}
+	}
+	return result
+}
+
+func hasOwnerReference(ownerRefs []v1.OwnerReference, ownerUID types.UID) bool {
+	for _, ownerRef := range ownerRefs {
+		if ownerRef.UID == ownerUID {
+			return true
+		}
+	}
+	return false
+}
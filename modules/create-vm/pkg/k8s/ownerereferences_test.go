@@ -21,4 +21,22 @@ var _ = Describe("Ownerereferences", func() {
 		table.Entry("empty", []v1.OwnerReference{}),
 		table.Entry("one", []v1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "first", UID: "", Controller: nil, BlockOwnerDeletion: nil}}),
 	)
+
+	It("does not append existing OwnerReferences", func() {
+		existing := []v1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "first", UID: "1"}}
+		refs := []v1.OwnerReference{
+			{APIVersion: "v1", Kind: "Pod", Name: "first", UID: "1"},
+			{APIVersion: "v1", Kind: "Pod", Name: "second", UID: "2"},
+		}
+
+		Expect(k8s.AppendOwnerReferences(existing, refs)).To(Equal(refs))
+	})
+
+	table.DescribeTable("Remove OwnerReferences", func(refs []v1.OwnerReference, expectedRefs []v1.OwnerReference) {
+		Expect(k8s.RemoveOwnerReferences(refs, "1")).To(Equal(expectedRefs))
+	},
+		table.Entry("nil", nil, []v1.OwnerReference{}),
+		table.Entry("other", []v1.OwnerReference{{Name: "second", UID: "2"}}, []v1.OwnerReference{{Name: "second", UID: "2"}}),
+		table.Entry("matching", []v1.OwnerReference{{Name: "first", UID: "1"}, {Name: "second", UID: "2"}}, []v1.OwnerReference{{Name: "second", UID: "2"}}),
+	)
 })
@@ -18,6 +18,7 @@ type pvcProvider struct {
 type PersistentVolumeClaimProvider interface {
 	GetByName(namespace string, names ...string) ([]*v1.PersistentVolumeClaim, error)
 	AddOwnerReferences(dv *v1.PersistentVolumeClaim, newOwnerRefs ...metav1.OwnerReference) (*v1.PersistentVolumeClaim, error)
+	RemoveOwnerReferences(pvc *v1.PersistentVolumeClaim, ownerUID types.UID) (*v1.PersistentVolumeClaim, error)
 }
 
 func NewPersistentVolumeClaimProvider(client clientv1.CoreV1Interface) PersistentVolumeClaimProvider {
@@ -62,3 +63,24 @@ func (d *pvcProvider) AddOwnerReferences(pvc *v1.PersistentVolumeClaim, newOwner
 
 	return d.client.PersistentVolumeClaims(pvc.Namespace).Patch(context.TODO(), pvc.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
 }
+
+func (d *pvcProvider) RemoveOwnerReferences(pvc *v1.PersistentVolumeClaim, ownerUID types.UID) (*v1.PersistentVolumeClaim, error) {
+	if pvc == nil {
+		return nil, errors.New("did not receive any PersistentVolumeClaim to remove reference from")
+	}
+
+	result := pvc.DeepCopy()
+	result.SetOwnerReferences(k8s.RemoveOwnerReferences(result.GetOwnerReferences(), ownerUID))
+
+	if len(result.GetOwnerReferences()) == len(pvc.GetOwnerReferences()) {
+		return pvc, nil
+	}
+
+	patch, err := k8s.CreatePatch(pvc, result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.client.PersistentVolumeClaims(pvc.Namespace).Patch(context.TODO(), pvc.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+}
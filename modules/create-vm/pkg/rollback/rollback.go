@@ -0,0 +1,59 @@
+package rollback
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"go.uber.org/zap"
+)
+
+type action struct {
+	description string
+	undo        func() error
+}
+
+// Rollback records how to undo each change made to the cluster and undoes the changes in reverse order
+type Rollback struct {
+	actions []action
+	lock    sync.Mutex
+}
+
+func NewRollback() *Rollback {
+	return &Rollback{}
+}
+
+// Add records an undo function of a change, eg. "create VM my-vm"
+func (r *Rollback) Add(description string, undo func() error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.actions = append(r.actions, action{description: description, undo: undo})
+}
+
+func (r *Rollback) Len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.actions)
+}
+
+// Run undoes all recorded changes in reverse order. Continues with the next change when one of them cannot be undone.
+func (r *Rollback) Run() error {
+	r.lock.Lock()
+	actions := r.actions
+	r.actions = nil
+	r.lock.Unlock()
+
+	var multiError zerrors.MultiError
+
+	for idx := len(actions) - 1; idx >= 0; idx-- {
+		description := actions[idx].description
+		log.Logger().Info("rolling back", zap.String("change", description))
+
+		if err := actions[idx].undo(); err != nil {
+			multiError.Add(description, fmt.Errorf("could not roll back %v: %v", description, err.Error()))
+		}
+	}
+
+	return multiError.AsOptional()
+}
@@ -0,0 +1,16 @@
+package rollback_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utilstest"
+)
+
+func TestRollback(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rollback Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
@@ -0,0 +1,53 @@
+package rollback_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/rollback"
+)
+
+var _ = Describe("Rollback", func() {
+	var r *rollback.Rollback
+	var undone []string
+
+	undo := func(description string, err error) func() error {
+		return func() error {
+			undone = append(undone, description)
+			return err
+		}
+	}
+
+	BeforeEach(func() {
+		r = rollback.NewRollback()
+		undone = nil
+	})
+
+	It("does nothing without changes", func() {
+		Expect(r.Run()).To(Succeed())
+		Expect(undone).To(BeEmpty())
+	})
+
+	It("undoes changes in reverse order", func() {
+		r.Add("create VM", undo("create VM", nil))
+		r.Add("add owner reference", undo("add owner reference", nil))
+		r.Add("start VM", undo("start VM", nil))
+		Expect(r.Len()).To(Equal(3))
+
+		Expect(r.Run()).To(Succeed())
+		Expect(undone).To(Equal([]string{"start VM", "add owner reference", "create VM"}))
+		Expect(r.Len()).To(Equal(0))
+	})
+
+	It("continues when a change cannot be undone", func() {
+		r.Add("create VM", undo("create VM", nil))
+		r.Add("add owner reference", undo("add owner reference", errors.New("forbidden")))
+
+		err := r.Run()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("could not roll back add owner reference: forbidden\n"))
+		Expect(undone).To(Equal([]string{"add owner reference", "create VM"}))
+	})
+})
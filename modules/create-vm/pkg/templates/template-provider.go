@@ -5,11 +5,14 @@ import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	templatev1 "github.com/openshift/api/template/v1"
 	tempclient "github.com/openshift/client-go/template/clientset/versioned/typed/template/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
 )
 
 const (
-	processingURI = "processedTemplates"
+	processingURI     = "processedTemplates"
+	templatesResource = "templates"
 )
 
 type templateProvider struct {
@@ -18,9 +21,28 @@ type templateProvider struct {
 
 type TemplateProvider interface {
 	Get(namespace string, name string) (*templatev1.Template, error)
+	List(namespace string, labelSelector string) ([]templatev1.Template, error)
 	Process(namespace string, template *templatev1.Template, paramValues map[string]string) (*templatev1.Template, error)
 }
 
+// IsTemplateAPIAvailable returns false if the cluster does not serve the template.openshift.io API, eg. on plain Kubernetes
+func IsTemplateAPIAvailable(client discovery.ServerResourcesInterface) (bool, error) {
+	resources, err := client.ServerResourcesForGroupVersion(templatev1.GroupVersion.String())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Name == templatesResource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func NewTemplateProvider(client tempclient.TemplateV1Interface) TemplateProvider {
 	return &templateProvider{
 		client: client,
@@ -31,6 +53,14 @@ func (t *templateProvider) Get(namespace string, name string) (*templatev1.Templ
 	return t.client.Templates(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 }
 
+func (t *templateProvider) List(namespace string, labelSelector string) ([]templatev1.Template, error) {
+	templateList, err := t.client.Templates(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	return templateList.Items, nil
+}
+
 func (t *templateProvider) Process(namespace string, template *templatev1.Template, paramValues map[string]string) (*templatev1.Template, error) {
 	temp := template.DeepCopy()
 	params := temp.Parameters
@@ -0,0 +1,52 @@
+package templates_test
+
+import (
+	"errors"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/templates"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+type fakeDiscovery struct {
+	discovery.ServerResourcesInterface
+	resources map[string]*metav1.APIResourceList
+	err       error
+}
+
+func (f *fakeDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if resources, ok := f.resources[groupVersion]; ok {
+		return resources, nil
+	}
+	return nil, k8serrors.NewNotFound(schema.GroupResource{Group: groupVersion}, "")
+}
+
+var _ = Describe("TemplateProvider", func() {
+	table.DescribeTable("IsTemplateAPIAvailable", func(client *fakeDiscovery, expectedAvailable bool, expectedErr string) {
+		available, err := templates.IsTemplateAPIAvailable(client)
+		if expectedErr != "" {
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(Equal(expectedErr))
+		} else {
+			Expect(err).Should(Succeed())
+		}
+		Expect(available).To(Equal(expectedAvailable))
+	},
+		table.Entry("plain kubernetes", &fakeDiscovery{}, false, ""),
+		table.Entry("okd", &fakeDiscovery{resources: map[string]*metav1.APIResourceList{
+			"template.openshift.io/v1": {APIResources: []metav1.APIResource{{Name: "processedtemplates"}, {Name: "templates"}}},
+		}}, true, ""),
+		table.Entry("missing templates resource", &fakeDiscovery{resources: map[string]*metav1.APIResourceList{
+			"template.openshift.io/v1": {APIResources: []metav1.APIResource{{Name: "templateinstances"}}},
+		}}, false, ""),
+		table.Entry("discovery error", &fakeDiscovery{err: errors.New("connection refused")}, false, "connection refused"),
+	)
+})
@@ -42,6 +42,31 @@ func GetTemplateValidations(template *templatev1.Template) (*validations.Templat
 	return validations.NewTemplateValidations(commonTemplateValidations), nil
 }
 
+// GetNewestTemplate returns the template with the newest revision. Templates are ordered by their
+// version label first and by their name second, so fedora-server-small-v0.11.3 is newer than fedora-server-small
+func GetNewestTemplate(templates []templatev1.Template) *templatev1.Template {
+	var newest *templatev1.Template
+
+	for i := range templates {
+		if newest == nil || isOlder(newest, &templates[i]) {
+			newest = &templates[i]
+		}
+	}
+
+	return newest
+}
+
+func isOlder(template *templatev1.Template, otherTemplate *templatev1.Template) bool {
+	version := template.Labels[lab.TemplateVersionLabel]
+	otherVersion := otherTemplate.Labels[lab.TemplateVersionLabel]
+
+	if version != otherVersion {
+		return textIDs{version, otherVersion}.Less(0, 1)
+	}
+
+	return textIDs{template.Name, otherTemplate.Name}.Less(0, 1)
+}
+
 // returns osID, osName
 func GetOs(template *templatev1.Template) (string, string) {
 
@@ -3,7 +3,10 @@ package templates_test
 import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/sharedtest/testobjects/template"
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	templatev1 "github.com/openshift/api/template/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/templates"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/sharedtest/testconstants"
@@ -24,6 +27,41 @@ var _ = Describe("Template", func() {
 		Expect(validations.GetDefaultDiskBus()).To(Equal(testconstants.Virtio))
 	})
 
+	table.DescribeTable("GetNewestTemplate", func(templateIDs [][]string, expectedName string) {
+		var testTemplates []templatev1.Template
+		for _, templateID := range templateIDs {
+			testTemplate := templatev1.Template{ObjectMeta: metav1.ObjectMeta{Name: templateID[0]}}
+			if len(templateID) > 1 {
+				testTemplate.Labels = map[string]string{"template.kubevirt.io/version": templateID[1]}
+			}
+			testTemplates = append(testTemplates, testTemplate)
+		}
+
+		newestTemplate := templates.GetNewestTemplate(testTemplates)
+		if expectedName == "" {
+			Expect(newestTemplate).To(BeNil())
+		} else {
+			Expect(newestTemplate.Name).To(Equal(expectedName))
+		}
+	},
+		table.Entry("no templates", nil, ""),
+		table.Entry("single template", [][]string{{"fedora-server-small"}}, "fedora-server-small"),
+		table.Entry("revision in name", [][]string{
+			{"fedora-server-small-v0.11.3"},
+			{"fedora-server-small"},
+			{"fedora-server-small-v0.9.1"},
+		}, "fedora-server-small-v0.11.3"),
+		table.Entry("version label", [][]string{
+			{"fedora-server-small-a", "v0.11.3"},
+			{"fedora-server-small-b", "v0.12.0"},
+			{"fedora-server-small-c", "v0.9.1"},
+		}, "fedora-server-small-b"),
+		table.Entry("version label wins over name", [][]string{
+			{"fedora-server-small-v0.11.3", "v0.11.3"},
+			{"fedora-server-small", "v0.13.1"},
+		}, "fedora-server-small"),
+	)
+
 	It("GetOs", func() {
 		osID, osName := templates.GetOs(template.NewFedoraServerTinyTemplate().Build())
 		Expect(osID).To(Equal("fedora29"))
@@ -0,0 +1,191 @@
+package validations
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+)
+
+const (
+	jsonPathPrefix = "jsonpath::"
+)
+
+const (
+	integerRule = "integer"
+	stringRule  = "string"
+	regexRule   = "regex"
+	enumRule    = "enum"
+)
+
+// Validate evaluates all rules against the VM template the same way the template validator webhook does.
+// Rules which are just warnings are only logged.
+func (t *TemplateValidations) Validate(vm *kubevirtv1.VirtualMachine) error {
+	if vm.Spec.Template == nil {
+		return nil
+	}
+
+	unstructuredVMI, err := runtime.DefaultUnstructuredConverter.ToUnstructured(vm.Spec.Template)
+	if err != nil {
+		return err
+	}
+
+	var multiError zerrors.MultiError
+
+	for _, validation := range t.validations {
+		if err := validation.evaluate(unstructuredVMI); err != nil {
+			if validation.JustWarning {
+				log.Logger().Warn("template validation failed", zap.String("name", validation.Name), zap.String("message", err.Error()))
+				continue
+			}
+			multiError.Add(validation.Name, zerrors.NewSoftError("%v", err.Error()))
+		}
+	}
+
+	return multiError.AsOptional()
+}
+
+// evaluate returns an error with the rule's message if any of the values found on the path does not satisfy the rule
+func (c *CommonTemplateValidation) evaluate(obj map[string]interface{}) error {
+	values, err := findValues(obj, c.Path)
+	if err != nil {
+		return c.newError("could not evaluate path %v: %v", c.Path, err.Error())
+	}
+
+	for _, value := range values {
+		var satisfied bool
+		switch c.Rule {
+		case integerRule:
+			satisfied, err = c.evaluateInteger(value)
+		case stringRule:
+			satisfied = c.evaluateString(value)
+		case regexRule:
+			satisfied, err = c.evaluateRegex(value)
+		case enumRule:
+			satisfied = c.evaluateEnum(value)
+		default:
+			err = fmt.Errorf("unknown rule %v", c.Rule)
+		}
+
+		if err != nil {
+			return c.newError("%v", err.Error())
+		}
+
+		if !satisfied {
+			return c.newError("value %v of %v does not satisfy %v rule", value, c.Path, c.Rule)
+		}
+	}
+
+	return nil
+}
+
+func (c *CommonTemplateValidation) evaluateInteger(value interface{}) (bool, error) {
+	intValue, err := toInt64(value)
+	if err != nil {
+		return false, err
+	}
+
+	if c.Min != nil && intValue < *c.Min {
+		return false, nil
+	}
+	if c.Max != nil && intValue > *c.Max {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (c *CommonTemplateValidation) evaluateString(value interface{}) bool {
+	length := len(fmt.Sprintf("%v", value))
+
+	if c.MinLength != nil && length < *c.MinLength {
+		return false
+	}
+	if c.MaxLength != nil && length > *c.MaxLength {
+		return false
+	}
+	return true
+}
+
+func (c *CommonTemplateValidation) evaluateRegex(value interface{}) (bool, error) {
+	return regexp.MatchString(c.Regex, fmt.Sprintf("%v", value))
+}
+
+func (c *CommonTemplateValidation) evaluateEnum(value interface{}) bool {
+	strValue := fmt.Sprintf("%v", value)
+
+	for _, allowedValue := range c.Values {
+		if allowedValue == strValue {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CommonTemplateValidation) newError(format string, a ...interface{}) error {
+	if c.Message != "" {
+		return fmt.Errorf("%v", c.Message)
+	}
+	return fmt.Errorf("%v: %v", c.Name, fmt.Sprintf(format, a...))
+}
+
+// findValues returns no values if the path does not exist in the object
+func findValues(obj map[string]interface{}, path string) ([]interface{}, error) {
+	if !strings.HasPrefix(path, jsonPathPrefix) {
+		return nil, fmt.Errorf("path should start with %v", jsonPathPrefix)
+	}
+
+	parser := jsonpath.New("validation").AllowMissingKeys(true)
+	if err := parser.Parse(fmt.Sprintf("{%v}", strings.TrimPrefix(path, jsonPathPrefix))); err != nil {
+		return nil, err
+	}
+
+	results, err := parser.FindResults(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+
+	return values, nil
+}
+
+func toInt64(value interface{}) (int64, error) {
+	if strValue, ok := value.(string); ok {
+		if intValue, err := strconv.ParseInt(strValue, 10, 64); err == nil {
+			return intValue, nil
+		}
+		quantity, err := resource.ParseQuantity(strValue)
+		if err != nil {
+			return 0, fmt.Errorf("could not convert %v to integer", strValue)
+		}
+		return quantity.Value(), nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflectValue.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(reflectValue.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return int64(reflectValue.Float()), nil
+	}
+
+	return 0, fmt.Errorf("could not convert %v to integer", value)
+}
@@ -0,0 +1,108 @@
+package validations_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/templates/validations"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utilstest/testobjects"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/sharedtest/testconstants"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+)
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func newTestVM() *kubevirtv1.VirtualMachine {
+	return &kubevirtv1.VirtualMachine{
+		Spec: kubevirtv1.VirtualMachineSpec{
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				Spec: kubevirtv1.VirtualMachineInstanceSpec{
+					Domain: kubevirtv1.DomainSpec{
+						CPU: &kubevirtv1.CPU{
+							Cores: 2,
+						},
+						Resources: kubevirtv1.ResourceRequirements{
+							Requests: v1.ResourceList{
+								v1.ResourceMemory: resource.MustParse("2Gi"),
+							},
+						},
+						Machine: kubevirtv1.Machine{
+							Type: "q35",
+						},
+						Devices: kubevirtv1.Devices{
+							Disks: []kubevirtv1.Disk{
+								{
+									Name: "rootdisk",
+									DiskDevice: kubevirtv1.DiskDevice{
+										Disk: &kubevirtv1.DiskTarget{Bus: Virtio},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Rules", func() {
+	table.DescribeTable("passes", func(templateValidations []validations.CommonTemplateValidation) {
+		Expect(validations.NewTemplateValidations(templateValidations).Validate(newTestVM())).To(Succeed())
+	},
+		table.Entry("nil", nil),
+		table.Entry("enum", testobjects.NewTestCommonTemplateValidations(Scsi, Virtio)),
+		table.Entry("integer", []validations.CommonTemplateValidation{{
+			Name: "cores", Rule: "integer", Path: "jsonpath::.spec.domain.cpu.cores", Min: int64Ptr(1), Max: int64Ptr(4),
+		}}),
+		table.Entry("integer quantity", []validations.CommonTemplateValidation{{
+			Name: "memory", Rule: "integer", Path: "jsonpath::.spec.domain.resources.requests.memory", Min: int64Ptr(1073741824),
+		}}),
+		table.Entry("string", []validations.CommonTemplateValidation{{
+			Name: "machine", Rule: "string", Path: "jsonpath::.spec.domain.machine.type", MinLength: intPtr(1), MaxLength: intPtr(3),
+		}}),
+		table.Entry("regex", []validations.CommonTemplateValidation{{
+			Name: "machine", Rule: "regex", Path: "jsonpath::.spec.domain.machine.type", Regex: "^q35$",
+		}}),
+		table.Entry("missing path", []validations.CommonTemplateValidation{{
+			Name: "sockets", Rule: "integer", Path: "jsonpath::.spec.domain.cpu.sockets", Min: int64Ptr(1),
+		}}),
+		table.Entry("failing just warning", []validations.CommonTemplateValidation{{
+			Name: "cores", Rule: "integer", Path: "jsonpath::.spec.domain.cpu.cores", Max: int64Ptr(1), JustWarning: true,
+		}}),
+	)
+
+	table.DescribeTable("fails", func(templateValidations []validations.CommonTemplateValidation, expectedErrMessage string) {
+		err := validations.NewTemplateValidations(templateValidations).Validate(newTestVM())
+		Expect(err).Should(HaveOccurred())
+		Expect(zerrors.IsErrorSoft(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(expectedErrMessage))
+	},
+		table.Entry("enum", testobjects.NewTestCommonTemplateValidations(Scsi, Sata), "scsi, sata allowed only"),
+		table.Entry("integer min", []validations.CommonTemplateValidation{{
+			Name: "cores", Rule: "integer", Path: "jsonpath::.spec.domain.cpu.cores", Min: int64Ptr(4), Message: "at least 4 cores",
+		}}, "at least 4 cores"),
+		table.Entry("integer quantity max", []validations.CommonTemplateValidation{{
+			Name: "memory", Rule: "integer", Path: "jsonpath::.spec.domain.resources.requests.memory", Max: int64Ptr(1073741824), Message: "too much memory",
+		}}, "too much memory"),
+		table.Entry("string", []validations.CommonTemplateValidation{{
+			Name: "machine", Rule: "string", Path: "jsonpath::.spec.domain.machine.type", MaxLength: intPtr(2), Message: "machine too long",
+		}}, "machine too long"),
+		table.Entry("regex", []validations.CommonTemplateValidation{{
+			Name: "machine", Rule: "regex", Path: "jsonpath::.spec.domain.machine.type", Regex: "^pc-.*",
+		}}, "does not satisfy regex rule"),
+		table.Entry("invalid path", []validations.CommonTemplateValidation{{
+			Name: "cores", Rule: "integer", Path: ".spec.template.spec.domain.cpu.cores", Message: "invalid cores",
+		}}, "invalid cores"),
+	)
+})
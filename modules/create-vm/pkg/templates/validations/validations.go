@@ -5,7 +5,10 @@ import (
 )
 
 const (
-	diskBusJSONPath = "jsonpath::.spec.domain.devices.disks[*].disk.bus"
+	diskBusJSONPath  = "jsonpath::.spec.domain.devices.disks[*].disk.bus"
+	cdromBusJSONPath = "jsonpath::.spec.domain.devices.disks[*].cdrom.bus"
+
+	interfaceModelJSONPath = "jsonpath::.spec.domain.devices.interfaces[*].model"
 )
 
 const (
@@ -15,16 +18,18 @@ const (
 )
 
 var defaultDiskBus = diskBusVirtIO
+var defaultCDRomBus = diskBusSATA
+var preferredInterfaceModel = "virtio"
 
 type CommonTemplateValidation struct {
 	Name        string   // Identifier of the rule. Must be unique among all the rules attached to a template
 	Rule        string   // Validation rule name. One of integer|string|regex|enum
 	Path        string   // jsonpath of the field whose value is going to be evaluated.
 	Message     string   // User-friendly string message describing the failure, should the rule not be satisfied
-	Min         int      // For 'integer' rule
-	Max         int      // For 'integer' rule
-	MinLength   int      // For 'string' rule
-	MaxLength   int      // For 'string' rule
+	Min         *int64   // For 'integer' rule
+	Max         *int64   // For 'integer' rule
+	MinLength   *int     // For 'string' rule
+	MaxLength   *int     // For 'string' rule
 	Regex       string   // For 'regex' rule
 	Values      []string // For 'enum' rule
 	JustWarning bool
@@ -71,6 +76,49 @@ func (t *TemplateValidations) GetDefaultDiskBus() string {
 	return defaultDiskBus
 }
 
+// GetDefaultCDRomBus never returns virtio bus, because it is not supported for CD-ROMs
+func (t *TemplateValidations) GetDefaultCDRomBus() string {
+	recommendedBuses := t.getRecommendedBuses(cdromBusJSONPath)
+	delete(recommendedBuses, diskBusVirtIO)
+
+	if len(recommendedBuses) == 0 || recommendedBuses[defaultCDRomBus] {
+		return defaultCDRomBus
+	}
+	for bus := range recommendedBuses {
+		return bus
+	}
+
+	return defaultCDRomBus
+}
+
+// GetDefaultInterfaceModel returns an empty model (virtio by default in KubeVirt) if the template does not restrict the models
+func (t *TemplateValidations) GetDefaultInterfaceModel() string {
+	allowedModels := t.getAllowedEnumValues(interfaceModelJSONPath, false)
+	recommendedModels := t.getAllowedEnumValues(interfaceModelJSONPath, true)
+
+	if len(allowedModels) > 0 && len(recommendedModels) > 0 {
+		var allowedRecommendedModels []string
+		for _, model := range recommendedModels {
+			if containsString(allowedModels, model) {
+				allowedRecommendedModels = append(allowedRecommendedModels, model)
+			}
+		}
+		recommendedModels = allowedRecommendedModels
+	}
+
+	for _, models := range [][]string{recommendedModels, allowedModels} {
+		if len(models) == 0 {
+			continue
+		}
+		if containsString(models, preferredInterfaceModel) {
+			return preferredInterfaceModel
+		}
+		return models[0]
+	}
+
+	return ""
+}
+
 func (t *TemplateValidations) getAllowedBuses(jsonPath string, justWarning bool) map[string]bool {
 	allowedBuses := t.getAllowedEnumValues(jsonPath, justWarning)
 	if len(allowedBuses) == 0 {
@@ -116,3 +164,12 @@ func (t *TemplateValidations) getAllowedEnumValues(jsonPath string, justWarning
 	}
 	return relevantValidations
 }
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
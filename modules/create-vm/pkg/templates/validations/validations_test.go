@@ -20,6 +20,40 @@ var _ = Describe("Validations", func() {
 		table.Entry("two with virtio", testobjects.NewTestCommonTemplateValidations(Scsi, Virtio), Virtio),
 	)
 
+	table.DescribeTable("gets default CD-ROM bus", func(templateValidations []validations.CommonTemplateValidation, expectedBus string) {
+		Expect(validations.NewTemplateValidations(templateValidations).GetDefaultCDRomBus()).To(Equal(expectedBus))
+	},
+		table.Entry("nil", nil, Sata),
+		table.Entry("disk validations", testobjects.NewTestCommonTemplateValidations(Virtio), Sata),
+		table.Entry("cdrom validations", []validations.CommonTemplateValidation{{
+			Name:   "cdrom-bus",
+			Rule:   "enum",
+			Path:   "jsonpath::.spec.domain.devices.disks[*].cdrom.bus",
+			Values: []string{Scsi},
+		}}, Scsi),
+	)
+
+	table.DescribeTable("gets default interface model", func(templateValidations []validations.CommonTemplateValidation, expectedModel string) {
+		Expect(validations.NewTemplateValidations(templateValidations).GetDefaultInterfaceModel()).To(Equal(expectedModel))
+	},
+		table.Entry("nil", nil, ""),
+		table.Entry("disk validations", testobjects.NewTestCommonTemplateValidations(Virtio), ""),
+		table.Entry("allowed models", []validations.CommonTemplateValidation{
+			newInterfaceModelValidation(false, "e1000e", "virtio"),
+		}, "virtio"),
+		table.Entry("allowed model", []validations.CommonTemplateValidation{
+			newInterfaceModelValidation(false, "e1000e"),
+		}, "e1000e"),
+		table.Entry("recommended model", []validations.CommonTemplateValidation{
+			newInterfaceModelValidation(false, "e1000e", "virtio"),
+			newInterfaceModelValidation(true, "e1000e"),
+		}, "e1000e"),
+		table.Entry("recommended model which is not allowed", []validations.CommonTemplateValidation{
+			newInterfaceModelValidation(false, "e1000e"),
+			newInterfaceModelValidation(true, "virtio"),
+		}, "e1000e"),
+	)
+
 	It("gets prefered bus", func() {
 		allowed := testobjects.NewTestCommonTemplateValidations(Scsi, Sata, Virtio)
 		otherAllowed := testobjects.NewTestCommonTemplateValidations(Virtio)
@@ -35,3 +69,13 @@ var _ = Describe("Validations", func() {
 		Expect(validations.NewTemplateValidations(finalTemplateValidations).GetDefaultDiskBus()).To(Equal(Sata))
 	})
 })
+
+func newInterfaceModelValidation(justWarning bool, models ...string) validations.CommonTemplateValidation {
+	return validations.CommonTemplateValidation{
+		Name:        "interface-model",
+		Rule:        "enum",
+		Path:        "jsonpath::.spec.domain.devices.interfaces[*].model",
+		Values:      models,
+		JustWarning: justWarning,
+	}
+}
@@ -0,0 +1,50 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
+)
+
+type BootSource struct {
+	Kind      constants.BootSourceKind
+	Namespace string
+	Name      string
+}
+
+// parseBootSource parses KIND:[NS/]NAME format, namespace is empty if not specified
+func parseBootSource(input string) (*BootSource, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil
+	}
+
+	split := strings.SplitN(input, bootSourceSep, 2)
+	if len(split) != 2 {
+		return nil, fmt.Errorf("boot source \"%v\" should be in DataSource:[NS/]NAME, DataVolume:[NS/]NAME or PVC:[NS/]NAME format", input)
+	}
+
+	bootSource := &BootSource{Kind: constants.BootSourceKind(strings.TrimSpace(split[0]))}
+
+	switch bootSource.Kind {
+	case constants.DataSourceBootSourceKind, constants.DataVolumeBootSourceKind, constants.PVCBootSourceKind:
+	default:
+		return nil, fmt.Errorf("unknown boot source kind %v in \"%v\", only DataSource|DataVolume|PVC is allowed", split[0], input)
+	}
+
+	namespacedName := strings.Split(strings.TrimSpace(split[1]), "/")
+	switch len(namespacedName) {
+	case 1:
+		bootSource.Name = strings.TrimSpace(namespacedName[0])
+	case 2:
+		bootSource.Namespace = strings.TrimSpace(namespacedName[0])
+		bootSource.Name = strings.TrimSpace(namespacedName[1])
+	}
+
+	if bootSource.Name == "" || (len(namespacedName) == 2 && bootSource.Namespace == "") || len(namespacedName) > 2 {
+		return nil, fmt.Errorf("boot source \"%v\" should be in DataSource:[NS/]NAME, DataVolume:[NS/]NAME or PVC:[NS/]NAME format", input)
+	}
+
+	return bootSource, nil
+}
@@ -2,38 +2,132 @@ package parse
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
+	lab "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants/labels"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zconstants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
 )
 
 const (
-	vmManifestOptionName        = "vm-manifest"
-	vmNamespaceOptionName       = "vm-namespace"
-	templateNameOptionName      = "template-name"
-	templateNamespaceOptionName = "template-namespace"
-	templateParamsOptionName    = "template-params"
+	vmManifestOptionName                 = "vm-manifest"
+	vmNamespaceOptionName                = "vm-namespace"
+	vmNameOptionName                     = "vm-name"
+	instancetypeOptionName               = "instancetype"
+	preferenceOptionName                 = "preference"
+	templateNameOptionName               = "template-name"
+	templateNamespaceOptionName          = "template-namespace"
+	templateOSOptionName                 = "template-os"
+	templateWorkloadOptionName           = "template-workload"
+	templateFlavorOptionName             = "template-flavor"
+	templateParamsOptionName             = "template-params"
+	dryRunOptionName                     = "dry-run"
+	ifExistsOptionName                   = "if-exists"
+	waitForReadyOptionName               = "wait-for-ready"
+	timeoutOptionName                    = "timeout"
+	cloudInitTypeOptionName              = "cloud-init-type"
+	cloudInitUserDataOptionName          = "cloud-init-user-data"
+	cloudInitUserDataSecretOptionName    = "cloud-init-user-data-secret"
+	cloudInitNetworkDataOptionName       = "cloud-init-network-data"
+	cloudInitNetworkDataSecretOptionName = "cloud-init-network-data-secret"
+	sysprepConfigMapOptionName           = "sysprep-config-map"
+	sysprepSecretOptionName              = "sysprep-secret"
+	sshPropagationMethodOptionName       = "ssh-propagation-method"
+	networksOptionName                   = "networks"
+	cpuSocketsOptionName                 = "cpu-sockets"
+	cpuCoresOptionName                   = "cpu-cores"
+	cpuThreadsOptionName                 = "cpu-threads"
+	memoryOptionName                     = "memory"
+	memoryLimitOptionName                = "memory-limit"
+	nodeSelectorOptionName               = "node-selector"
+	tolerationsOptionName                = "tolerations"
+	affinityOptionName                   = "affinity"
+	evictionStrategyOptionName           = "eviction-strategy"
+	bootSourceOptionName                 = "boot-source"
+	rootDiskSizeOptionName               = "root-disk-size"
+	dvsOptionName                        = "dvs"
+	ownDVsOptionName                     = "own-dvs"
+	pvcsOptionName                       = "pvcs"
+	ownPVCsOptionName                    = "own-pvcs"
+	countOptionName                      = "count"
+	namePatternOptionName                = "name-pattern"
+	concurrencyOptionName                = "concurrency"
+	sshUsersOptionName                   = "ssh-users"
+	outputJSONPathOptionName             = "output-jsonpath"
 )
 
 const templateParamSep = ":"
 const volumesSep = ":"
+const networkSep = ":"
+const nodeSelectorSep = ":"
+const bootSourceSep = ":"
+const matcherSep = ":"
 
 type CLIOptions struct {
-	TemplateName              string            `arg:"--template-name,env:TEMPLATE_NAME" placeholder:"NAME" help:"Name of a template to create VM from"`
-	TemplateNamespace         string            `arg:"--template-namespace,env:TEMPLATE_NAMESPACE" placeholder:"NAMESPACE" help:"Namespace of a template to create VM from"`
-	TemplateParams            []string          `arg:"--template-params" placeholder:"KEY1:VAL1 KEY2:VAL2" help:"Template params to pass when processing the template manifest"`
-	VirtualMachineManifest    string            `arg:"--vm-manifest,env:VM_MANIFEST" placeholder:"MANIFEST" help:"YAML manifest of a VirtualMachine resource to be created (can be set by VM_MANIFEST env variable)."`
-	VirtualMachineNamespace   string            `arg:"--vm-namespace,env:VM_NAMESPACE" placeholder:"NAMESPACE" help:"Namespace where to create the VM"`
-	DataVolumes               []string          `arg:"--dvs" placeholder:"DV1 VOLUME_NAME:DV2 DV3" help:"Add DataVolumes to VM Volumes. Replaces a particular volume if in VOLUME_NAME:DV_NAME format."`
-	OwnDataVolumes            []string          `arg:"--own-dvs" placeholder:"DV1 VOLUME_NAME:DV2 DV3" help:"Add DataVolumes to VM Volumes and add VM to DV ownerReferences. These DVs will be deleted once the created VM gets deleted. Replaces a particular volume if in VOLUME_NAME:DV_NAME format."`
-	PersistentVolumeClaims    []string          `arg:"--pvcs" placeholder:"PVC1 VOLUME_NAME:PVC2 PVC3" help:"Add PersistentVolumeClaims to VM Volumes. Replaces a particular volume if in PVC_NAME:DV_NAME format."`
-	OwnPersistentVolumeClaims []string          `arg:"--own-pvcs" placeholder:"PVC1  VOLUME_NAME:PVC2 PVC3" help:"Add PersistentVolumeClaims to VM Volumes and add VM to PVC ownerReferences. These PVCs will be deleted once the created VM gets deleted. Replaces a particular volume if in PVC_NAME:DV_NAME format."`
-	StartVM                   string            `arg:"--start-vm,env:START_VM" help:"Start vm after creation"`
-	Output                    output.OutputType `arg:"-o" placeholder:"FORMAT" help:"Output format. One of: yaml|json"`
-	Debug                     bool              `arg:"--debug" help:"Sets DEBUG log level"`
+	TemplateName               string            `arg:"--template-name,env:TEMPLATE_NAME" placeholder:"NAME" help:"Name of a template to create VM from"`
+	TemplateNamespace          string            `arg:"--template-namespace,env:TEMPLATE_NAMESPACE" placeholder:"NAMESPACE" help:"Namespace of a template to create VM from"`
+	TemplateOS                 string            `arg:"--template-os,env:TEMPLATE_OS" placeholder:"OS" help:"Find a template to create VM from by its os.template.kubevirt.io label, eg. fedora33. The newest revision is used when several templates match."`
+	TemplateWorkload           string            `arg:"--template-workload,env:TEMPLATE_WORKLOAD" placeholder:"WORKLOAD" help:"Find a template to create VM from by its workload.template.kubevirt.io label, eg. server"`
+	TemplateFlavor             string            `arg:"--template-flavor,env:TEMPLATE_FLAVOR" placeholder:"FLAVOR" help:"Find a template to create VM from by its flavor.template.kubevirt.io label, eg. small"`
+	TemplateParams             []string          `arg:"--template-params" placeholder:"KEY1:VAL1 KEY2:VAL2" help:"Template params to pass when processing the template manifest"`
+	VirtualMachineManifest     string            `arg:"--vm-manifest,env:VM_MANIFEST" placeholder:"MANIFEST" help:"YAML manifest of a VirtualMachine resource to be created (can be set by VM_MANIFEST env variable)."`
+	VirtualMachineNamespace    string            `arg:"--vm-namespace,env:VM_NAMESPACE" placeholder:"NAMESPACE" help:"Namespace where to create the VM"`
+	Instancetype               string            `arg:"--instancetype,env:INSTANCETYPE" placeholder:"[KIND:]NAME" help:"Create the VM from an instancetype instead of a template or a manifest. KIND is VirtualMachineClusterInstancetype (default) or VirtualMachineInstancetype. Requires boot-source and vm-name."`
+	Preference                 string            `arg:"--preference,env:PREFERENCE" placeholder:"[KIND:]NAME" help:"Preference of the VM created from an instancetype. KIND is VirtualMachineClusterPreference (default) or VirtualMachinePreference."`
+	VirtualMachineName         string            `arg:"--vm-name,env:VM_NAME" placeholder:"NAME" help:"Name of the VM created from an instancetype"`
+	DataVolumes                []string          `arg:"--dvs" placeholder:"DV1 VOLUME_NAME:DV2 DV3" help:"Add DataVolumes to VM Volumes. Replaces a particular volume if in VOLUME_NAME:DV_NAME format."`
+	OwnDataVolumes             []string          `arg:"--own-dvs" placeholder:"DV1 VOLUME_NAME:DV2 DV3" help:"Add DataVolumes to VM Volumes and add VM to DV ownerReferences. These DVs will be deleted once the created VM gets deleted. Replaces a particular volume if in VOLUME_NAME:DV_NAME format."`
+	PersistentVolumeClaims     []string          `arg:"--pvcs" placeholder:"PVC1 VOLUME_NAME:PVC2 PVC3" help:"Add PersistentVolumeClaims to VM Volumes. Replaces a particular volume if in PVC_NAME:DV_NAME format."`
+	OwnPersistentVolumeClaims  []string          `arg:"--own-pvcs" placeholder:"PVC1  VOLUME_NAME:PVC2 PVC3" help:"Add PersistentVolumeClaims to VM Volumes and add VM to PVC ownerReferences. These PVCs will be deleted once the created VM gets deleted. Replaces a particular volume if in PVC_NAME:DV_NAME format."`
+	BootSource                 string            `arg:"--boot-source,env:BOOT_SOURCE" placeholder:"DataSource:[NS/]NAME|DataVolume:[NS/]NAME|PVC:[NS/]NAME" help:"Clone the root disk of the VM from a DataSource, a DataVolume or a PVC with a dataVolumeTemplate, so the disk is deleted together with the VM. Namespace defaults to the VM namespace."`
+	RootDiskSize               string            `arg:"--root-disk-size,env:ROOT_DISK_SIZE" placeholder:"SIZE" help:"Size of the cloned root disk, format 30Gi. Defaults to the size of the boot source."`
+	CloudInitType              string            `arg:"--cloud-init-type,env:CLOUD_INIT_TYPE" placeholder:"nocloud|configdrive" help:"Type of a cloud-init volume to add or replace. Defaults to nocloud."`
+	CloudInitUserData          string            `arg:"--cloud-init-user-data,env:CLOUD_INIT_USER_DATA" placeholder:"USER_DATA" help:"Inline cloud-init user data"`
+	CloudInitUserDataSecret    string            `arg:"--cloud-init-user-data-secret,env:CLOUD_INIT_USER_DATA_SECRET" placeholder:"SECRET" help:"Name of a Secret with cloud-init user data"`
+	CloudInitNetworkData       string            `arg:"--cloud-init-network-data,env:CLOUD_INIT_NETWORK_DATA" placeholder:"NETWORK_DATA" help:"Inline cloud-init network data"`
+	CloudInitNetworkDataSecret string            `arg:"--cloud-init-network-data-secret,env:CLOUD_INIT_NETWORK_DATA_SECRET" placeholder:"SECRET" help:"Name of a Secret with cloud-init network data"`
+	SysprepConfigMap           string            `arg:"--sysprep-config-map,env:SYSPREP_CONFIG_MAP" placeholder:"CONFIG_MAP" help:"Name of a ConfigMap with a Sysprep answer file (autounattend.xml) for Windows VMs"`
+	SysprepSecret              string            `arg:"--sysprep-secret,env:SYSPREP_SECRET" placeholder:"SECRET" help:"Name of a Secret with a Sysprep answer file (autounattend.xml) for Windows VMs"`
+	Networks                   []string          `arg:"--networks" placeholder:"NAME:pod[:BINDING] NAME:multus:[NS/]NAD[:BINDING]" help:"Add networks and their interfaces to the VM. Replaces a particular network if the name already exists. BINDING is one of bridge|masquerade|sriov and defaults to masquerade for pod network and to bridge for multus network."`
+	CPUSockets                 string            `arg:"--cpu-sockets,env:CPU_SOCKETS" placeholder:"CPU_SOCKETS" help:"Number of CPU sockets of the VM"`
+	CPUCores                   string            `arg:"--cpu-cores,env:CPU_CORES" placeholder:"CPU_CORES" help:"Number of CPU cores of the VM"`
+	CPUThreads                 string            `arg:"--cpu-threads,env:CPU_THREADS" placeholder:"CPU_THREADS" help:"Number of CPU threads of the VM"`
+	Memory                     string            `arg:"--memory,env:MEMORY" placeholder:"MEMORY" help:"Memory request of the VM, format 1Gi, 512Mi"`
+	MemoryLimit                string            `arg:"--memory-limit,env:MEMORY_LIMIT" placeholder:"MEMORY" help:"Memory limit of the VM, format 1Gi, 512Mi"`
+	NodeSelector               []string          `arg:"--node-selector" placeholder:"KEY1:VAL1 KEY2:VAL2" help:"Add labels to the VM nodeSelector. Replaces a particular label if the key already exists."`
+	Tolerations                []string          `arg:"--tolerations" placeholder:"TOLERATION1 TOLERATION2" help:"VM tolerations in json format, eg. {\"key\": \"dedicated\", \"operator\": \"Exists\", \"effect\": \"NoSchedule\"}. Replaces a particular toleration if a toleration with the same key and effect already exists."`
+	Affinity                   string            `arg:"--affinity,env:AFFINITY" placeholder:"AFFINITY" help:"VM affinity in json or yaml format. Replaces the affinity of the VM."`
+	EvictionStrategy           string            `arg:"--eviction-strategy,env:EVICTION_STRATEGY" placeholder:"LiveMigrate" help:"Eviction strategy of the VM"`
+	SSHPublicKeySecrets        []string          `arg:"--ssh-public-key-secrets" placeholder:"SECRET1 SECRET2" help:"Add public keys from these Secrets (eg. the public key Secret of generate-ssh-keys task) to the VM accessCredentials"`
+	SSHPropagationMethod       string            `arg:"--ssh-propagation-method,env:SSH_PROPAGATION_METHOD" placeholder:"cloud-init|qemu-guest-agent" help:"How the public keys are injected into the guest. cloud-init requires a configdrive cloud-init volume, which is added or converted from nocloud volume. Defaults to cloud-init."`
+	SSHUsers                   []string          `arg:"--ssh-users" placeholder:"USER1 USER2" help:"Guest users to add the public keys to. Required for qemu-guest-agent propagation method."`
+	Count                      string            `arg:"--count,env:COUNT" placeholder:"COUNT" help:"Number of VMs to create. Defaults to 1. dvs, own-dvs, pvcs and own-pvcs cannot be used when count is greater than 1."`
+	NamePattern                string            `arg:"--name-pattern,env:NAME_PATTERN" placeholder:"PATTERN" help:"Name of the created VMs, eg. my-vm-{index}. {index} is replaced by the index of the VM starting from 0, also in template params. Defaults to VM_NAME-{index} when count is greater than 1."`
+	Concurrency                string            `arg:"--concurrency,env:CONCURRENCY" placeholder:"CONCURRENCY" help:"Maximum number of VMs created at the same time. Defaults to 10."`
+	StartVM                    string            `arg:"--start-vm,env:START_VM" help:"Start vm after creation"`
+	WaitForReady               string            `arg:"--wait-for-ready,env:WAIT_FOR_READY" placeholder:"true|false" help:"Wait until the VMI is running. The VM should be started by start-vm or by its run strategy."`
+	WaitForGuestAgent          string            `arg:"--wait-for-guest-agent,env:WAIT_FOR_GUEST_AGENT" placeholder:"true|false" help:"Wait until the VMI is running and its guest agent is connected"`
+	Timeout                    string            `arg:"--timeout,env:TIMEOUT" placeholder:"DURATION" help:"Timeout for waiting for the VMI to be ready. Should be in a 3h2m1s format. Defaults to 1h."`
+	DryRun                     string            `arg:"--dry-run,env:DRY_RUN" placeholder:"none|client|server" help:"Only print the resolved VM without creating it. The server mode also submits the VM to the cluster without persisting it."`
+	IfExists                   string            `arg:"--if-exists,env:IF_EXISTS" placeholder:"fail|skip|replace|patch" help:"What to do when the VM already exists. skip keeps the existing VM, replace deletes and recreates it and patch applies the resolved VM with server-side apply."`
+	RollbackOnFailure          string            `arg:"--rollback-on-failure,env:ROLLBACK_ON_FAILURE" placeholder:"true|false" help:"Undo the changes in reverse order when any step fails: delete the created VMs, restore the patched VMs and remove the added owner references. VMs deleted by replace are not restored."`
+	Output                     output.OutputType `arg:"-o" placeholder:"FORMAT" help:"Output format. One of: yaml|json"`
+	OutputFile                 string            `arg:"--output-file,env:OUTPUT_FILE" placeholder:"PATH" help:"Write the created VM to this file, eg. in a workspace. In json format for .json files, in yaml format otherwise."`
+	OutputJSONPath             string            `arg:"--output-jsonpath,env:OUTPUT_JSONPATH" placeholder:"JSONPATH" help:"Record the created VM projected by this JSONPath expression into the output result, eg. {.spec.template.spec.domain.cpu}"`
+	MetricsFile                string            `arg:"--metrics-file,env:METRICS_FILE" placeholder:"PATH" help:"Write the durations and outcomes of the task to this file in OpenMetrics text format, eg. in a workspace."`
+	MetricsPushgatewayURL      string            `arg:"--metrics-pushgateway-url,env:METRICS_PUSHGATEWAY_URL" placeholder:"URL" help:"Push the durations and outcomes of the task to this Pushgateway, eg. http://pushgateway:9091"`
+	Debug                      bool              `arg:"--debug" help:"Sets DEBUG log level"`
+	LogFormat                  string            `arg:"--log-format,env:LOG_FORMAT" placeholder:"json|console" help:"Log format. One of: json|console. Defaults to json."`
 }
 
 func (c *CLIOptions) GetPVCNames() []string {
@@ -52,10 +146,227 @@ func (c *CLIOptions) GetOwnDVNames() []string {
 	return removeVolumePrefixes(c.OwnDataVolumes)
 }
 
+func (c *CLIOptions) GetNetworks() []Network {
+	networks, err := parseNetworks(c.Networks)
+
+	if err != nil {
+		panic(fmt.Errorf("init was not called: %v", err.Error()))
+	}
+	return networks
+}
+
+func (c *CLIOptions) GetCPUSockets() uint32 {
+	return parseCPUCount(c.CPUSockets)
+}
+
+func (c *CLIOptions) GetCPUCores() uint32 {
+	return parseCPUCount(c.CPUCores)
+}
+
+func (c *CLIOptions) GetCPUThreads() uint32 {
+	return parseCPUCount(c.CPUThreads)
+}
+
+func (c *CLIOptions) HasCPUTopology() bool {
+	return c.GetCPUSockets() > 0 || c.GetCPUCores() > 0 || c.GetCPUThreads() > 0
+}
+
+func (c *CLIOptions) GetMemory() *resource.Quantity {
+	return parseQuantity(c.Memory)
+}
+
+func (c *CLIOptions) GetMemoryLimit() *resource.Quantity {
+	return parseQuantity(c.MemoryLimit)
+}
+
+func (c *CLIOptions) GetNodeSelector() map[string]string {
+	result, err := zutils.ExtractKeysAndValuesByLastKnownKey(c.NodeSelector, nodeSelectorSep)
+
+	if err != nil {
+		panic(fmt.Errorf("init was not called: %v", err.Error()))
+	}
+	return result
+}
+
+func (c *CLIOptions) GetTolerations() []corev1.Toleration {
+	tolerations, err := parseTolerations(c.Tolerations)
+
+	if err != nil {
+		panic(fmt.Errorf("init was not called: %v", err.Error()))
+	}
+	return tolerations
+}
+
+func (c *CLIOptions) GetAffinity() *corev1.Affinity {
+	affinity, err := parseAffinity(c.Affinity)
+
+	if err != nil {
+		panic(fmt.Errorf("init was not called: %v", err.Error()))
+	}
+	return affinity
+}
+
+func (c *CLIOptions) GetEvictionStrategy() *kubevirtv1.EvictionStrategy {
+	if c.EvictionStrategy == "" {
+		return nil
+	}
+	evictionStrategy := kubevirtv1.EvictionStrategy(c.EvictionStrategy)
+	return &evictionStrategy
+}
+
+// GetBootSource returns nil if no boot source was specified
+func (c *CLIOptions) GetBootSource() *BootSource {
+	bootSource, err := parseBootSource(c.BootSource)
+
+	if err != nil {
+		panic(fmt.Errorf("init was not called: %v", err.Error()))
+	}
+	if bootSource != nil && bootSource.Namespace == "" {
+		bootSource.Namespace = c.GetVirtualMachineNamespace()
+	}
+	return bootSource
+}
+
+// GetInstancetype returns nil if no instancetype was specified
+func (c *CLIOptions) GetInstancetype() *Matcher {
+	instancetype, err := parseMatcher(c.Instancetype, constants.VirtualMachineClusterInstancetypeKind,
+		constants.VirtualMachineClusterInstancetypeKind, constants.VirtualMachineInstancetypeKind)
+
+	if err != nil {
+		panic(fmt.Errorf("init was not called: %v", err.Error()))
+	}
+	return instancetype
+}
+
+// GetPreference returns nil if no preference was specified
+func (c *CLIOptions) GetPreference() *Matcher {
+	preference, err := parseMatcher(c.Preference, constants.VirtualMachineClusterPreferenceKind,
+		constants.VirtualMachineClusterPreferenceKind, constants.VirtualMachinePreferenceKind)
+
+	if err != nil {
+		panic(fmt.Errorf("init was not called: %v", err.Error()))
+	}
+	return preference
+}
+
+func (c *CLIOptions) GetRootDiskSize() *resource.Quantity {
+	return parseQuantity(c.RootDiskSize)
+}
+
+func (c *CLIOptions) GetCount() int {
+	return parsePositiveInt(c.Count, 1)
+}
+
+func (c *CLIOptions) GetConcurrency() int {
+	return parsePositiveInt(c.Concurrency, constants.DefaultConcurrency)
+}
+
+func (c *CLIOptions) IsBatch() bool {
+	return c.GetCount() > 1
+}
+
+// GetVMName returns the name of the VM with the index. The name from a template or a manifest is used if no pattern is set.
+func (c *CLIOptions) GetVMName(name string, index int) string {
+	if c.NamePattern != "" {
+		return replaceIndex(c.NamePattern, index)
+	}
+	if c.IsBatch() && name != "" {
+		return fmt.Sprintf("%v-%v", name, index)
+	}
+	return name
+}
+
+// GetIndexedTemplateParams returns template params with the index of the VM
+func (c *CLIOptions) GetIndexedTemplateParams(index int) map[string]string {
+	result := c.GetTemplateParams()
+	for key, value := range result {
+		result[key] = replaceIndex(value, index)
+	}
+	return result
+}
+
 func (c *CLIOptions) GetStartVMFlag() bool {
 	return c.StartVM == "true"
 }
 
+func (c *CLIOptions) GetCloudInitType() constants.CloudInitType {
+	if c.CloudInitType == "" {
+		return constants.CloudInitNoCloud
+	}
+	return constants.CloudInitType(c.CloudInitType)
+}
+
+func (c *CLIOptions) HasCloudInit() bool {
+	return c.CloudInitUserData != "" || c.CloudInitUserDataSecret != "" || c.CloudInitNetworkData != "" || c.CloudInitNetworkDataSecret != ""
+}
+
+func (c *CLIOptions) GetSSHPropagationMethod() constants.SSHPropagationMethod {
+	if c.SSHPropagationMethod == "" {
+		return constants.SSHPropagationCloudInit
+	}
+	return constants.SSHPropagationMethod(c.SSHPropagationMethod)
+}
+
+func (c *CLIOptions) HasSysprep() bool {
+	return c.SysprepConfigMap != "" || c.SysprepSecret != ""
+}
+
+func (c *CLIOptions) GetWaitForGuestAgent() bool {
+	return c.WaitForGuestAgent == "true"
+}
+
+func (c *CLIOptions) GetOutputFile() string {
+	return c.OutputFile
+}
+
+func (c *CLIOptions) GetOutputJSONPath() string {
+	return c.OutputJSONPath
+}
+
+func (c *CLIOptions) GetMetricsFile() string {
+	return c.MetricsFile
+}
+
+func (c *CLIOptions) GetMetricsPushgatewayURL() string {
+	return c.MetricsPushgatewayURL
+}
+
+func (c *CLIOptions) GetRollbackOnFailure() bool {
+	return c.RollbackOnFailure == "true"
+}
+
+func (c *CLIOptions) GetWaitForReady() bool {
+	return c.WaitForReady == "true" || c.GetWaitForGuestAgent()
+}
+
+func (c *CLIOptions) GetTimeout() time.Duration {
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err == nil {
+			return timeout
+		}
+	}
+	return constants.DefaultWaitForReadyTimeout
+}
+
+func (c *CLIOptions) GetDryRun() constants.DryRunMode {
+	if c.DryRun == "" {
+		return constants.DryRunNone
+	}
+	return constants.DryRunMode(c.DryRun)
+}
+
+func (c *CLIOptions) IsDryRun() bool {
+	return c.GetDryRun() != constants.DryRunNone
+}
+
+func (c *CLIOptions) GetIfExists() constants.IfExistsMode {
+	if c.IfExists == "" {
+		return constants.IfExistsFail
+	}
+	return constants.IfExistsMode(c.IfExists)
+}
+
 func (c *CLIOptions) GetPVCDiskNamesMap() map[string]string {
 	return getDiskNameMap(zutils.ConcatStringSlices(c.OwnPersistentVolumeClaims, c.PersistentVolumeClaims))
 }
@@ -80,15 +391,47 @@ func (c *CLIOptions) GetDebugLevel() zapcore.Level {
 	return zapcore.InfoLevel
 }
 
+func (c *CLIOptions) GetLogFormat() log.LogFormat {
+	return log.LogFormat(c.LogFormat)
+}
+
+// HasTemplateSelector returns true if the template should be found by its labels instead of its name
+func (c *CLIOptions) HasTemplateSelector() bool {
+	return c.TemplateOS != "" || c.TemplateWorkload != "" || c.TemplateFlavor != ""
+}
+
+func (c *CLIOptions) GetTemplateSelector() string {
+	var selector []string
+
+	for labelPrefix, value := range map[string]string{
+		lab.TemplateOsLabel:       c.TemplateOS,
+		lab.TemplateWorkloadLabel: c.TemplateWorkload,
+		lab.TemplateFlavorLabel:   c.TemplateFlavor,
+	} {
+		if value != "" {
+			selector = append(selector, labelPrefix+"/"+value+"="+zconstants.True)
+		}
+	}
+	sort.Strings(selector)
+
+	return strings.Join(selector, ",")
+}
+
 func (c *CLIOptions) GetCreationMode() constants.CreationMode {
-	if c.VirtualMachineManifest != "" && c.TemplateName != "" {
+	hasTemplate := c.TemplateName != "" || c.HasTemplateSelector()
+	hasInstancetype := c.Instancetype != ""
+
+	if (c.VirtualMachineManifest != "" && hasTemplate) || (hasInstancetype && (c.VirtualMachineManifest != "" || hasTemplate)) {
 		return ""
 	}
+	if hasInstancetype {
+		return constants.InstancetypeCreationMode
+	}
 	if c.VirtualMachineManifest != "" {
 		return constants.VMManifestCreationMode
 	}
 
-	if c.TemplateName != "" {
+	if hasTemplate {
 		return constants.TemplateCreationMode
 	}
 
@@ -107,6 +450,10 @@ func (c *CLIOptions) GetVirtualMachineNamespace() string {
 	return c.VirtualMachineNamespace
 }
 
+func (c *CLIOptions) GetVirtualMachineName() string {
+	return c.VirtualMachineName
+}
+
 func (c *CLIOptions) Init() error {
 	if err := c.assertValidMode(); err != nil {
 		return err
@@ -116,6 +463,18 @@ func (c *CLIOptions) Init() error {
 		return err
 	}
 
+	if _, err := parseNetworks(c.Networks); err != nil {
+		return zerrors.NewMissingRequiredError("invalid %v: %v", networksOptionName, err.Error())
+	}
+
+	if err := c.assertValidCloudInitAndSysprep(); err != nil {
+		return err
+	}
+
+	if err := c.assertValidResourcesAndScheduling(); err != nil {
+		return err
+	}
+
 	if _, err := zutils.ExtractKeysAndValuesByLastKnownKey(c.TemplateParams, templateParamSep); err != nil {
 		return zerrors.NewMissingRequiredError("invalid %v: %v", templateParamsOptionName, err.Error())
 	}
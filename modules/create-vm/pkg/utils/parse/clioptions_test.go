@@ -2,6 +2,7 @@ package parse_test
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
@@ -11,6 +12,9 @@ import (
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
 )
 
 var (
@@ -24,7 +28,7 @@ var _ = Describe("CLIOptions", func() {
 		Expect(err).Should(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring(expectedErrMessage))
 	},
-		table.Entry("no mode", "one of vm-manifest, template-name should be specified", &parse.CLIOptions{}),
+		table.Entry("no mode", "one of vm-manifest, template-name, instancetype should be specified", &parse.CLIOptions{}),
 		table.Entry("both modes", "only one of vm-manifest, template-name should be specified", &parse.CLIOptions{
 			TemplateName:           "test",
 			VirtualMachineManifest: testVMManifest,
@@ -37,6 +41,53 @@ var _ = Describe("CLIOptions", func() {
 			VirtualMachineManifest: testVMManifest,
 			TemplateParams:         []string{"K1:V1"},
 		}),
+		table.Entry("useless template selector", "template-os, template-workload, template-flavor options are not applicable for vm-manifest", &parse.CLIOptions{
+			VirtualMachineManifest: testVMManifest,
+			TemplateOS:             "fedora33",
+		}),
+		table.Entry("template name and template selector", "template-os, template-workload, template-flavor options are not applicable for template-name", &parse.CLIOptions{
+			TemplateName:     "test",
+			TemplateWorkload: "server",
+		}),
+		table.Entry("instancetype and manifest", "only one of vm-manifest, template-name, instancetype should be specified", &parse.CLIOptions{
+			Instancetype:           "u1.medium",
+			VirtualMachineManifest: testVMManifest,
+		}),
+		table.Entry("instancetype and template selector", "only one of vm-manifest, template-name, instancetype should be specified", &parse.CLIOptions{
+			Instancetype: "u1.medium",
+			TemplateOS:   "fedora33",
+		}),
+		table.Entry("useless template params with instancetype", "template-namespace, template-params options are not applicable for instancetype", &parse.CLIOptions{
+			Instancetype:   "u1.medium",
+			TemplateParams: []string{"K1:V1"},
+		}),
+		table.Entry("resources with instancetype", "cpu-sockets, cpu-cores, cpu-threads, memory, memory-limit options are not applicable for instancetype", &parse.CLIOptions{
+			Instancetype: "u1.medium",
+			Memory:       "2Gi",
+		}),
+		table.Entry("invalid instancetype kind", "invalid instancetype: unknown kind VirtualMachineClusterPreference in \"VirtualMachineClusterPreference:u1.medium\", only VirtualMachineClusterInstancetype|VirtualMachineInstancetype is allowed", &parse.CLIOptions{
+			Instancetype: "VirtualMachineClusterPreference:u1.medium",
+		}),
+		table.Entry("invalid preference format", "invalid preference: \"VirtualMachinePreference:fedora:36\" should be in [KIND:]NAME format", &parse.CLIOptions{
+			Instancetype: "u1.medium",
+			Preference:   "VirtualMachinePreference:fedora:36",
+		}),
+		table.Entry("instancetype without boot source", "boot-source option is required for instancetype", &parse.CLIOptions{
+			Instancetype:       "u1.medium",
+			VirtualMachineName: "my-vm",
+		}),
+		table.Entry("instancetype without vm name", "one of vm-name, name-pattern should be specified for instancetype", &parse.CLIOptions{
+			Instancetype: "u1.medium",
+			BootSource:   "DataSource:fedora",
+		}),
+		table.Entry("preference without instancetype", "preference option requires instancetype", &parse.CLIOptions{
+			TemplateName: "test",
+			Preference:   "fedora",
+		}),
+		table.Entry("vm name without instancetype", "vm-name option is applicable only for instancetype", &parse.CLIOptions{
+			VirtualMachineManifest: testVMManifest,
+			VirtualMachineName:     "my-vm",
+		}),
 		table.Entry("invalidManifest", "could not read VM manifest", &parse.CLIOptions{
 			VirtualMachineManifest: "blabla",
 		}),
@@ -44,6 +95,170 @@ var _ = Describe("CLIOptions", func() {
 			TemplateName: "test",
 			Output:       "incorrect-fmt",
 		}),
+		table.Entry("invalid log format", "text is not a valid log format", &parse.CLIOptions{
+			TemplateName: "test",
+			LogFormat:    "text",
+		}),
+		table.Entry("invalid Pushgateway URL", "pushgateway:9091 is not a valid Pushgateway URL", &parse.CLIOptions{
+			TemplateName:          "test",
+			MetricsPushgatewayURL: "pushgateway:9091",
+		}),
+		table.Entry("invalid dry run", "invalid dry-run all, only none|client|server is allowed", &parse.CLIOptions{
+			TemplateName: "test",
+			DryRun:       "all",
+		}),
+		table.Entry("invalid timeout", "could not parse timeout", &parse.CLIOptions{
+			TemplateName: "test",
+			Timeout:      "1 hour",
+		}),
+		table.Entry("wait for ready with dry run", "wait-for-ready option is not applicable for dry-run", &parse.CLIOptions{
+			TemplateName:      "test",
+			WaitForGuestAgent: "true",
+			DryRun:            "client",
+		}),
+		table.Entry("invalid cloud init type", "invalid cloud-init-type cloudbase, only nocloud|configdrive is allowed", &parse.CLIOptions{
+			TemplateName:  "test",
+			CloudInitType: "cloudbase",
+		}),
+		table.Entry("both cloud init user data", "only one of cloud-init-user-data, cloud-init-user-data-secret should be specified", &parse.CLIOptions{
+			TemplateName:            "test",
+			CloudInitUserData:       "#cloud-config",
+			CloudInitUserDataSecret: "user-data",
+		}),
+		table.Entry("both cloud init network data", "only one of cloud-init-network-data, cloud-init-network-data-secret should be specified", &parse.CLIOptions{
+			TemplateName:               "test",
+			CloudInitUserData:          "#cloud-config",
+			CloudInitNetworkData:       "version: 1",
+			CloudInitNetworkDataSecret: "network-data",
+		}),
+		table.Entry("cloud init without user data", "one of cloud-init-user-data, cloud-init-user-data-secret should be specified", &parse.CLIOptions{
+			TemplateName:         "test",
+			CloudInitNetworkData: "version: 1",
+		}),
+		table.Entry("both sysprep sources", "only one of sysprep-config-map, sysprep-secret should be specified", &parse.CLIOptions{
+			TemplateName:     "test",
+			SysprepConfigMap: "sysprep",
+			SysprepSecret:    "sysprep",
+		}),
+		table.Entry("invalid ssh propagation method", "invalid ssh-propagation-method config-drive, only cloud-init|qemu-guest-agent is allowed", &parse.CLIOptions{
+			TemplateName:         "test",
+			SSHPropagationMethod: "config-drive",
+		}),
+		table.Entry("ssh cloud-init propagation with no cloud", "ssh-propagation-method cloud-init requires configdrive cloud-init volume", &parse.CLIOptions{
+			TemplateName:        "test",
+			SSHPublicKeySecrets: []string{"public-key"},
+			CloudInitType:       "nocloud",
+			CloudInitUserData:   "#cloud-config",
+		}),
+		table.Entry("ssh qemu guest agent propagation without users", "ssh-users option is required for ssh-propagation-method qemu-guest-agent", &parse.CLIOptions{
+			TemplateName:         "test",
+			SSHPublicKeySecrets:  []string{"public-key"},
+			SSHPropagationMethod: "qemu-guest-agent",
+		}),
+		table.Entry("invalid network format", "invalid networks: network \"nic1\" should be in NAME:pod[:BINDING] or NAME:multus:[NS/]NAD[:BINDING] format", &parse.CLIOptions{
+			TemplateName: "test",
+			Networks:     []string{"nic1"},
+		}),
+		table.Entry("invalid network type", "invalid networks: unknown network type genie in \"nic1:genie\", only pod|multus is allowed", &parse.CLIOptions{
+			TemplateName: "test",
+			Networks:     []string{"nic1:genie"},
+		}),
+		table.Entry("missing multus network name", "invalid networks: network \"nic1:multus\" should be in NAME:multus:[NS/]NAD[:BINDING] format", &parse.CLIOptions{
+			TemplateName: "test",
+			Networks:     []string{"nic1:multus"},
+		}),
+		table.Entry("invalid binding", "invalid networks: unknown binding slirp in \"default:pod:slirp\", only bridge|masquerade|sriov is allowed", &parse.CLIOptions{
+			TemplateName: "test",
+			Networks:     []string{"default:pod:slirp"},
+		}),
+		table.Entry("masquerade binding for multus", "invalid networks: masquerade binding in \"nic1:multus:nad:masquerade\" is allowed only for pod network", &parse.CLIOptions{
+			TemplateName: "test",
+			Networks:     []string{"nic1:multus:nad:masquerade"},
+		}),
+		table.Entry("sriov binding for pod", "invalid networks: sriov binding in \"default:pod:sriov\" is allowed only for multus network", &parse.CLIOptions{
+			TemplateName: "test",
+			Networks:     []string{"default:pod:sriov"},
+		}),
+		table.Entry("invalid cpu cores", "invalid cpu-cores two, should be a positive number", &parse.CLIOptions{
+			TemplateName: "test",
+			CPUCores:     "two",
+		}),
+		table.Entry("zero cpu sockets", "invalid cpu-sockets 0, should be a positive number", &parse.CLIOptions{
+			TemplateName: "test",
+			CPUSockets:   "0",
+		}),
+		table.Entry("invalid memory", "could not parse memory: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'", &parse.CLIOptions{
+			TemplateName: "test",
+			Memory:       "2 gigs",
+		}),
+		table.Entry("memory limit lower than memory", "memory-limit 1Gi should not be lower than memory 2Gi", &parse.CLIOptions{
+			TemplateName: "test",
+			Memory:       "2Gi",
+			MemoryLimit:  "1Gi",
+		}),
+		table.Entry("invalid node selector", "invalid node-selector: no key found before \"zone\"; pair should be in \"KEY:VAL\" format", &parse.CLIOptions{
+			TemplateName: "test",
+			NodeSelector: []string{"zone"},
+		}),
+		table.Entry("invalid tolerations", "invalid tolerations: could not parse toleration {\"keys\": \"dedicated\"}: error unmarshaling JSON: while decoding JSON: json: unknown field \"keys\"", &parse.CLIOptions{
+			TemplateName: "test",
+			Tolerations:  []string{`{"keys": "dedicated"}`},
+		}),
+		table.Entry("invalid affinity", "could not parse affinity: error converting YAML to JSON: yaml: line 1: did not find expected node content", &parse.CLIOptions{
+			TemplateName: "test",
+			Affinity:     "{[",
+		}),
+		table.Entry("invalid boot source format", "invalid boot-source: boot source \"fedora\" should be in DataSource:[NS/]NAME, DataVolume:[NS/]NAME or PVC:[NS/]NAME format", &parse.CLIOptions{
+			TemplateName: "test",
+			BootSource:   "fedora",
+		}),
+		table.Entry("invalid boot source namespace", "invalid boot-source: boot source \"PVC:/fedora\" should be in DataSource:[NS/]NAME, DataVolume:[NS/]NAME or PVC:[NS/]NAME format", &parse.CLIOptions{
+			TemplateName: "test",
+			BootSource:   "PVC:/fedora",
+		}),
+		table.Entry("invalid boot source kind", "invalid boot-source: unknown boot source kind Image in \"Image:fedora\", only DataSource|DataVolume|PVC is allowed", &parse.CLIOptions{
+			TemplateName: "test",
+			BootSource:   "Image:fedora",
+		}),
+		table.Entry("root disk size without boot source", "root-disk-size option requires boot-source", &parse.CLIOptions{
+			TemplateName: "test",
+			RootDiskSize: "30Gi",
+		}),
+		table.Entry("invalid root disk size", "could not parse root-disk-size", &parse.CLIOptions{
+			TemplateName: "test",
+			BootSource:   "PVC:fedora",
+			RootDiskSize: "30 gigs",
+		}),
+		table.Entry("invalid eviction strategy", "invalid eviction-strategy None, only LiveMigrate is allowed", &parse.CLIOptions{
+			TemplateName:     "test",
+			EvictionStrategy: "None",
+		}),
+		table.Entry("batch with volumes", "dvs, own-dvs, pvcs, own-pvcs options are not applicable when count is greater than 1", &parse.CLIOptions{
+			TemplateName:              "test",
+			Count:                     "2",
+			OwnPersistentVolumeClaims: []string{"pvc1"},
+		}),
+		table.Entry("invalid count", "invalid count many, should be a positive number", &parse.CLIOptions{
+			TemplateName: "test",
+			Count:        "many",
+		}),
+		table.Entry("zero concurrency", "invalid concurrency 0, should be a positive number", &parse.CLIOptions{
+			TemplateName: "test",
+			Concurrency:  "0",
+		}),
+		table.Entry("name pattern without index", "name-pattern should contain {index} when count is greater than 1", &parse.CLIOptions{
+			TemplateName: "test",
+			Count:        "3",
+			NamePattern:  "my-vm",
+		}),
+		table.Entry("invalid output jsonpath", "invalid output-jsonpath: could not parse {.metadata.name JSONPath: unclosed action", &parse.CLIOptions{
+			TemplateName:   "test",
+			OutputJSONPath: "{.metadata.name",
+		}),
+		table.Entry("invalid if exists", "invalid if-exists update, only fail|skip|replace|patch is allowed", &parse.CLIOptions{
+			TemplateName: "test",
+			IfExists:     "update",
+		}),
 		table.Entry("invalid template params 1", "invalid template-params: no key found before \"V1\"; pair should be in \"KEY:VAL\" format", &parse.CLIOptions{
 			TemplateName:   "test",
 			TemplateParams: []string{"V1", "K2=V2"},
@@ -80,6 +295,39 @@ var _ = Describe("CLIOptions", func() {
 			"GetDebugLevel":              zapcore.InfoLevel,
 			"GetCreationMode":            constants.TemplateCreationMode,
 			"GetStartVMFlag":             false,
+			"GetDryRun":                  constants.DryRunNone,
+			"IsDryRun":                   false,
+			"GetIfExists":                constants.IfExistsFail,
+			"GetWaitForReady":            false,
+			"GetWaitForGuestAgent":       false,
+			"GetCloudInitType":           constants.CloudInitNoCloud,
+			"HasCloudInit":               false,
+			"HasSysprep":                 false,
+			"GetSSHPropagationMethod":    constants.SSHPropagationCloudInit,
+			"GetNetworks":                []parse.Network{},
+			"HasTemplateSelector":        false,
+			"GetTemplateSelector":        "",
+			"GetCPUSockets":              uint32(0),
+			"HasCPUTopology":             false,
+			"GetMemory":                  (*resource.Quantity)(nil),
+			"GetMemoryLimit":             (*resource.Quantity)(nil),
+			"GetNodeSelector":            map[string]string{},
+			"GetTolerations":             []corev1.Toleration{},
+			"GetAffinity":                (*corev1.Affinity)(nil),
+			"GetEvictionStrategy":        (*kubevirtv1.EvictionStrategy)(nil),
+			"GetBootSource":              (*parse.BootSource)(nil),
+			"GetInstancetype":            (*parse.Matcher)(nil),
+			"GetPreference":              (*parse.Matcher)(nil),
+			"GetRootDiskSize":            (*resource.Quantity)(nil),
+			"GetTimeout":                 time.Hour,
+			"GetCount":                   1,
+			"GetConcurrency":             constants.DefaultConcurrency,
+			"IsBatch":                    false,
+			"GetRollbackOnFailure":       false,
+			"GetOutputFile":              "",
+			"GetOutputJSONPath":          "",
+			"GetMetricsFile":             "",
+			"GetMetricsPushgatewayURL":   "",
 		}),
 		table.Entry("handles template cli arguments", &parse.CLIOptions{
 			TemplateName:              "test",
@@ -93,6 +341,22 @@ var _ = Describe("CLIOptions", func() {
 			OwnDataVolumes:            []string{"mydisk3:dv3", "dv4", "mydisk4:dv5"},
 			Debug:                     true,
 			StartVM:                   "true",
+			DryRun:                    "client",
+			IfExists:                  "replace",
+			Networks:                  []string{"default:pod", " nic1 : multus : my-ns/my-nad ", "nic2:multus:sriov-nad:sriov", "nic3:pod:bridge"},
+			CloudInitType:             "configdrive",
+			CloudInitUserDataSecret:   "user-data",
+			SysprepSecret:             "sysprep",
+			CPUSockets:                "2",
+			CPUCores:                  " 4 ",
+			Memory:                    "2Gi",
+			MemoryLimit:               " 4Gi",
+			NodeSelector:              []string{"zone:eu-1", "kubernetes.io/arch:amd64"},
+			Tolerations:               []string{`{"key": "dedicated", "operator": "Equal", "value": "vms", "effect": "NoSchedule"}`},
+			Affinity:                  "nodeAffinity:\n  requiredDuringSchedulingIgnoredDuringExecution:\n    nodeSelectorTerms:\n    - matchExpressions:\n      - key: zone\n        operator: Exists\n",
+			EvictionStrategy:          "LiveMigrate",
+			BootSource:                "DataSource: os-images/fedora ",
+			RootDiskSize:              "30Gi",
 		}, map[string]interface{}{
 			"GetTemplateNamespace":       defaultNS,
 			"GetVirtualMachineNamespace": defaultNS,
@@ -120,6 +384,43 @@ var _ = Describe("CLIOptions", func() {
 			"GetDebugLevel":   zapcore.DebugLevel,
 			"GetCreationMode": constants.TemplateCreationMode,
 			"GetStartVMFlag":  true,
+			"GetDryRun":       constants.DryRunClient,
+			"IsDryRun":        true,
+			"GetIfExists":     constants.IfExistsReplace,
+			"GetNetworks": []parse.Network{
+				{Name: "default", Type: constants.PodNetworkType, Binding: constants.MasqueradeInterfaceBinding},
+				{Name: "nic1", Type: constants.MultusNetworkType, MultusNetworkName: "my-ns/my-nad", Binding: constants.BridgeInterfaceBinding},
+				{Name: "nic2", Type: constants.MultusNetworkType, MultusNetworkName: "sriov-nad", Binding: constants.SRIOVInterfaceBinding},
+				{Name: "nic3", Type: constants.PodNetworkType, Binding: constants.BridgeInterfaceBinding},
+			},
+			"GetCloudInitType": constants.CloudInitConfigDrive,
+			"HasCloudInit":     true,
+			"HasSysprep":       true,
+			"GetCPUSockets":    uint32(2),
+			"GetCPUCores":      uint32(4),
+			"GetCPUThreads":    uint32(0),
+			"HasCPUTopology":   true,
+			"GetMemory":        quantityPtr("2Gi"),
+			"GetMemoryLimit":   quantityPtr("4Gi"),
+			"GetNodeSelector": map[string]string{
+				"zone":               "eu-1",
+				"kubernetes.io/arch": "amd64",
+			},
+			"GetTolerations": []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "vms", Effect: corev1.TaintEffectNoSchedule},
+			},
+			"GetAffinity": &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpExists}},
+						}},
+					},
+				},
+			},
+			"GetEvictionStrategy": evictionStrategyPtr(kubevirtv1.EvictionStrategyLiveMigrate),
+			"GetBootSource":       &parse.BootSource{Kind: constants.DataSourceBootSourceKind, Namespace: "os-images", Name: "fedora"},
+			"GetRootDiskSize":     quantityPtr("30Gi"),
 		}),
 		table.Entry("handles vm cli arguments", &parse.CLIOptions{
 			VirtualMachineManifest:    testVMManifest,
@@ -131,6 +432,14 @@ var _ = Describe("CLIOptions", func() {
 			OwnDataVolumes:            []string{"dv3"},
 			Debug:                     true,
 			StartVM:                   "false",
+			DryRun:                    "none",
+			IfExists:                  "skip",
+			BootSource:                "PVC:fedora-golden",
+			SSHPublicKeySecrets:       []string{"public-key"},
+			SSHPropagationMethod:      "qemu-guest-agent",
+			SSHUsers:                  []string{"fedora"},
+			WaitForGuestAgent:         "true",
+			Timeout:                   " 10m ",
 		}, map[string]interface{}{
 			"GetTemplateNamespace":       "",
 			"GetVirtualMachineNamespace": defaultNS,
@@ -149,10 +458,68 @@ var _ = Describe("CLIOptions", func() {
 				"dv2":     "dv2",
 				"dv3":     "dv3",
 			},
-			"GetTemplateParams": map[string]string{},
-			"GetDebugLevel":     zapcore.DebugLevel,
-			"GetCreationMode":   constants.VMManifestCreationMode,
-			"GetStartVMFlag":    false,
+			"GetTemplateParams":       map[string]string{},
+			"GetDebugLevel":           zapcore.DebugLevel,
+			"GetCreationMode":         constants.VMManifestCreationMode,
+			"GetStartVMFlag":          false,
+			"GetDryRun":               constants.DryRunNone,
+			"IsDryRun":                false,
+			"GetIfExists":             constants.IfExistsSkip,
+			"GetBootSource":           &parse.BootSource{Kind: constants.PVCBootSourceKind, Namespace: defaultNS, Name: "fedora-golden"},
+			"GetSSHPropagationMethod": constants.SSHPropagationQemuGuestAgent,
+			"GetWaitForReady":         true,
+			"GetWaitForGuestAgent":    true,
+			"GetTimeout":              10 * time.Minute,
+		}),
+		table.Entry("handles instancetype cli arguments", &parse.CLIOptions{
+			Instancetype:            " u1.medium",
+			Preference:              "virtualmachinepreference:fedora ",
+			VirtualMachineName:      " my-vm ",
+			VirtualMachineNamespace: defaultNS,
+			BootSource:              "DataVolume:os-images/fedora",
+			Count:                   "2",
+		}, map[string]interface{}{
+			"GetCreationMode":            constants.InstancetypeCreationMode,
+			"GetVirtualMachineNamespace": defaultNS,
+			"GetVirtualMachineName":      "my-vm",
+			"GetInstancetype":            &parse.Matcher{Kind: constants.VirtualMachineClusterInstancetypeKind, Name: "u1.medium"},
+			"GetPreference":              &parse.Matcher{Kind: constants.VirtualMachinePreferenceKind, Name: "fedora"},
+			"GetBootSource":              &parse.BootSource{Kind: constants.DataVolumeBootSourceKind, Namespace: "os-images", Name: "fedora"},
+			"GetTemplateNamespace":       "",
+		}),
+		table.Entry("handles template selector", &parse.CLIOptions{
+			TemplateOS:              "fedora33",
+			TemplateWorkload:        " server ",
+			TemplateFlavor:          "small",
+			VirtualMachineNamespace: defaultNS,
+		}, map[string]interface{}{
+			"GetTemplateNamespace":       "",
+			"GetVirtualMachineNamespace": defaultNS,
+			"GetCreationMode":            constants.TemplateCreationMode,
+			"HasTemplateSelector":        true,
+			"GetTemplateSelector":        "flavor.template.kubevirt.io/small=true,os.template.kubevirt.io/fedora33=true,workload.template.kubevirt.io/server=true",
+		}),
+		table.Entry("handles batch", &parse.CLIOptions{
+			TemplateName:            "test",
+			TemplateNamespace:       defaultNS,
+			VirtualMachineNamespace: defaultNS,
+			Count:                   " 3",
+			NamePattern:             "my-vm-{index}",
+			Concurrency:             "2 ",
+			RollbackOnFailure:       " true",
+			OutputFile:              " /workspace/vms.json",
+			OutputJSONPath:          "{.metadata.name} ",
+			MetricsFile:             "/workspace/metrics.txt ",
+			MetricsPushgatewayURL:   " http://pushgateway:9091",
+		}, map[string]interface{}{
+			"GetCount":                 3,
+			"GetConcurrency":           2,
+			"IsBatch":                  true,
+			"GetRollbackOnFailure":     true,
+			"GetOutputFile":            "/workspace/vms.json",
+			"GetOutputJSONPath":        "{.metadata.name}",
+			"GetMetricsFile":           "/workspace/metrics.txt",
+			"GetMetricsPushgatewayURL": "http://pushgateway:9091",
 		}),
 		table.Entry("handles trim", &parse.CLIOptions{
 			TemplateName:              "test",
@@ -162,7 +529,9 @@ var _ = Describe("CLIOptions", func() {
 			OwnPersistentVolumeClaims: []string{" pvc2", " pvc3  "},
 			DataVolumes:               []string{" dv1", "dv2"},
 			OwnDataVolumes:            []string{" dv3     "},
+			DryRun:                    " server ",
 		}, map[string]interface{}{
+			"GetDryRun":                  constants.DryRunServer,
 			"GetTemplateNamespace":       defaultNS,
 			"GetVirtualMachineNamespace": defaultNS,
 			"GetPVCNames":                []string{"pvc1"},
@@ -183,3 +552,38 @@ var _ = Describe("CLIOptions", func() {
 	)
 
 })
+
+var _ = Describe("CLIOptions batch names", func() {
+	table.DescribeTable("GetVMName returns correct name", func(options *parse.CLIOptions, name string, index int, expectedName string) {
+		Expect(options.GetVMName(name, index)).To(Equal(expectedName))
+	},
+		table.Entry("single VM", &parse.CLIOptions{}, "vm", 0, "vm"),
+		table.Entry("single VM with pattern", &parse.CLIOptions{NamePattern: "my-vm-{index}"}, "vm", 0, "my-vm-0"),
+		table.Entry("batch with pattern", &parse.CLIOptions{Count: "3", NamePattern: "my-vm-{index}"}, "vm", 2, "my-vm-2"),
+		table.Entry("batch without pattern", &parse.CLIOptions{Count: "3"}, "vm", 1, "vm-1"),
+		table.Entry("batch without name", &parse.CLIOptions{Count: "3"}, "", 1, ""),
+	)
+
+	It("GetIndexedTemplateParams replaces index", func() {
+		options := &parse.CLIOptions{
+			TemplateName:            "test",
+			TemplateNamespace:       defaultNS,
+			VirtualMachineNamespace: defaultNS,
+			TemplateParams:          []string{"NAME:vm-{index}", "DESCRIPTION:plain"},
+		}
+		Expect(options.Init()).Should(Succeed())
+		Expect(options.GetIndexedTemplateParams(4)).To(Equal(map[string]string{
+			"NAME":        "vm-4",
+			"DESCRIPTION": "plain",
+		}))
+	})
+})
+
+func quantityPtr(value string) *resource.Quantity {
+	quantity := resource.MustParse(value)
+	return &quantity
+}
+
+func evictionStrategyPtr(evictionStrategy kubevirtv1.EvictionStrategy) *kubevirtv1.EvictionStrategy {
+	return &evictionStrategy
+}
@@ -0,0 +1,43 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Matcher references an instancetype or a preference by its kind and name
+type Matcher struct {
+	Kind string
+	Name string
+}
+
+// parseMatcher parses [KIND:]NAME format, kind defaults to defaultKind and should be one of kinds
+func parseMatcher(input string, defaultKind string, kinds ...string) (*Matcher, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil
+	}
+
+	matcher := &Matcher{Kind: defaultKind}
+
+	split := strings.SplitN(input, matcherSep, 2)
+	if len(split) == 2 {
+		matcher.Kind = strings.TrimSpace(split[0])
+		matcher.Name = strings.TrimSpace(split[1])
+	} else {
+		matcher.Name = input
+	}
+
+	if matcher.Name == "" || strings.Contains(matcher.Name, matcherSep) {
+		return nil, fmt.Errorf("\"%v\" should be in [KIND:]NAME format", input)
+	}
+
+	for _, kind := range kinds {
+		if strings.EqualFold(matcher.Kind, kind) {
+			matcher.Kind = kind
+			return matcher, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown kind %v in \"%v\", only %v is allowed", matcher.Kind, input, strings.Join(kinds, "|"))
+}
@@ -0,0 +1,90 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
+)
+
+type Network struct {
+	Name              string
+	Type              constants.NetworkType
+	MultusNetworkName string
+	Binding           constants.InterfaceBinding
+}
+
+func parseNetworks(input []string) ([]Network, error) {
+	networks := make([]Network, 0, len(input))
+
+	for _, networkOption := range input {
+		network, err := parseNetwork(networkOption)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// parseNetwork parses NAME:pod[:BINDING] and NAME:multus:[NS/]NAD[:BINDING] formats
+func parseNetwork(input string) (Network, error) {
+	split := strings.Split(strings.TrimSpace(input), networkSep)
+	for i := range split {
+		split[i] = strings.TrimSpace(split[i])
+	}
+
+	if len(split) < 2 || split[0] == "" {
+		return Network{}, fmt.Errorf("network \"%v\" should be in NAME:pod[:BINDING] or NAME:multus:[NS/]NAD[:BINDING] format", input)
+	}
+
+	network := Network{
+		Name: split[0],
+		Type: constants.NetworkType(split[1]),
+	}
+
+	var binding string
+
+	switch network.Type {
+	case constants.PodNetworkType:
+		if len(split) > 3 {
+			return Network{}, fmt.Errorf("network \"%v\" should be in NAME:pod[:BINDING] format", input)
+		}
+		if len(split) == 3 {
+			binding = split[2]
+		}
+		network.Binding = constants.MasqueradeInterfaceBinding
+	case constants.MultusNetworkType:
+		if len(split) < 3 || len(split) > 4 || split[2] == "" {
+			return Network{}, fmt.Errorf("network \"%v\" should be in NAME:multus:[NS/]NAD[:BINDING] format", input)
+		}
+		network.MultusNetworkName = split[2]
+		if len(split) == 4 {
+			binding = split[3]
+		}
+		network.Binding = constants.BridgeInterfaceBinding
+	default:
+		return Network{}, fmt.Errorf("unknown network type %v in \"%v\", only pod|multus is allowed", split[1], input)
+	}
+
+	if binding != "" {
+		network.Binding = constants.InterfaceBinding(binding)
+	}
+
+	switch network.Binding {
+	case constants.BridgeInterfaceBinding:
+	case constants.MasqueradeInterfaceBinding:
+		if network.Type != constants.PodNetworkType {
+			return Network{}, fmt.Errorf("masquerade binding in \"%v\" is allowed only for pod network", input)
+		}
+	case constants.SRIOVInterfaceBinding:
+		if network.Type != constants.MultusNetworkType {
+			return Network{}, fmt.Errorf("sriov binding in \"%v\" is allowed only for multus network", input)
+		}
+	default:
+		return Network{}, fmt.Errorf("unknown binding %v in \"%v\", only bridge|masquerade|sriov is allowed", binding, input)
+	}
+
+	return network, nil
+}
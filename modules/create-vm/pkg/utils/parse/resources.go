@@ -0,0 +1,53 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+func parseCPUCount(value string) uint32 {
+	result, _ := strconv.ParseUint(value, 10, 32)
+	return uint32(result)
+}
+
+func parseQuantity(value string) *resource.Quantity {
+	if value == "" {
+		return nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		panic(fmt.Errorf("init was not called: %v", err.Error()))
+	}
+	return &quantity
+}
+
+func parseTolerations(input []string) ([]corev1.Toleration, error) {
+	tolerations := make([]corev1.Toleration, 0, len(input))
+
+	for _, tolerationStr := range input {
+		var toleration corev1.Toleration
+		if err := yaml.UnmarshalStrict([]byte(tolerationStr), &toleration); err != nil {
+			return nil, fmt.Errorf("could not parse toleration %v: %v", tolerationStr, err.Error())
+		}
+		tolerations = append(tolerations, toleration)
+	}
+
+	return tolerations, nil
+}
+
+func parseAffinity(input string) (*corev1.Affinity, error) {
+	if input == "" {
+		return nil, nil
+	}
+
+	var affinity corev1.Affinity
+	if err := yaml.UnmarshalStrict([]byte(input), &affinity); err != nil {
+		return nil, err
+	}
+
+	return &affinity, nil
+}
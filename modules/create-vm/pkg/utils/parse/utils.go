@@ -1,12 +1,15 @@
 package parse
 
 import (
+	"strconv"
 	"strings"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
 )
 
 func (c *CLIOptions) getMissingNamespaceOptionNames() string {
 	var result = make([]string, 0, 2)
-	if c.GetTemplateNamespace() == "" {
+	if c.GetTemplateNamespace() == "" && !c.HasTemplateSelector() {
 		result = append(result, templateNamespaceOptionName)
 	}
 	if c.GetVirtualMachineNamespace() == "" {
@@ -50,3 +53,14 @@ func splitVolumePrefix(input string) (string, string) {
 
 	return "", strings.TrimSpace(input)
 }
+
+func parsePositiveInt(value string, defaultValue int) int {
+	if result, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && result > 0 {
+		return result
+	}
+	return defaultValue
+}
+
+func replaceIndex(value string, index int) string {
+	return strings.ReplaceAll(value, constants.IndexPlaceholder, strconv.Itoa(index))
+}
@@ -1,17 +1,35 @@
 package parse
 
 import (
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/metrics"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
+	"k8s.io/apimachinery/pkg/api/resource"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	"sigs.k8s.io/yaml"
 )
 
 func (c *CLIOptions) assertValidMode() error {
+	if c.Instancetype != "" {
+		return c.assertValidInstancetypeMode()
+	}
+
+	if c.Preference != "" {
+		return zerrors.NewSoftError("%v option requires %v", preferenceOptionName, instancetypeOptionName)
+	}
+
+	if c.VirtualMachineName != "" {
+		return zerrors.NewSoftError("%v option is applicable only for %v", vmNameOptionName, instancetypeOptionName)
+	}
+
 	if c.VirtualMachineManifest != "" {
 		if c.TemplateName != "" {
 			return zerrors.NewSoftError("only one of %v, %v should be specified", vmManifestOptionName, templateNameOptionName)
@@ -21,8 +39,16 @@ func (c *CLIOptions) assertValidMode() error {
 			return zerrors.NewSoftError("%v, %v options are not applicable for %v", templateNamespaceOptionName, templateParamsOptionName, vmManifestOptionName)
 		}
 
-	} else if c.TemplateName == "" {
-		return zerrors.NewSoftError("one of %v, %v should be specified", vmManifestOptionName, templateNameOptionName)
+		if c.HasTemplateSelector() {
+			return zerrors.NewSoftError("%v, %v, %v options are not applicable for %v", templateOSOptionName, templateWorkloadOptionName, templateFlavorOptionName, vmManifestOptionName)
+		}
+
+	} else if c.TemplateName == "" && !c.HasTemplateSelector() {
+		return zerrors.NewSoftError("one of %v, %v, %v should be specified", vmManifestOptionName, templateNameOptionName, instancetypeOptionName)
+	}
+
+	if c.TemplateName != "" && c.HasTemplateSelector() {
+		return zerrors.NewSoftError("%v, %v, %v options are not applicable for %v", templateOSOptionName, templateWorkloadOptionName, templateFlavorOptionName, templateNameOptionName)
 	}
 
 	if c.GetCreationMode() == "" {
@@ -31,19 +57,215 @@ func (c *CLIOptions) assertValidMode() error {
 	return nil
 }
 
+func (c *CLIOptions) assertValidInstancetypeMode() error {
+	if c.VirtualMachineManifest != "" || c.TemplateName != "" || c.HasTemplateSelector() {
+		return zerrors.NewSoftError("only one of %v, %v, %v should be specified", vmManifestOptionName, templateNameOptionName, instancetypeOptionName)
+	}
+
+	if len(c.GetTemplateParams()) > 0 || c.GetTemplateNamespace() != "" {
+		return zerrors.NewSoftError("%v, %v options are not applicable for %v", templateNamespaceOptionName, templateParamsOptionName, instancetypeOptionName)
+	}
+
+	// KubeVirt rejects a VM which overrides the CPU and memory of its instancetype
+	for _, value := range []string{c.CPUSockets, c.CPUCores, c.CPUThreads, c.Memory, c.MemoryLimit} {
+		if strings.TrimSpace(value) != "" {
+			return zerrors.NewSoftError("%v, %v, %v, %v, %v options are not applicable for %v", cpuSocketsOptionName, cpuCoresOptionName, cpuThreadsOptionName,
+				memoryOptionName, memoryLimitOptionName, instancetypeOptionName)
+		}
+	}
+
+	if _, err := parseMatcher(c.Instancetype, constants.VirtualMachineClusterInstancetypeKind,
+		constants.VirtualMachineClusterInstancetypeKind, constants.VirtualMachineInstancetypeKind); err != nil {
+		return zerrors.NewMissingRequiredError("invalid %v: %v", instancetypeOptionName, err.Error())
+	}
+
+	if _, err := parseMatcher(c.Preference, constants.VirtualMachineClusterPreferenceKind,
+		constants.VirtualMachineClusterPreferenceKind, constants.VirtualMachinePreferenceKind); err != nil {
+		return zerrors.NewMissingRequiredError("invalid %v: %v", preferenceOptionName, err.Error())
+	}
+
+	if strings.TrimSpace(c.BootSource) == "" {
+		return zerrors.NewMissingRequiredError("%v option is required for %v", bootSourceOptionName, instancetypeOptionName)
+	}
+
+	if strings.TrimSpace(c.VirtualMachineName) == "" && strings.TrimSpace(c.NamePattern) == "" {
+		return zerrors.NewMissingRequiredError("one of %v, %v should be specified for %v", vmNameOptionName, namePatternOptionName, instancetypeOptionName)
+	}
+
+	return nil
+}
+
 func (c *CLIOptions) assertValidTypes() error {
 	if !output.IsOutputType(string(c.Output)) {
 		return zerrors.NewMissingRequiredError("%v is not a valid output type", c.Output)
 	}
+
+	if !log.IsLogFormat(c.LogFormat) {
+		return zerrors.NewMissingRequiredError("%v is not a valid log format", c.LogFormat)
+	}
+
+	if !metrics.IsPushgatewayURL(strings.TrimSpace(c.MetricsPushgatewayURL)) {
+		return zerrors.NewMissingRequiredError("%v is not a valid Pushgateway URL", c.MetricsPushgatewayURL)
+	}
+
+	if c.OutputJSONPath != "" {
+		if _, err := output.ParseJSONPath(c.OutputJSONPath); err != nil {
+			return zerrors.NewMissingRequiredError("invalid %v: %v", outputJSONPathOptionName, err.Error())
+		}
+	}
+
+	switch constants.DryRunMode(strings.TrimSpace(c.DryRun)) {
+	case "", constants.DryRunNone, constants.DryRunClient, constants.DryRunServer:
+	default:
+		return zerrors.NewMissingRequiredError("invalid %v %v, only none|client|server is allowed", dryRunOptionName, c.DryRun)
+	}
+
+	switch constants.IfExistsMode(strings.TrimSpace(c.IfExists)) {
+	case "", constants.IfExistsFail, constants.IfExistsSkip, constants.IfExistsReplace, constants.IfExistsPatch:
+	default:
+		return zerrors.NewMissingRequiredError("invalid %v %v, only fail|skip|replace|patch is allowed", ifExistsOptionName, c.IfExists)
+	}
+
+	switch constants.CloudInitType(strings.TrimSpace(c.CloudInitType)) {
+	case "", constants.CloudInitNoCloud, constants.CloudInitConfigDrive:
+	default:
+		return zerrors.NewMissingRequiredError("invalid %v %v, only nocloud|configdrive is allowed", cloudInitTypeOptionName, c.CloudInitType)
+	}
+
+	if c.Timeout != "" {
+		if _, err := time.ParseDuration(strings.TrimSpace(c.Timeout)); err != nil {
+			return zerrors.NewMissingRequiredError("could not parse %v: %v", timeoutOptionName, err.Error())
+		}
+	}
+
+	for _, option := range [][2]string{{countOptionName, c.Count}, {concurrencyOptionName, c.Concurrency}} {
+		if value := strings.TrimSpace(option[1]); value != "" {
+			if number, err := strconv.Atoi(value); err != nil || number <= 0 {
+				return zerrors.NewMissingRequiredError("invalid %v %v, should be a positive number", option[0], value)
+			}
+		}
+	}
+
+	if c.NamePattern != "" && c.GetCount() > 1 && !strings.Contains(c.NamePattern, constants.IndexPlaceholder) {
+		return zerrors.NewMissingRequiredError("%v should contain %v when %v is greater than 1", namePatternOptionName, constants.IndexPlaceholder, countOptionName)
+	}
+
+	// each VM of a batch would get the same volumes, which can be owned only by one of them
+	if c.GetCount() > 1 && len(c.DataVolumes)+len(c.OwnDataVolumes)+len(c.PersistentVolumeClaims)+len(c.OwnPersistentVolumeClaims) > 0 {
+		return zerrors.NewMissingRequiredError("%v, %v, %v, %v options are not applicable when %v is greater than 1", dvsOptionName, ownDVsOptionName,
+			pvcsOptionName, ownPVCsOptionName, countOptionName)
+	}
+
+	if c.GetWaitForReady() && c.IsDryRun() {
+		return zerrors.NewMissingRequiredError("%v option is not applicable for %v", waitForReadyOptionName, dryRunOptionName)
+	}
+	return nil
+}
+
+func (c *CLIOptions) assertValidCloudInitAndSysprep() error {
+	if c.CloudInitUserData != "" && c.CloudInitUserDataSecret != "" {
+		return zerrors.NewMissingRequiredError("only one of %v, %v should be specified", cloudInitUserDataOptionName, cloudInitUserDataSecretOptionName)
+	}
+
+	if c.CloudInitNetworkData != "" && c.CloudInitNetworkDataSecret != "" {
+		return zerrors.NewMissingRequiredError("only one of %v, %v should be specified", cloudInitNetworkDataOptionName, cloudInitNetworkDataSecretOptionName)
+	}
+
+	if c.HasCloudInit() && c.CloudInitUserData == "" && c.CloudInitUserDataSecret == "" {
+		return zerrors.NewMissingRequiredError("one of %v, %v should be specified", cloudInitUserDataOptionName, cloudInitUserDataSecretOptionName)
+	}
+
+	if c.SysprepConfigMap != "" && c.SysprepSecret != "" {
+		return zerrors.NewMissingRequiredError("only one of %v, %v should be specified", sysprepConfigMapOptionName, sysprepSecretOptionName)
+	}
+
+	switch constants.SSHPropagationMethod(strings.TrimSpace(c.SSHPropagationMethod)) {
+	case "", constants.SSHPropagationCloudInit:
+		if len(c.SSHPublicKeySecrets) > 0 && constants.CloudInitType(strings.TrimSpace(c.CloudInitType)) == constants.CloudInitNoCloud {
+			return zerrors.NewMissingRequiredError("%v %v requires %v cloud-init volume", sshPropagationMethodOptionName, constants.SSHPropagationCloudInit, constants.CloudInitConfigDrive)
+		}
+	case constants.SSHPropagationQemuGuestAgent:
+		if len(c.SSHPublicKeySecrets) > 0 && len(c.SSHUsers) == 0 {
+			return zerrors.NewMissingRequiredError("%v option is required for %v %v", sshUsersOptionName, sshPropagationMethodOptionName, constants.SSHPropagationQemuGuestAgent)
+		}
+	default:
+		return zerrors.NewMissingRequiredError("invalid %v %v, only cloud-init|qemu-guest-agent is allowed", sshPropagationMethodOptionName, c.SSHPropagationMethod)
+	}
+
+	return nil
+}
+
+func (c *CLIOptions) assertValidResourcesAndScheduling() error {
+	for optionName, value := range map[string]string{cpuSocketsOptionName: c.CPUSockets, cpuCoresOptionName: c.CPUCores, cpuThreadsOptionName: c.CPUThreads} {
+		if value = strings.TrimSpace(value); value != "" {
+			if count, err := strconv.ParseUint(value, 10, 32); err != nil || count == 0 {
+				return zerrors.NewMissingRequiredError("invalid %v %v, should be a positive number", optionName, value)
+			}
+		}
+	}
+
+	var memory, memoryLimit resource.Quantity
+	var err error
+	if value := strings.TrimSpace(c.Memory); value != "" {
+		if memory, err = resource.ParseQuantity(value); err != nil {
+			return zerrors.NewMissingRequiredError("could not parse %v: %v", memoryOptionName, err.Error())
+		}
+	}
+
+	if value := strings.TrimSpace(c.MemoryLimit); value != "" {
+		if memoryLimit, err = resource.ParseQuantity(value); err != nil {
+			return zerrors.NewMissingRequiredError("could not parse %v: %v", memoryLimitOptionName, err.Error())
+		}
+		if !memory.IsZero() && memoryLimit.Cmp(memory) < 0 {
+			return zerrors.NewMissingRequiredError("%v %v should not be lower than %v %v", memoryLimitOptionName, c.MemoryLimit, memoryOptionName, c.Memory)
+		}
+	}
+
+	if _, err := zutils.ExtractKeysAndValuesByLastKnownKey(c.NodeSelector, nodeSelectorSep); err != nil {
+		return zerrors.NewMissingRequiredError("invalid %v: %v", nodeSelectorOptionName, err.Error())
+	}
+
+	if _, err := parseTolerations(c.Tolerations); err != nil {
+		return zerrors.NewMissingRequiredError("invalid %v: %v", tolerationsOptionName, err.Error())
+	}
+
+	if _, err := parseAffinity(strings.TrimSpace(c.Affinity)); err != nil {
+		return zerrors.NewMissingRequiredError("could not parse %v: %v", affinityOptionName, err.Error())
+	}
+
+	if _, err := parseBootSource(c.BootSource); err != nil {
+		return zerrors.NewMissingRequiredError("invalid %v: %v", bootSourceOptionName, err.Error())
+	}
+
+	if value := strings.TrimSpace(c.RootDiskSize); value != "" {
+		if strings.TrimSpace(c.BootSource) == "" {
+			return zerrors.NewMissingRequiredError("%v option requires %v", rootDiskSizeOptionName, bootSourceOptionName)
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return zerrors.NewMissingRequiredError("could not parse %v: %v", rootDiskSizeOptionName, err.Error())
+		}
+	}
+
+	switch kubevirtv1.EvictionStrategy(strings.TrimSpace(c.EvictionStrategy)) {
+	case "", kubevirtv1.EvictionStrategyLiveMigrate:
+	default:
+		return zerrors.NewMissingRequiredError("invalid %v %v, only %v is allowed", evictionStrategyOptionName, c.EvictionStrategy, kubevirtv1.EvictionStrategyLiveMigrate)
+	}
+
 	return nil
 }
 
 func (c *CLIOptions) trimSpaces() {
-	for _, strVariablePtr := range []*string{&c.TemplateName, &c.TemplateNamespace, &c.VirtualMachineNamespace} {
+	for _, strVariablePtr := range []*string{&c.TemplateName, &c.TemplateNamespace, &c.TemplateOS, &c.TemplateWorkload, &c.TemplateFlavor, &c.VirtualMachineNamespace,
+		&c.VirtualMachineName, &c.Instancetype, &c.Preference, &c.DryRun, &c.IfExists, &c.WaitForReady, &c.WaitForGuestAgent, &c.Timeout,
+		&c.CloudInitType, &c.CloudInitUserDataSecret, &c.CloudInitNetworkDataSecret, &c.SysprepConfigMap, &c.SysprepSecret, &c.SSHPropagationMethod,
+		&c.CPUSockets, &c.CPUCores, &c.CPUThreads, &c.Memory, &c.MemoryLimit, &c.Affinity, &c.EvictionStrategy, &c.BootSource, &c.RootDiskSize,
+		&c.Count, &c.NamePattern, &c.Concurrency, &c.RollbackOnFailure,
+		&c.OutputFile, &c.OutputJSONPath, &c.MetricsFile, &c.MetricsPushgatewayURL} {
 		*strVariablePtr = strings.TrimSpace(*strVariablePtr)
 	}
 
-	for _, sliceVariablePtr := range []*[]string{&c.DataVolumes, &c.OwnDataVolumes, &c.PersistentVolumeClaims, &c.OwnPersistentVolumeClaims} {
+	for _, sliceVariablePtr := range []*[]string{&c.DataVolumes, &c.OwnDataVolumes, &c.PersistentVolumeClaims, &c.OwnPersistentVolumeClaims, &c.SSHPublicKeySecrets, &c.SSHUsers, &c.Networks, &c.NodeSelector, &c.Tolerations} {
 		for i, v := range *sliceVariablePtr {
 			(*sliceVariablePtr)[i] = strings.TrimSpace(v)
 		}
@@ -53,19 +275,29 @@ func (c *CLIOptions) trimSpaces() {
 func (c *CLIOptions) resolveDefaultNamespacesAndManifests() error {
 	if c.GetCreationMode() == constants.TemplateCreationMode {
 		vmNamespace := c.GetVirtualMachineNamespace()
+		// templates found by a selector are looked up across all namespaces
 		tempNamespace := c.GetTemplateNamespace()
-		if vmNamespace == "" || tempNamespace == "" {
+		resolveTempNamespace := tempNamespace == "" && !c.HasTemplateSelector()
+		if vmNamespace == "" || resolveTempNamespace {
 			activeNamespace, err := env.GetActiveNamespace()
 			if err != nil {
 				return zerrors.NewMissingRequiredError("%v: %v option is empty", err.Error(), c.getMissingNamespaceOptionNames())
 			}
-			if tempNamespace == "" {
+			if resolveTempNamespace {
 				c.TemplateNamespace = activeNamespace
 			}
 			if vmNamespace == "" {
 				c.VirtualMachineNamespace = activeNamespace
 			}
 		}
+	} else if c.GetCreationMode() == constants.InstancetypeCreationMode {
+		if c.GetVirtualMachineNamespace() == "" {
+			activeNamespace, err := env.GetActiveNamespace()
+			if err != nil {
+				return zerrors.NewMissingRequiredError("%v: %v option is empty", err.Error(), vmNamespaceOptionName)
+			}
+			c.VirtualMachineNamespace = activeNamespace
+		}
 	} else if c.GetCreationMode() == constants.VMManifestCreationMode {
 		vmNamespace := c.GetVirtualMachineNamespace()
 		if vmNamespace == "" {
@@ -0,0 +1,93 @@
+package vm
+
+import (
+	lab "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants/labels"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+)
+
+const instancetypeGroupName = "instancetype.kubevirt.io"
+
+// IsInstancetypeAPIAvailable returns false if the cluster does not serve the instancetype.kubevirt.io API, eg. on older KubeVirt versions
+func IsInstancetypeAPIAvailable(client discovery.ServerGroupsInterface) (bool, error) {
+	groups, err := client.ServerGroups()
+	if err != nil {
+		return false, err
+	}
+
+	for _, group := range groups.Groups {
+		if group.Name == instancetypeGroupName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// InstancetypeMatcher references an instancetype or a preference of a VM
+type InstancetypeMatcher struct {
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name"`
+}
+
+// InstancetypeVirtualMachine is a VirtualMachine with spec.instancetype and spec.preference.
+// The vendored KubeVirt API predates instancetypes, so the VirtualMachine type of the vendored client drops these fields.
+type InstancetypeVirtualMachine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InstancetypeVirtualMachineSpec  `json:"spec"`
+	Status kubevirtv1.VirtualMachineStatus `json:"status,omitempty"`
+}
+
+type InstancetypeVirtualMachineSpec struct {
+	kubevirtv1.VirtualMachineSpec `json:",inline"`
+
+	Instancetype *InstancetypeMatcher `json:"instancetype,omitempty"`
+	Preference   *InstancetypeMatcher `json:"preference,omitempty"`
+}
+
+// NewInstancetypeVM returns a stopped VM without resources, which are provided by its instancetype and preference.
+// The template is labeled with the VM name and is filled by the same functions as the VMs of the other creation modes.
+func NewInstancetypeVM(name, namespace string) *kubevirtv1.VirtualMachine {
+	running := false
+	return &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: kubevirtv1.VirtualMachineSpec{
+			Running: &running,
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						lab.VMNameLabel: name,
+					},
+				},
+			},
+		},
+	}
+}
+
+// WithInstancetype returns a copy of the VM with the instancetype and the preference, which are not set if they are nil
+func WithInstancetype(vm *kubevirtv1.VirtualMachine, instancetype, preference *parse.Matcher) *InstancetypeVirtualMachine {
+	result := &InstancetypeVirtualMachine{
+		ObjectMeta: *vm.ObjectMeta.DeepCopy(),
+		Spec: InstancetypeVirtualMachineSpec{
+			VirtualMachineSpec: *vm.Spec.DeepCopy(),
+			Instancetype:       newInstancetypeMatcher(instancetype),
+			Preference:         newInstancetypeMatcher(preference),
+		},
+		Status: *vm.Status.DeepCopy(),
+	}
+	result.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+	return result
+}
+
+func newInstancetypeMatcher(matcher *parse.Matcher) *InstancetypeMatcher {
+	if matcher == nil {
+		return nil
+	}
+	return &InstancetypeMatcher{Kind: matcher.Kind, Name: matcher.Name}
+}
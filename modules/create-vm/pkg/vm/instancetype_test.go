@@ -0,0 +1,92 @@
+package vm_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
+	lab "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants/labels"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
+	vm2 "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vm"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+)
+
+type fakeGroupsDiscovery struct {
+	groups []string
+	err    error
+}
+
+func (f *fakeGroupsDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := &metav1.APIGroupList{}
+	for _, group := range f.groups {
+		result.Groups = append(result.Groups, metav1.APIGroup{Name: group})
+	}
+	return result, nil
+}
+
+var _ = Describe("Instancetype", func() {
+	table.DescribeTable("IsInstancetypeAPIAvailable", func(client *fakeGroupsDiscovery, expectedAvailable bool, expectedErr string) {
+		available, err := vm2.IsInstancetypeAPIAvailable(client)
+		if expectedErr != "" {
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(Equal(expectedErr))
+		} else {
+			Expect(err).Should(Succeed())
+		}
+		Expect(available).To(Equal(expectedAvailable))
+	},
+		table.Entry("older kubevirt", &fakeGroupsDiscovery{groups: []string{"kubevirt.io", "cdi.kubevirt.io"}}, false, ""),
+		table.Entry("kubevirt with instancetypes", &fakeGroupsDiscovery{groups: []string{"kubevirt.io", "instancetype.kubevirt.io"}}, true, ""),
+		table.Entry("discovery error", &fakeGroupsDiscovery{err: errors.New("connection refused")}, false, "connection refused"),
+	)
+
+	It("creates a stopped VM", func() {
+		vm := vm2.NewInstancetypeVM("my-vm", "default")
+		Expect(vm.Name).To(Equal("my-vm"))
+		Expect(vm.Namespace).To(Equal("default"))
+		Expect(*vm.Spec.Running).To(BeFalse())
+		Expect(vm.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue(lab.VMNameLabel, "my-vm"))
+	})
+
+	It("sets the instancetype and the preference", func() {
+		vm := vm2.NewInstancetypeVM("my-vm", "default")
+		vm.Spec.Template.Spec.Volumes = []kubevirtv1.Volume{{Name: "rootdisk", VolumeSource: kubevirtv1.VolumeSource{
+			DataVolume: &kubevirtv1.DataVolumeSource{Name: "my-vm-rootdisk"},
+		}}}
+
+		result := vm2.WithInstancetype(vm,
+			&parse.Matcher{Kind: constants.VirtualMachineClusterInstancetypeKind, Name: "u1.medium"},
+			&parse.Matcher{Kind: constants.VirtualMachinePreferenceKind, Name: "fedora"},
+		)
+		Expect(result.APIVersion).To(Equal(kubevirtv1.GroupVersion.String()))
+		Expect(result.Kind).To(Equal("VirtualMachine"))
+		Expect(result.Name).To(Equal("my-vm"))
+		Expect(result.Spec.Instancetype).To(Equal(&vm2.InstancetypeMatcher{Kind: constants.VirtualMachineClusterInstancetypeKind, Name: "u1.medium"}))
+		Expect(result.Spec.Preference).To(Equal(&vm2.InstancetypeMatcher{Kind: constants.VirtualMachinePreferenceKind, Name: "fedora"}))
+		Expect(result.Spec.Template.Spec.Volumes).To(Equal(vm.Spec.Template.Spec.Volumes))
+		Expect(vm.GetObjectKind().GroupVersionKind().Empty()).To(BeTrue())
+
+		data, err := json.Marshal(result)
+		Expect(err).Should(Succeed())
+		var decoded kubevirtv1.VirtualMachine
+		Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded.Spec.Template.Spec.Volumes).To(Equal(vm.Spec.Template.Spec.Volumes))
+	})
+
+	It("does not set a missing preference", func() {
+		result := vm2.WithInstancetype(vm2.NewInstancetypeVM("my-vm", "default"), &parse.Matcher{Kind: constants.VirtualMachineInstancetypeKind, Name: "small"}, nil)
+		Expect(result.Spec.Preference).To(BeNil())
+
+		data, err := json.Marshal(result)
+		Expect(err).Should(Succeed())
+		Expect(string(data)).To(ContainSubstring(`"instancetype":{"kind":"VirtualMachineInstancetype","name":"small"}`))
+		Expect(string(data)).ToNot(ContainSubstring("preference"))
+	})
+})
@@ -1,29 +1,182 @@
 package vm
 
 import (
+	"context"
+	"encoding/json"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	kubevirtcliv1 "kubevirt.io/client-go/kubecli"
 )
 
 type virtualMachineProvider struct {
-	client kubevirtcliv1.KubevirtClient
+	client       kubevirtcliv1.KubevirtClient
+	instancetype *parse.Matcher
+	preference   *parse.Matcher
 }
 
 type VirtualMachineProvider interface {
 	Create(namespace string, vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error)
+	CreateDryRun(namespace string, vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error)
+	Get(namespace, name string) (*kubevirtv1.VirtualMachine, error)
+	GetWithInstancetype(namespace, name string) (*InstancetypeVirtualMachine, error)
+	Apply(namespace string, vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error)
+	Update(namespace string, vm *InstancetypeVirtualMachine) (*kubevirtv1.VirtualMachine, error)
+	Delete(namespace, name string, propagationPolicy metav1.DeletionPropagation) error
 	Start(namespace, name string) error
+	Stop(namespace, name string) error
 }
 
-func NewVirtualMachineProvider(client kubevirtcliv1.KubevirtClient) VirtualMachineProvider {
+const fieldManager = "create-vm"
+
+// NewVirtualMachineProvider returns a provider which sets the instancetype and the preference of the created and applied VMs, if they are not nil
+func NewVirtualMachineProvider(client kubevirtcliv1.KubevirtClient, instancetype, preference *parse.Matcher) VirtualMachineProvider {
 	return &virtualMachineProvider{
-		client: client,
+		client:       client,
+		instancetype: instancetype,
+		preference:   preference,
 	}
 }
 
 func (v *virtualMachineProvider) Create(namespace string, vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error) {
-	return v.client.VirtualMachine(namespace).Create(vm)
+	if v.instancetype == nil {
+		return v.client.VirtualMachine(namespace).Create(vm)
+	}
+
+	body, err := v.getBody(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	newVM := &kubevirtv1.VirtualMachine{}
+	err = v.client.RestClient().Post().
+		Resource("virtualmachines").
+		Namespace(namespace).
+		Body(body).
+		Do(context.TODO()).
+		Into(newVM)
+
+	newVM.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+
+	return newVM, err
+}
+
+// CreateDryRun submits the VM to the API server, which runs admission and validation without persisting the VM
+func (v *virtualMachineProvider) CreateDryRun(namespace string, vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error) {
+	body, err := v.getBody(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	newVM := &kubevirtv1.VirtualMachine{}
+	err = v.client.RestClient().Post().
+		Resource("virtualmachines").
+		Namespace(namespace).
+		Param("dryRun", metav1.DryRunAll).
+		Body(body).
+		Do(context.TODO()).
+		Into(newVM)
+
+	newVM.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+
+	return newVM, err
+}
+
+func (v *virtualMachineProvider) Get(namespace, name string) (*kubevirtv1.VirtualMachine, error) {
+	return v.client.VirtualMachine(namespace).Get(name, &metav1.GetOptions{})
+}
+
+// Apply updates the VM with server-side apply and takes ownership of the conflicting fields
+func (v *virtualMachineProvider) Apply(namespace string, vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error) {
+	appliedVM := vm.DeepCopy()
+	appliedVM.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+	appliedVM.ResourceVersion = ""
+
+	data, err := v.getBody(appliedVM)
+	if err != nil {
+		return nil, err
+	}
+
+	newVM := &kubevirtv1.VirtualMachine{}
+	err = v.client.RestClient().Patch(types.ApplyPatchType).
+		Resource("virtualmachines").
+		Namespace(namespace).
+		Name(vm.Name).
+		Param("fieldManager", fieldManager).
+		Param("force", "true").
+		Body(data).
+		Do(context.TODO()).
+		Into(newVM)
+
+	newVM.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+
+	return newVM, err
+}
+
+// GetWithInstancetype returns the VM together with its instancetype and preference
+func (v *virtualMachineProvider) GetWithInstancetype(namespace, name string) (*InstancetypeVirtualMachine, error) {
+	data, err := v.client.RestClient().Get().
+		Resource("virtualmachines").
+		Namespace(namespace).
+		Name(name).
+		DoRaw(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	vm := &InstancetypeVirtualMachine{}
+	if err := json.Unmarshal(data, vm); err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
+// Update replaces the VM, including its instancetype and preference. The update fails with a conflict if the resourceVersion of the VM is outdated.
+func (v *virtualMachineProvider) Update(namespace string, vm *InstancetypeVirtualMachine) (*kubevirtv1.VirtualMachine, error) {
+	updatedVM := *vm
+	updatedVM.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+
+	data, err := json.Marshal(&updatedVM)
+	if err != nil {
+		return nil, err
+	}
+
+	newVM := &kubevirtv1.VirtualMachine{}
+	err = v.client.RestClient().Put().
+		Resource("virtualmachines").
+		Namespace(namespace).
+		Name(vm.Name).
+		Body(data).
+		Do(context.TODO()).
+		Into(newVM)
+
+	newVM.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+
+	return newVM, err
+}
+
+// Delete deletes the VM. With the foreground propagation policy the VM is deleted once all of its dependents (VMI, DataVolumes, PersistentVolumeClaims) are deleted.
+func (v *virtualMachineProvider) Delete(namespace, name string, propagationPolicy metav1.DeletionPropagation) error {
+	return v.client.VirtualMachine(namespace).Delete(name, &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy})
 }
 
 func (v *virtualMachineProvider) Start(namespace, name string) error {
 	return v.client.VirtualMachine(namespace).Start(name)
 }
+
+func (v *virtualMachineProvider) Stop(namespace, name string) error {
+	return v.client.VirtualMachine(namespace).Stop(name)
+}
+
+// getBody returns the VM with its kind, instancetype and preference as a JSON request body
+func (v *virtualMachineProvider) getBody(vm *kubevirtv1.VirtualMachine) ([]byte, error) {
+	if v.instancetype == nil {
+		vmWithKind := vm.DeepCopy()
+		vmWithKind.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+		return json.Marshal(vmWithKind)
+	}
+
+	return json.Marshal(WithInstancetype(vm, v.instancetype, v.preference))
+}
@@ -0,0 +1,140 @@
+package vm_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
+	vm2 "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vm"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const vmsPath = "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachines"
+
+type recordedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	body   map[string]interface{}
+}
+
+// newFakeAPIServer echoes the request body back, like the API server returns the created or updated VM
+func newFakeAPIServer(requests *[]recordedRequest, storedVM string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		Expect(err).Should(Succeed())
+
+		request := recordedRequest{method: r.Method, path: r.URL.Path, query: r.URL.Query()}
+		if len(data) > 0 {
+			Expect(json.Unmarshal(data, &request.body)).To(Succeed())
+		}
+		*requests = append(*requests, request)
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			data = []byte(storedVM)
+		}
+		_, _ = w.Write(data)
+	}))
+}
+
+func getNestedMap(object map[string]interface{}, fields ...string) map[string]interface{} {
+	for _, field := range fields {
+		object, _ = object[field].(map[string]interface{})
+	}
+	return object
+}
+
+var _ = Describe("VirtualMachineProvider", func() {
+	var requests []recordedRequest
+	var server *httptest.Server
+	var provider vm2.VirtualMachineProvider
+	var vm *kubevirtv1.VirtualMachine
+
+	instancetype := &parse.Matcher{Kind: constants.VirtualMachineClusterInstancetypeKind, Name: "u1.medium"}
+	preference := &parse.Matcher{Kind: constants.VirtualMachineClusterPreferenceKind, Name: "fedora"}
+
+	BeforeEach(func() {
+		requests = nil
+		server = newFakeAPIServer(&requests, `{"apiVersion":"kubevirt.io/v1","kind":"VirtualMachine","metadata":{"name":"my-vm","namespace":"default","resourceVersion":"42"},`+
+			`"spec":{"instancetype":{"kind":"VirtualMachineInstancetype","name":"small"},"running":false,"template":{"spec":{"domain":{"devices":{}}}}}}`)
+
+		client, err := kubecli.GetKubevirtClientFromRESTConfig(&rest.Config{Host: server.URL})
+		Expect(err).Should(Succeed())
+		provider = vm2.NewVirtualMachineProvider(client, instancetype, preference)
+
+		vm = vm2.NewInstancetypeVM("my-vm", "default")
+		vm.Spec.Template.Spec.Volumes = []kubevirtv1.Volume{{Name: "rootdisk", VolumeSource: kubevirtv1.VolumeSource{
+			DataVolume: &kubevirtv1.DataVolumeSource{Name: "my-vm-rootdisk"},
+		}}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	expectInstancetypeVM := func(body map[string]interface{}) {
+		Expect(body).To(HaveKeyWithValue("apiVersion", "kubevirt.io/v1"))
+		Expect(body).To(HaveKeyWithValue("kind", "VirtualMachine"))
+		Expect(getNestedMap(body, "spec", "instancetype")).To(Equal(map[string]interface{}{
+			"kind": constants.VirtualMachineClusterInstancetypeKind, "name": "u1.medium",
+		}))
+		Expect(getNestedMap(body, "spec", "preference")).To(Equal(map[string]interface{}{
+			"kind": constants.VirtualMachineClusterPreferenceKind, "name": "fedora",
+		}))
+		Expect(getNestedMap(body, "spec", "template", "metadata", "labels")).To(HaveKeyWithValue("vm.kubevirt.io/name", "my-vm"))
+		Expect(getNestedMap(body, "spec", "template", "spec")["volumes"]).To(HaveLen(1))
+	}
+
+	table.DescribeTable("sends the instancetype and the preference", func(call func() (*kubevirtv1.VirtualMachine, error), expectedMethod, expectedPath string, expectedQuery url.Values) {
+		result, err := call()
+		Expect(err).Should(Succeed())
+		Expect(result.Name).To(Equal("my-vm"))
+		Expect(result.Spec.Template.Spec.Volumes).To(Equal(vm.Spec.Template.Spec.Volumes))
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(expectedMethod))
+		Expect(requests[0].path).To(Equal(expectedPath))
+		Expect(requests[0].query).To(Equal(expectedQuery))
+		expectInstancetypeVM(requests[0].body)
+	},
+		table.Entry("Create", func() (*kubevirtv1.VirtualMachine, error) {
+			return provider.Create("default", vm)
+		}, http.MethodPost, vmsPath, url.Values{}),
+		table.Entry("CreateDryRun", func() (*kubevirtv1.VirtualMachine, error) {
+			return provider.CreateDryRun("default", vm)
+		}, http.MethodPost, vmsPath, url.Values{"dryRun": []string{"All"}}),
+		table.Entry("Apply", func() (*kubevirtv1.VirtualMachine, error) {
+			return provider.Apply("default", vm)
+		}, http.MethodPatch, vmsPath+"/my-vm", url.Values{"fieldManager": []string{"create-vm"}, "force": []string{"true"}}),
+	)
+
+	It("reads and updates the instancetype", func() {
+		existingVM, err := provider.GetWithInstancetype("default", "my-vm")
+		Expect(err).Should(Succeed())
+		Expect(existingVM.ResourceVersion).To(Equal("42"))
+		Expect(existingVM.Spec.Instancetype).To(Equal(&vm2.InstancetypeMatcher{Kind: constants.VirtualMachineInstancetypeKind, Name: "small"}))
+		Expect(existingVM.Spec.Preference).To(BeNil())
+
+		_, err = provider.Update("default", existingVM)
+		Expect(err).Should(Succeed())
+
+		Expect(requests).To(HaveLen(2))
+		Expect(requests[1].method).To(Equal(http.MethodPut))
+		Expect(requests[1].path).To(Equal(vmsPath + "/my-vm"))
+		Expect(getNestedMap(requests[1].body, "metadata")).To(HaveKeyWithValue("resourceVersion", "42"))
+		Expect(getNestedMap(requests[1].body, "spec", "instancetype")).To(Equal(map[string]interface{}{
+			"kind": constants.VirtualMachineInstancetypeKind, "name": "small",
+		}))
+		Expect(getNestedMap(requests[1].body, "spec")).ToNot(HaveKey("preference"))
+	})
+})
@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
 	lab "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants/labels"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/k8s"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/templates"
@@ -9,8 +10,17 @@ import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zconstants"
 	templatev1 "github.com/openshift/api/template/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+const (
+	cloudInitVolumeName = "cloudinitdisk"
+	emptyCloudConfig    = "#cloud-config\n"
+	sysprepVolumeName   = "sysprep"
+	rootDiskVolumeName  = "rootdisk"
 )
 
 func AddMetadata(vm *kubevirtv1.VirtualMachine, template *templatev1.Template) {
@@ -63,6 +73,17 @@ func getDisk(vm *kubevirtv1.VirtualMachine, name string) *kubevirtv1.Disk {
 	return nil
 }
 
+// returns transient pointer to the first cloud-init Volume struct in array
+func getCloudInitVolume(vm *kubevirtv1.VirtualMachine) *kubevirtv1.Volume {
+	for i := 0; i < len(vm.Spec.Template.Spec.Volumes); i++ {
+		if vm.Spec.Template.Spec.Volumes[i].CloudInitNoCloud != nil || vm.Spec.Template.Spec.Volumes[i].CloudInitConfigDrive != nil {
+			return &vm.Spec.Template.Spec.Volumes[i]
+		}
+	}
+
+	return nil
+}
+
 // returns transient pointer to the Volume struct in array
 func getVolume(vm *kubevirtv1.VirtualMachine, name string) *kubevirtv1.Volume {
 	for i := 0; i < len(vm.Spec.Template.Spec.Volumes); i++ {
@@ -134,6 +155,376 @@ func AddVolumes(vm *kubevirtv1.VirtualMachine, templateValidations *validations.
 			volume.DataVolume.Name = dvName
 		}
 	}
+
+	if cliParams.HasCloudInit() {
+		volumeName := cloudInitVolumeName
+		if volume := getCloudInitVolume(vm); volume != nil {
+			volumeName = volume.Name
+		}
+
+		ensureDisk(volumeName)
+		ensureVolume(volumeName).VolumeSource = newCloudInitVolumeSource(cliParams)
+	}
+
+	if cliParams.HasSysprep() {
+		if getDisk(vm, sysprepVolumeName) == nil {
+			// sysprep has to be attached as a CD-ROM
+			vm.Spec.Template.Spec.Domain.Devices.Disks = append(vm.Spec.Template.Spec.Domain.Devices.Disks, kubevirtv1.Disk{
+				Name: sysprepVolumeName,
+				DiskDevice: kubevirtv1.DiskDevice{
+					CDRom: &kubevirtv1.CDRomTarget{Bus: templateValidations.GetDefaultCDRomBus()},
+				},
+			})
+		}
+
+		sysprep := &kubevirtv1.SysprepSource{}
+		if cliParams.SysprepConfigMap != "" {
+			sysprep.ConfigMap = &v1.LocalObjectReference{Name: cliParams.SysprepConfigMap}
+		} else {
+			sysprep.Secret = &v1.LocalObjectReference{Name: cliParams.SysprepSecret}
+		}
+		ensureVolume(sysprepVolumeName).VolumeSource = kubevirtv1.VolumeSource{Sysprep: sysprep}
+	}
+}
+
+// returns transient pointer to the DataVolumeTemplateSpec struct in array
+func getDataVolumeTemplate(vm *kubevirtv1.VirtualMachine, name string) *kubevirtv1.DataVolumeTemplateSpec {
+	for i := 0; i < len(vm.Spec.DataVolumeTemplates); i++ {
+		if vm.Spec.DataVolumeTemplates[i].Name == name {
+			return &vm.Spec.DataVolumeTemplates[i]
+		}
+	}
+
+	return nil
+}
+
+// getRootDiskName returns the disk with the lowest boot order, a rootdisk or the first disk in this order
+func getRootDiskName(vm *kubevirtv1.VirtualMachine) string {
+	disks := vm.Spec.Template.Spec.Domain.Devices.Disks
+	var rootDisk *kubevirtv1.Disk
+
+	for i := range disks {
+		if disks[i].BootOrder != nil && (rootDisk == nil || *disks[i].BootOrder < *rootDisk.BootOrder) {
+			rootDisk = &disks[i]
+		}
+	}
+
+	switch {
+	case rootDisk != nil:
+		return rootDisk.Name
+	case getDisk(vm, rootDiskVolumeName) != nil:
+		return rootDiskVolumeName
+	case len(disks) > 0:
+		return disks[0].Name
+	}
+	return rootDiskVolumeName
+}
+
+// AddBootSource backs the root disk with a dataVolumeTemplate which clones the source PVC. An existing dataVolumeTemplate
+// of the root disk is reused, so the VM does not create the original disk as well.
+func AddBootSource(vm *kubevirtv1.VirtualMachine, templateValidations *validations.TemplateValidations, sourcePVC *v1.PersistentVolumeClaim, size resource.Quantity) {
+	if templateValidations == nil {
+		templateValidations = validations.NewTemplateValidations(nil)
+	}
+	diskName := getRootDiskName(vm)
+
+	if getDisk(vm, diskName) == nil {
+		vm.Spec.Template.Spec.Domain.Devices.Disks = append(vm.Spec.Template.Spec.Domain.Devices.Disks, kubevirtv1.Disk{
+			Name: diskName,
+			DiskDevice: kubevirtv1.DiskDevice{
+				Disk: &kubevirtv1.DiskTarget{Bus: templateValidations.GetDefaultDiskBus()},
+			},
+		})
+	}
+
+	volume := getVolume(vm, diskName)
+	if volume == nil {
+		vm.Spec.Template.Spec.Volumes = append(vm.Spec.Template.Spec.Volumes, kubevirtv1.Volume{Name: diskName})
+		volume = getVolume(vm, diskName)
+	}
+
+	dvName := vm.GetName() + "-" + diskName
+	if volume.DataVolume != nil && getDataVolumeTemplate(vm, volume.DataVolume.Name) != nil {
+		dvName = volume.DataVolume.Name
+	}
+	volume.VolumeSource = kubevirtv1.VolumeSource{
+		DataVolume: &kubevirtv1.DataVolumeSource{Name: dvName},
+	}
+
+	dataVolumeTemplate := getDataVolumeTemplate(vm, dvName)
+	if dataVolumeTemplate == nil {
+		vm.Spec.DataVolumeTemplates = append(vm.Spec.DataVolumeTemplates, kubevirtv1.DataVolumeTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Name: dvName},
+		})
+		dataVolumeTemplate = getDataVolumeTemplate(vm, dvName)
+	}
+
+	// the same storage class and volume mode as the source let CDI use smart clone
+	dataVolumeTemplate.Spec = cdiv1.DataVolumeSpec{
+		Source: cdiv1.DataVolumeSource{
+			PVC: &cdiv1.DataVolumeSourcePVC{Namespace: sourcePVC.Namespace, Name: sourcePVC.Name},
+		},
+		PVC: &v1.PersistentVolumeClaimSpec{
+			AccessModes:      sourcePVC.Spec.AccessModes,
+			VolumeMode:       sourcePVC.Spec.VolumeMode,
+			StorageClassName: sourcePVC.Spec.StorageClassName,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: size},
+			},
+		},
+	}
+}
+
+// returns transient pointer to the Network struct in array
+func getNetwork(vm *kubevirtv1.VirtualMachine, name string) *kubevirtv1.Network {
+	for i := 0; i < len(vm.Spec.Template.Spec.Networks); i++ {
+		if vm.Spec.Template.Spec.Networks[i].Name == name {
+			return &vm.Spec.Template.Spec.Networks[i]
+		}
+	}
+
+	return nil
+}
+
+// returns transient pointer to the Interface struct in array
+func getInterface(vm *kubevirtv1.VirtualMachine, name string) *kubevirtv1.Interface {
+	for i := 0; i < len(vm.Spec.Template.Spec.Domain.Devices.Interfaces); i++ {
+		if vm.Spec.Template.Spec.Domain.Devices.Interfaces[i].Name == name {
+			return &vm.Spec.Template.Spec.Domain.Devices.Interfaces[i]
+		}
+	}
+
+	return nil
+}
+
+// AddNetworks adds or replaces networks and their interfaces. Existing interfaces keep their model and MAC address.
+func AddNetworks(vm *kubevirtv1.VirtualMachine, templateValidations *validations.TemplateValidations, cliParams *parse.CLIOptions) {
+	if templateValidations == nil {
+		templateValidations = validations.NewTemplateValidations(nil)
+	}
+	defaultModel := templateValidations.GetDefaultInterfaceModel()
+
+	for _, networkOption := range cliParams.GetNetworks() {
+		network := getNetwork(vm, networkOption.Name)
+		if network == nil {
+			vm.Spec.Template.Spec.Networks = append(vm.Spec.Template.Spec.Networks, kubevirtv1.Network{Name: networkOption.Name})
+			network = getNetwork(vm, networkOption.Name)
+		}
+
+		switch networkOption.Type {
+		case constants.PodNetworkType:
+			if network.Pod == nil {
+				network.NetworkSource = kubevirtv1.NetworkSource{Pod: &kubevirtv1.PodNetwork{}}
+			}
+		case constants.MultusNetworkType:
+			network.NetworkSource = kubevirtv1.NetworkSource{
+				Multus: &kubevirtv1.MultusNetwork{NetworkName: networkOption.MultusNetworkName},
+			}
+		}
+
+		iface := getInterface(vm, networkOption.Name)
+		if iface == nil {
+			newInterface := kubevirtv1.Interface{Name: networkOption.Name}
+			// model is given by the VF driver for sriov interfaces
+			if networkOption.Binding != constants.SRIOVInterfaceBinding {
+				newInterface.Model = defaultModel
+			}
+			vm.Spec.Template.Spec.Domain.Devices.Interfaces = append(vm.Spec.Template.Spec.Domain.Devices.Interfaces, newInterface)
+			iface = getInterface(vm, networkOption.Name)
+		}
+
+		switch networkOption.Binding {
+		case constants.BridgeInterfaceBinding:
+			iface.InterfaceBindingMethod = kubevirtv1.InterfaceBindingMethod{Bridge: &kubevirtv1.InterfaceBridge{}}
+		case constants.MasqueradeInterfaceBinding:
+			iface.InterfaceBindingMethod = kubevirtv1.InterfaceBindingMethod{Masquerade: &kubevirtv1.InterfaceMasquerade{}}
+		case constants.SRIOVInterfaceBinding:
+			iface.InterfaceBindingMethod = kubevirtv1.InterfaceBindingMethod{SRIOV: &kubevirtv1.InterfaceSRIOV{}}
+		}
+	}
+}
+
+// AddResources overrides CPU topology and memory requests and limits of the VM
+func AddResources(vm *kubevirtv1.VirtualMachine, cliParams *parse.CLIOptions) {
+	domain := &vm.Spec.Template.Spec.Domain
+
+	if cliParams.HasCPUTopology() {
+		if domain.CPU == nil {
+			domain.CPU = &kubevirtv1.CPU{}
+		}
+		if sockets := cliParams.GetCPUSockets(); sockets > 0 {
+			domain.CPU.Sockets = sockets
+		}
+		if cores := cliParams.GetCPUCores(); cores > 0 {
+			domain.CPU.Cores = cores
+		}
+		if threads := cliParams.GetCPUThreads(); threads > 0 {
+			domain.CPU.Threads = threads
+		}
+	}
+
+	if memory := cliParams.GetMemory(); memory != nil {
+		if domain.Resources.Requests == nil {
+			domain.Resources.Requests = v1.ResourceList{}
+		}
+		domain.Resources.Requests[v1.ResourceMemory] = *memory
+	}
+
+	if memoryLimit := cliParams.GetMemoryLimit(); memoryLimit != nil {
+		if domain.Resources.Limits == nil {
+			domain.Resources.Limits = v1.ResourceList{}
+		}
+		domain.Resources.Limits[v1.ResourceMemory] = *memoryLimit
+	}
+}
+
+// AddScheduling overrides nodeSelector, tolerations, affinity and evictionStrategy of the VM
+func AddScheduling(vm *kubevirtv1.VirtualMachine, cliParams *parse.CLIOptions) {
+	spec := &vm.Spec.Template.Spec
+
+	if nodeSelector := cliParams.GetNodeSelector(); len(nodeSelector) > 0 {
+		if spec.NodeSelector == nil {
+			spec.NodeSelector = make(map[string]string, len(nodeSelector))
+		}
+		for key, value := range nodeSelector {
+			spec.NodeSelector[key] = value
+		}
+	}
+
+	for _, toleration := range cliParams.GetTolerations() {
+		replaced := false
+		for i := range spec.Tolerations {
+			if spec.Tolerations[i].Key == toleration.Key && spec.Tolerations[i].Effect == toleration.Effect {
+				spec.Tolerations[i] = toleration
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			spec.Tolerations = append(spec.Tolerations, toleration)
+		}
+	}
+
+	if affinity := cliParams.GetAffinity(); affinity != nil {
+		spec.Affinity = affinity
+	}
+
+	if evictionStrategy := cliParams.GetEvictionStrategy(); evictionStrategy != nil {
+		spec.EvictionStrategy = evictionStrategy
+	}
+}
+
+// AddAccessCredentials adds public keys from secrets to the VM. The cloud-init propagation method
+// requires a configDrive cloud-init volume, so an existing noCloud volume is converted or a new one is added.
+func AddAccessCredentials(vm *kubevirtv1.VirtualMachine, templateValidations *validations.TemplateValidations, cliParams *parse.CLIOptions) {
+	if len(cliParams.SSHPublicKeySecrets) == 0 {
+		return
+	}
+	if templateValidations == nil {
+		templateValidations = validations.NewTemplateValidations(nil)
+	}
+
+	method := cliParams.GetSSHPropagationMethod()
+
+	for _, secretName := range cliParams.SSHPublicKeySecrets {
+		if hasSSHPublicKeySecret(vm, secretName) {
+			continue
+		}
+
+		sshPublicKey := &kubevirtv1.SSHPublicKeyAccessCredential{
+			Source: kubevirtv1.SSHPublicKeyAccessCredentialSource{
+				Secret: &kubevirtv1.AccessCredentialSecretSource{SecretName: secretName},
+			},
+		}
+
+		if method == constants.SSHPropagationQemuGuestAgent {
+			sshPublicKey.PropagationMethod.QemuGuestAgent = &kubevirtv1.QemuGuestAgentSSHPublicKeyAccessCredentialPropagation{
+				Users: cliParams.SSHUsers,
+			}
+		} else {
+			sshPublicKey.PropagationMethod.ConfigDrive = &kubevirtv1.ConfigDriveSSHPublicKeyAccessCredentialPropagation{}
+		}
+
+		vm.Spec.Template.Spec.AccessCredentials = append(vm.Spec.Template.Spec.AccessCredentials, kubevirtv1.AccessCredential{SSHPublicKey: sshPublicKey})
+	}
+
+	if method == constants.SSHPropagationCloudInit {
+		ensureConfigDriveVolume(vm, templateValidations.GetDefaultDiskBus())
+	}
+}
+
+func hasSSHPublicKeySecret(vm *kubevirtv1.VirtualMachine, secretName string) bool {
+	for _, accessCredential := range vm.Spec.Template.Spec.AccessCredentials {
+		if sshPublicKey := accessCredential.SSHPublicKey; sshPublicKey != nil && sshPublicKey.Source.Secret != nil && sshPublicKey.Source.Secret.SecretName == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+func ensureConfigDriveVolume(vm *kubevirtv1.VirtualMachine, defaultBus string) {
+	if volume := getCloudInitVolume(vm); volume != nil {
+		if noCloud := volume.CloudInitNoCloud; noCloud != nil {
+			volume.VolumeSource = kubevirtv1.VolumeSource{
+				CloudInitConfigDrive: &kubevirtv1.CloudInitConfigDriveSource{
+					UserDataSecretRef:    noCloud.UserDataSecretRef,
+					UserDataBase64:       noCloud.UserDataBase64,
+					UserData:             noCloud.UserData,
+					NetworkDataSecretRef: noCloud.NetworkDataSecretRef,
+					NetworkDataBase64:    noCloud.NetworkDataBase64,
+					NetworkData:          noCloud.NetworkData,
+				},
+			}
+		}
+		return
+	}
+
+	if getDisk(vm, cloudInitVolumeName) == nil {
+		vm.Spec.Template.Spec.Domain.Devices.Disks = append(vm.Spec.Template.Spec.Domain.Devices.Disks, kubevirtv1.Disk{
+			Name: cloudInitVolumeName,
+			DiskDevice: kubevirtv1.DiskDevice{
+				Disk: &kubevirtv1.DiskTarget{Bus: defaultBus},
+			},
+		})
+	}
+
+	vm.Spec.Template.Spec.Volumes = append(vm.Spec.Template.Spec.Volumes, kubevirtv1.Volume{
+		Name: cloudInitVolumeName,
+		VolumeSource: kubevirtv1.VolumeSource{
+			CloudInitConfigDrive: &kubevirtv1.CloudInitConfigDriveSource{UserData: emptyCloudConfig},
+		},
+	})
+}
+
+func newCloudInitVolumeSource(cliParams *parse.CLIOptions) kubevirtv1.VolumeSource {
+	var userDataSecretRef, networkDataSecretRef *v1.LocalObjectReference
+
+	if cliParams.CloudInitUserDataSecret != "" {
+		userDataSecretRef = &v1.LocalObjectReference{Name: cliParams.CloudInitUserDataSecret}
+	}
+	if cliParams.CloudInitNetworkDataSecret != "" {
+		networkDataSecretRef = &v1.LocalObjectReference{Name: cliParams.CloudInitNetworkDataSecret}
+	}
+
+	if cliParams.GetCloudInitType() == constants.CloudInitConfigDrive {
+		return kubevirtv1.VolumeSource{
+			CloudInitConfigDrive: &kubevirtv1.CloudInitConfigDriveSource{
+				UserData:             cliParams.CloudInitUserData,
+				UserDataSecretRef:    userDataSecretRef,
+				NetworkData:          cliParams.CloudInitNetworkData,
+				NetworkDataSecretRef: networkDataSecretRef,
+			},
+		}
+	}
+
+	return kubevirtv1.VolumeSource{
+		CloudInitNoCloud: &kubevirtv1.CloudInitNoCloudSource{
+			UserData:             cliParams.CloudInitUserData,
+			UserDataSecretRef:    userDataSecretRef,
+			NetworkData:          cliParams.CloudInitNetworkData,
+			NetworkDataSecretRef: networkDataSecretRef,
+		},
+	}
 }
 
 func AsVMOwnerReference(vm *kubevirtv1.VirtualMachine) metav1.OwnerReference {
@@ -6,8 +6,10 @@ import (
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 	"sort"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/templates/validations"
@@ -188,6 +190,385 @@ var _ = Describe("VM", func() {
 				},
 			))
 		})
+
+		Describe("Adds cloud-init and sysprep", func() {
+			BeforeEach(func() {
+				cliOptions = &parse.CLIOptions{
+					TemplateName:            "test",
+					TemplateNamespace:       "default",
+					VirtualMachineNamespace: "default",
+				}
+			})
+
+			It("adds cloud-init volume", func() {
+				cliOptions.CloudInitUserData = "#cloud-config"
+				cliOptions.CloudInitNetworkDataSecret = "network-data"
+				Expect(cliOptions.Init()).Should(Succeed())
+
+				vm2.AddVolumes(vm, validations.NewTemplateValidations(testobjects.NewTestCommonTemplateValidations(Scsi)), cliOptions)
+
+				Expect(vm.Spec.Template.Spec.Domain.Devices.Disks).To(ConsistOf(kubevirtv1.Disk{
+					Name: "cloudinitdisk",
+					DiskDevice: kubevirtv1.DiskDevice{
+						Disk: &kubevirtv1.DiskTarget{Bus: Scsi},
+					},
+				}))
+				Expect(vm.Spec.Template.Spec.Volumes).To(ConsistOf(kubevirtv1.Volume{
+					Name: "cloudinitdisk",
+					VolumeSource: kubevirtv1.VolumeSource{
+						CloudInitNoCloud: &kubevirtv1.CloudInitNoCloudSource{
+							UserData:             "#cloud-config",
+							NetworkDataSecretRef: &v1.LocalObjectReference{Name: "network-data"},
+						},
+					},
+				}))
+			})
+
+			It("replaces existing cloud-init volume", func() {
+				vm = shtestobjects.NewTestVM().WithCloudConfig(shtestobjects.CloudConfig{}).Build()
+				vm.Spec.Template.Spec.Volumes[0].Name = "mycloudinit"
+				vm.Spec.Template.Spec.Domain.Devices.Disks[0].Name = "mycloudinit"
+				cliOptions.CloudInitType = "configdrive"
+				cliOptions.CloudInitUserDataSecret = "user-data"
+				Expect(cliOptions.Init()).Should(Succeed())
+
+				vm2.AddVolumes(vm, emptyValidations, cliOptions)
+
+				Expect(vm.Spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(1))
+				Expect(vm.Spec.Template.Spec.Volumes).To(ConsistOf(kubevirtv1.Volume{
+					Name: "mycloudinit",
+					VolumeSource: kubevirtv1.VolumeSource{
+						CloudInitConfigDrive: &kubevirtv1.CloudInitConfigDriveSource{
+							UserDataSecretRef: &v1.LocalObjectReference{Name: "user-data"},
+						},
+					},
+				}))
+			})
+
+			table.DescribeTable("adds sysprep volume", func(templateValidations *validations.TemplateValidations, expectedBus string) {
+				cliOptions.SysprepConfigMap = "sysprep-config"
+				Expect(cliOptions.Init()).Should(Succeed())
+
+				vm2.AddVolumes(vm, templateValidations, cliOptions)
+
+				Expect(vm.Spec.Template.Spec.Domain.Devices.Disks).To(ConsistOf(kubevirtv1.Disk{
+					Name: "sysprep",
+					DiskDevice: kubevirtv1.DiskDevice{
+						CDRom: &kubevirtv1.CDRomTarget{Bus: expectedBus},
+					},
+				}))
+				Expect(vm.Spec.Template.Spec.Volumes).To(ConsistOf(kubevirtv1.Volume{
+					Name: "sysprep",
+					VolumeSource: kubevirtv1.VolumeSource{
+						Sysprep: &kubevirtv1.SysprepSource{
+							ConfigMap: &v1.LocalObjectReference{Name: "sysprep-config"},
+						},
+					},
+				}))
+			},
+				table.Entry("no validations", nil, Sata),
+				table.Entry("virtio validations", validations.NewTemplateValidations(testobjects.NewTestCommonTemplateValidations(Virtio)), Sata),
+			)
+		})
+	})
+
+	Describe("Adds boot source", func() {
+		var sourcePVC *v1.PersistentVolumeClaim
+		var size resource.Quantity
+
+		BeforeEach(func() {
+			storageClass := "ceph-rbd"
+			volumeMode := v1.PersistentVolumeBlock
+			sourcePVC = &v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "fedora", Namespace: "os-images"},
+				Spec: v1.PersistentVolumeClaimSpec{
+					AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+					StorageClassName: &storageClass,
+					VolumeMode:       &volumeMode,
+				},
+			}
+			size = resource.MustParse("30Gi")
+		})
+
+		expectRootDiskClone := func(dataVolumeTemplate kubevirtv1.DataVolumeTemplateSpec, dvName string) {
+			Expect(dataVolumeTemplate.Name).To(Equal(dvName))
+			Expect(dataVolumeTemplate.Spec.Source.PVC).To(Equal(&cdiv1.DataVolumeSourcePVC{Namespace: "os-images", Name: "fedora"}))
+			Expect(dataVolumeTemplate.Spec.PVC.AccessModes).To(Equal(sourcePVC.Spec.AccessModes))
+			Expect(dataVolumeTemplate.Spec.PVC.StorageClassName).To(Equal(sourcePVC.Spec.StorageClassName))
+			Expect(dataVolumeTemplate.Spec.PVC.VolumeMode).To(Equal(sourcePVC.Spec.VolumeMode))
+			Expect(dataVolumeTemplate.Spec.PVC.Resources.Requests[v1.ResourceStorage]).To(Equal(size))
+		}
+
+		It("adds a root disk to VM without disks", func() {
+			vm2.AddBootSource(vm, nil, sourcePVC, size)
+
+			dvName := vm.Name + "-rootdisk"
+			Expect(vm.Spec.Template.Spec.Domain.Devices.Disks).To(Equal([]kubevirtv1.Disk{{
+				Name:       "rootdisk",
+				DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: Virtio}},
+			}}))
+			Expect(vm.Spec.Template.Spec.Volumes).To(Equal([]kubevirtv1.Volume{{
+				Name:         "rootdisk",
+				VolumeSource: kubevirtv1.VolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{Name: dvName}},
+			}}))
+			Expect(vm.Spec.DataVolumeTemplates).To(HaveLen(1))
+			expectRootDiskClone(vm.Spec.DataVolumeTemplates[0], dvName)
+		})
+
+		It("replaces the disk with the first boot order", func() {
+			bootOrder := uint(1)
+			vm.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{{Name: "cloudinitdisk"}, {Name: "disk0", BootOrder: &bootOrder}}
+			vm.Spec.Template.Spec.Volumes = []kubevirtv1.Volume{{
+				Name:         "disk0",
+				VolumeSource: kubevirtv1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "my-pvc"}},
+			}}
+
+			vm2.AddBootSource(vm, nil, sourcePVC, size)
+
+			Expect(vm.Spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(2))
+			Expect(vm.Spec.Template.Spec.Volumes).To(Equal([]kubevirtv1.Volume{{
+				Name:         "disk0",
+				VolumeSource: kubevirtv1.VolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{Name: vm.Name + "-disk0"}},
+			}}))
+			Expect(vm.Spec.DataVolumeTemplates).To(HaveLen(1))
+			expectRootDiskClone(vm.Spec.DataVolumeTemplates[0], vm.Name+"-disk0")
+		})
+
+		It("reuses the dataVolumeTemplate of the root disk", func() {
+			vm.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{{Name: "rootdisk"}}
+			vm.Spec.Template.Spec.Volumes = []kubevirtv1.Volume{{
+				Name:         "rootdisk",
+				VolumeSource: kubevirtv1.VolumeSource{DataVolume: &kubevirtv1.DataVolumeSource{Name: "my-vm"}},
+			}}
+			vm.Spec.DataVolumeTemplates = []kubevirtv1.DataVolumeTemplateSpec{
+				{ObjectMeta: metav1.ObjectMeta{Name: "my-vm"}, Spec: cdiv1.DataVolumeSpec{Source: cdiv1.DataVolumeSource{HTTP: &cdiv1.DataVolumeSourceHTTP{URL: "http://images/fedora.qcow2"}}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+			}
+
+			vm2.AddBootSource(vm, nil, sourcePVC, size)
+
+			Expect(vm.Spec.Template.Spec.Volumes[0].DataVolume.Name).To(Equal("my-vm"))
+			Expect(vm.Spec.DataVolumeTemplates).To(HaveLen(2))
+			expectRootDiskClone(vm.Spec.DataVolumeTemplates[0], "my-vm")
+			Expect(vm.Spec.DataVolumeTemplates[1].Name).To(Equal("data"))
+		})
+	})
+
+	Describe("Adds resources and scheduling", func() {
+		It("keeps the VM when there are no overrides", func() {
+			cliOptions := &parse.CLIOptions{
+				TemplateName:            "test",
+				TemplateNamespace:       "default",
+				VirtualMachineNamespace: "default",
+			}
+			Expect(cliOptions.Init()).Should(Succeed())
+			expectedVM := vm.DeepCopy()
+
+			vm2.AddResources(vm, cliOptions)
+			vm2.AddScheduling(vm, cliOptions)
+
+			Expect(vm).To(Equal(expectedVM))
+		})
+
+		It("overrides resources and scheduling", func() {
+			cliOptions := &parse.CLIOptions{
+				TemplateName:            "test",
+				TemplateNamespace:       "default",
+				VirtualMachineNamespace: "default",
+				CPUCores:                "4",
+				CPUThreads:              "2",
+				Memory:                  "2Gi",
+				MemoryLimit:             "4Gi",
+				NodeSelector:            []string{"zone:eu-2", "gpu:true"},
+				Tolerations: []string{
+					`{"key": "dedicated", "operator": "Equal", "value": "vms", "effect": "NoSchedule"}`,
+					`{"key": "maintenance", "operator": "Exists", "effect": "NoExecute"}`,
+				},
+				Affinity:         `{"podAntiAffinity": {"preferredDuringSchedulingIgnoredDuringExecution": [{"weight": 1, "podAffinityTerm": {"topologyKey": "zone"}}]}}`,
+				EvictionStrategy: "LiveMigrate",
+			}
+			Expect(cliOptions.Init()).Should(Succeed())
+
+			spec := &vm.Spec.Template.Spec
+			spec.Domain.CPU = &kubevirtv1.CPU{Sockets: 2, Cores: 1}
+			spec.NodeSelector = map[string]string{"zone": "eu-1", "arch": "amd64"}
+			spec.Tolerations = []v1.Toleration{
+				{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "tests", Effect: v1.TaintEffectNoSchedule},
+				{Key: "dedicated", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute},
+			}
+
+			vm2.AddResources(vm, cliOptions)
+			vm2.AddScheduling(vm, cliOptions)
+
+			Expect(spec.Domain.CPU).To(Equal(&kubevirtv1.CPU{Sockets: 2, Cores: 4, Threads: 2}))
+			Expect(spec.Domain.Resources.Requests[v1.ResourceMemory]).To(Equal(resource.MustParse("2Gi")))
+			Expect(spec.Domain.Resources.Limits[v1.ResourceMemory]).To(Equal(resource.MustParse("4Gi")))
+			Expect(spec.NodeSelector).To(Equal(map[string]string{"zone": "eu-2", "arch": "amd64", "gpu": "true"}))
+			Expect(spec.Tolerations).To(Equal([]v1.Toleration{
+				{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "vms", Effect: v1.TaintEffectNoSchedule},
+				{Key: "dedicated", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute},
+				{Key: "maintenance", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute},
+			}))
+			Expect(spec.Affinity).To(Equal(&v1.Affinity{
+				PodAntiAffinity: &v1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+						{Weight: 1, PodAffinityTerm: v1.PodAffinityTerm{TopologyKey: "zone"}},
+					},
+				},
+			}))
+			Expect(*spec.EvictionStrategy).To(Equal(kubevirtv1.EvictionStrategyLiveMigrate))
+		})
+	})
+
+	Describe("Adds networks", func() {
+		var cliOptions *parse.CLIOptions
+
+		BeforeEach(func() {
+			cliOptions = &parse.CLIOptions{
+				TemplateName:            "test",
+				TemplateNamespace:       "default",
+				VirtualMachineNamespace: "default",
+				Networks:                []string{"default:pod", "nic1:multus:my-ns/my-nad", "nic2:multus:sriov-nad:sriov"},
+			}
+			Expect(cliOptions.Init()).Should(Succeed())
+		})
+
+		It("replaces existing and adds new networks", func() {
+			modelValidations := validations.NewTemplateValidations([]validations.CommonTemplateValidation{{
+				Name:   "interface-model",
+				Rule:   "enum",
+				Path:   "jsonpath::.spec.domain.devices.interfaces[*].model",
+				Values: []string{"e1000e"},
+			}})
+			Expect(vm.Spec.Template.Spec.Networks).To(HaveLen(1))
+			existingInterface := vm.Spec.Template.Spec.Domain.Devices.Interfaces[0]
+			Expect(existingInterface.Bridge).ToNot(BeNil())
+
+			vm2.AddNetworks(vm, modelValidations, cliOptions)
+
+			Expect(vm.Spec.Template.Spec.Networks).To(Equal([]kubevirtv1.Network{
+				{
+					Name:          "default",
+					NetworkSource: kubevirtv1.NetworkSource{Pod: &kubevirtv1.PodNetwork{}},
+				},
+				{
+					Name:          "nic1",
+					NetworkSource: kubevirtv1.NetworkSource{Multus: &kubevirtv1.MultusNetwork{NetworkName: "my-ns/my-nad"}},
+				},
+				{
+					Name:          "nic2",
+					NetworkSource: kubevirtv1.NetworkSource{Multus: &kubevirtv1.MultusNetwork{NetworkName: "sriov-nad"}},
+				},
+			}))
+
+			existingInterface.InterfaceBindingMethod = kubevirtv1.InterfaceBindingMethod{Masquerade: &kubevirtv1.InterfaceMasquerade{}}
+			Expect(vm.Spec.Template.Spec.Domain.Devices.Interfaces).To(Equal([]kubevirtv1.Interface{
+				existingInterface,
+				{
+					Name:                   "nic1",
+					Model:                  "e1000e",
+					InterfaceBindingMethod: kubevirtv1.InterfaceBindingMethod{Bridge: &kubevirtv1.InterfaceBridge{}},
+				},
+				{
+					Name:                   "nic2",
+					InterfaceBindingMethod: kubevirtv1.InterfaceBindingMethod{SRIOV: &kubevirtv1.InterfaceSRIOV{}},
+				},
+			}))
+		})
+	})
+
+	Describe("Adds access credentials", func() {
+		var cliOptions *parse.CLIOptions
+
+		BeforeEach(func() {
+			cliOptions = &parse.CLIOptions{
+				TemplateName:            "test",
+				TemplateNamespace:       "default",
+				VirtualMachineNamespace: "default",
+				SSHPublicKeySecrets:     []string{"public-key1", "public-key2"},
+			}
+		})
+
+		newSSHPublicKey := func(secretName string, propagationMethod kubevirtv1.SSHPublicKeyAccessCredentialPropagationMethod) kubevirtv1.AccessCredential {
+			return kubevirtv1.AccessCredential{
+				SSHPublicKey: &kubevirtv1.SSHPublicKeyAccessCredential{
+					Source: kubevirtv1.SSHPublicKeyAccessCredentialSource{
+						Secret: &kubevirtv1.AccessCredentialSecretSource{SecretName: secretName},
+					},
+					PropagationMethod: propagationMethod,
+				},
+			}
+		}
+
+		It("adds nothing without secrets", func() {
+			cliOptions.SSHPublicKeySecrets = nil
+			Expect(cliOptions.Init()).Should(Succeed())
+
+			vm2.AddAccessCredentials(vm, nil, cliOptions)
+			Expect(vm.Spec.Template.Spec.AccessCredentials).To(BeEmpty())
+			Expect(vm.Spec.Template.Spec.Volumes).To(BeEmpty())
+		})
+
+		It("adds qemu guest agent access credentials", func() {
+			cliOptions.SSHPropagationMethod = "qemu-guest-agent"
+			cliOptions.SSHUsers = []string{"fedora"}
+			Expect(cliOptions.Init()).Should(Succeed())
+
+			vm2.AddAccessCredentials(vm, nil, cliOptions)
+
+			propagationMethod := kubevirtv1.SSHPublicKeyAccessCredentialPropagationMethod{
+				QemuGuestAgent: &kubevirtv1.QemuGuestAgentSSHPublicKeyAccessCredentialPropagation{Users: []string{"fedora"}},
+			}
+			Expect(vm.Spec.Template.Spec.AccessCredentials).To(Equal([]kubevirtv1.AccessCredential{
+				newSSHPublicKey("public-key1", propagationMethod),
+				newSSHPublicKey("public-key2", propagationMethod),
+			}))
+			Expect(vm.Spec.Template.Spec.Volumes).To(BeEmpty())
+		})
+
+		It("adds cloud-init access credentials with new config drive", func() {
+			Expect(cliOptions.Init()).Should(Succeed())
+
+			vm2.AddAccessCredentials(vm, validations.NewTemplateValidations(testobjects.NewTestCommonTemplateValidations(Sata)), cliOptions)
+			vm2.AddAccessCredentials(vm, nil, cliOptions) // should not add credentials twice
+
+			propagationMethod := kubevirtv1.SSHPublicKeyAccessCredentialPropagationMethod{
+				ConfigDrive: &kubevirtv1.ConfigDriveSSHPublicKeyAccessCredentialPropagation{},
+			}
+			Expect(vm.Spec.Template.Spec.AccessCredentials).To(Equal([]kubevirtv1.AccessCredential{
+				newSSHPublicKey("public-key1", propagationMethod),
+				newSSHPublicKey("public-key2", propagationMethod),
+			}))
+			Expect(vm.Spec.Template.Spec.Domain.Devices.Disks).To(ConsistOf(kubevirtv1.Disk{
+				Name: "cloudinitdisk",
+				DiskDevice: kubevirtv1.DiskDevice{
+					Disk: &kubevirtv1.DiskTarget{Bus: Sata},
+				},
+			}))
+			Expect(vm.Spec.Template.Spec.Volumes).To(ConsistOf(kubevirtv1.Volume{
+				Name: "cloudinitdisk",
+				VolumeSource: kubevirtv1.VolumeSource{
+					CloudInitConfigDrive: &kubevirtv1.CloudInitConfigDriveSource{UserData: "#cloud-config\n"},
+				},
+			}))
+		})
+
+		It("converts no cloud volume to config drive", func() {
+			vm = shtestobjects.NewTestVM().WithCloudConfig(shtestobjects.CloudConfig{}).Build()
+			userData := vm.Spec.Template.Spec.Volumes[0].CloudInitNoCloud.UserData
+			Expect(cliOptions.Init()).Should(Succeed())
+
+			vm2.AddAccessCredentials(vm, nil, cliOptions)
+
+			Expect(vm.Spec.Template.Spec.AccessCredentials).To(HaveLen(2))
+			Expect(vm.Spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(1))
+			Expect(vm.Spec.Template.Spec.Volumes).To(ConsistOf(kubevirtv1.Volume{
+				Name: "cloudinitdisk",
+				VolumeSource: kubevirtv1.VolumeSource{
+					CloudInitConfigDrive: &kubevirtv1.CloudInitConfigDriveSource{UserData: userData},
+				},
+			}))
+		})
 	})
 
 	It("Adds correct metadata from template", func() {
@@ -2,19 +2,32 @@ package vmcreator
 
 import (
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/datasource"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/datavolume"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/pvc"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/rollback"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/templates"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/templates/validations"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
 	virtualMachine "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vm"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vmi"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vmiwaiter"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/events"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
+	templateapi "github.com/openshift/api/template/v1"
 	templatev1 "github.com/openshift/client-go/template/clientset/versioned/typed/template/v1"
 	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
@@ -23,6 +36,11 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+const (
+	vmDeletionPollInterval = 2 * time.Second
+	vmDeletionTimeout      = 5 * time.Minute
+)
+
 type VMCreator struct {
 	targetNamespace        string
 	cliOptions             *parse.CLIOptions
@@ -30,7 +48,13 @@ type VMCreator struct {
 	templateProvider       templates.TemplateProvider
 	virtualMachineProvider virtualMachine.VirtualMachineProvider
 	dataVolumeProvider     datavolume.DataVolumeProvider
+	dataSourceProvider     datasource.DataSourceProvider
 	pvcProvider            pvc.PersistentVolumeClaimProvider
+	vmiWaiter              *vmiwaiter.VirtualMachineInstanceWaiter
+	rollback               *rollback.Rollback
+	eventRecorder          events.Recorder
+	skippedExistingVMs     map[string]bool
+	lock                   sync.Mutex
 }
 
 func NewVMCreator(cliOptions *parse.CLIOptions) (*VMCreator, error) {
@@ -51,14 +75,33 @@ func NewVMCreator(cliOptions *parse.CLIOptions) (*VMCreator, error) {
 	}
 
 	var templateProvider templates.TemplateProvider
-	virtualMachineProvider := virtualMachine.NewVirtualMachineProvider(kubevirtClient)
+	virtualMachineProvider := virtualMachine.NewVirtualMachineProvider(kubevirtClient, cliOptions.GetInstancetype(), cliOptions.GetPreference())
 	dataVolumeProvider := datavolume.NewDataVolumeProvider(cdiClient)
+	dataSourceProvider := datasource.NewDataSourceProvider(cdiClient)
 	pvcProvider := pvc.NewPersistentVolumeClaimProvider(kubeClient.CoreV1())
+	vmiWaiter := vmiwaiter.NewVirtualMachineInstanceWaiter(cliOptions, vmi.NewVirtualMachineInstanceProvider(kubevirtClient))
 
 	if cliOptions.GetCreationMode() == constants.TemplateCreationMode {
+		available, err := templates.IsTemplateAPIAvailable(kubeClient.Discovery())
+		if err != nil {
+			return nil, fmt.Errorf("cannot discover %v API: %v", templateapi.GroupName, err.Error())
+		}
+		if !available {
+			return nil, zerrors.NewSoftError("%v API is not available in the cluster: VM can be created only from a VM manifest", templateapi.GroupName)
+		}
 		templateProvider = templates.NewTemplateProvider(templatev1.NewForConfigOrDie(config))
 	}
 
+	if cliOptions.GetCreationMode() == constants.InstancetypeCreationMode {
+		available, err := virtualMachine.IsInstancetypeAPIAvailable(kubeClient.Discovery())
+		if err != nil {
+			return nil, fmt.Errorf("cannot discover instancetype.kubevirt.io API: %v", err.Error())
+		}
+		if !available {
+			return nil, zerrors.NewSoftError("instancetype.kubevirt.io API is not available in the cluster: VM cannot be created from an instancetype")
+		}
+	}
+
 	return &VMCreator{
 		targetNamespace:        targetNS,
 		cliOptions:             cliOptions,
@@ -66,25 +109,117 @@ func NewVMCreator(cliOptions *parse.CLIOptions) (*VMCreator, error) {
 		templateProvider:       templateProvider,
 		virtualMachineProvider: virtualMachineProvider,
 		dataVolumeProvider:     dataVolumeProvider,
+		dataSourceProvider:     dataSourceProvider,
 		pvcProvider:            pvcProvider,
+		vmiWaiter:              vmiWaiter,
+		rollback:               rollback.NewRollback(),
+		eventRecorder:          events.NewRecorder(kubeClient.CoreV1()),
+		skippedExistingVMs:     map[string]bool{},
 	}, nil
 }
 
-func (v *VMCreator) StartVM(namespace, name string) error {
-	return v.virtualMachineProvider.Start(namespace, name)
+func (v *VMCreator) StartVM(vm *kubevirtv1.VirtualMachine) error {
+	namespace, name := vm.Namespace, vm.Name
+	if err := v.virtualMachineProvider.Start(namespace, name); err != nil {
+		return err
+	}
+	v.eventRecorder.Eventf(kubevirtv1.VirtualMachineGroupVersionKind, vm, events.StartedByTektonTask, "VM was started by a Tekton task")
+
+	v.rollback.Add(fmt.Sprintf("start of %v VM", name), func() error {
+		return v.virtualMachineProvider.Stop(namespace, name)
+	})
+	return nil
+}
+
+// Rollback undoes the changes made to the cluster in reverse order: added owner references, started, patched and created VMs
+func (v *VMCreator) Rollback() error {
+	log.Logger().Info("rolling back changes", zap.Int("changes", v.rollback.Len()))
+	return v.rollback.Run()
 }
 
-func (v *VMCreator) CreateVM() (*kubevirtv1.VirtualMachine, error) {
+func (v *VMCreator) WaitForReady(vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachineInstance, error) {
+	log.Logger().Info("waiting for VMI to be ready", zap.String("name", vm.Name), zap.String("namespace", vm.Namespace),
+		zap.Bool("waitForGuestAgent", v.cliOptions.GetWaitForGuestAgent()))
+	return v.vmiWaiter.WaitForReady(vm.Namespace, vm.Name)
+}
+
+// CreateVMs creates count VMs concurrently. Returns the VMs which were created in the order of their index
+// and a MultiError with a failure of each VM which was not created. A single VM returns its error directly.
+func (v *VMCreator) CreateVMs() ([]*kubevirtv1.VirtualMachine, error) {
+	var template *templateapi.Template
+	var err error
+
 	switch v.cliOptions.GetCreationMode() {
 	case constants.TemplateCreationMode:
-		return v.createVMFromTemplate()
-	case constants.VMManifestCreationMode:
-		return v.createVMFromManifest()
+		if template, err = v.getTemplate(); err != nil {
+			return nil, err
+		}
+	case constants.VMManifestCreationMode, constants.InstancetypeCreationMode:
+	default:
+		return nil, zerrors.NewMissingRequiredError("unknown creation mode: %v", v.cliOptions.GetCreationMode())
+	}
+
+	createVM := func(index int) (*kubevirtv1.VirtualMachine, error) {
+		if template != nil {
+			return v.createVMFromTemplate(template, index)
+		}
+		if v.cliOptions.GetCreationMode() == constants.InstancetypeCreationMode {
+			return v.createVMFromInstancetype(index)
+		}
+		return v.createVMFromManifest(index)
 	}
-	return nil, zerrors.NewMissingRequiredError("unknown creation mode: %v", v.cliOptions.GetCreationMode())
+
+	if !v.cliOptions.IsBatch() {
+		vm, err := createVM(0)
+		if err != nil {
+			return nil, err
+		}
+		return []*kubevirtv1.VirtualMachine{vm}, nil
+	}
+
+	count := v.cliOptions.GetCount()
+	vms := make([]*kubevirtv1.VirtualMachine, count)
+	errs := make([]error, count)
+
+	log.Logger().Info("creating VMs", zap.Int("count", count), zap.Int("concurrency", v.cliOptions.GetConcurrency()))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, v.cliOptions.GetConcurrency())
+
+	for index := 0; index < count; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			vms[index], errs[index] = createVM(index)
+		}(index)
+	}
+	wg.Wait()
+
+	var createdVMs []*kubevirtv1.VirtualMachine
+	var multiError zerrors.MultiError
+
+	for index := 0; index < count; index++ {
+		if errs[index] != nil {
+			multiError.Add(strconv.Itoa(index), newVMError(index, errs[index]))
+		} else {
+			createdVMs = append(createdVMs, vms[index])
+		}
+	}
+
+	return createdVMs, multiError.AsOptional()
 }
 
-func (v *VMCreator) createVMFromManifest() (*kubevirtv1.VirtualMachine, error) {
+// newVMError prefixes the error with the VM index and keeps its softness
+func newVMError(index int, err error) error {
+	if zerrors.IsErrorSoft(err) {
+		return zerrors.NewSoftError("VM %v: %v", index, err.Error())
+	}
+	return fmt.Errorf("VM %v: %v", index, err.Error())
+}
+
+func (v *VMCreator) createVMFromManifest(index int) (*kubevirtv1.VirtualMachine, error) {
 	var vm kubevirtv1.VirtualMachine
 
 	if err := yaml.Unmarshal([]byte(v.cliOptions.VirtualMachineManifest), &vm); err != nil {
@@ -92,24 +227,43 @@ func (v *VMCreator) createVMFromManifest() (*kubevirtv1.VirtualMachine, error) {
 	}
 
 	vm.Namespace = v.targetNamespace
+	vm.Name = v.cliOptions.GetVMName(vm.Name, index)
 	virtualMachine.AddMetadata(&vm, nil)
 
 	templateValidations := validations.NewTemplateValidations(nil) // fallback to defaults
 	virtualMachine.AddVolumes(&vm, templateValidations, v.cliOptions)
+	if err := v.addBootSource(&vm, templateValidations); err != nil {
+		return nil, err
+	}
+	virtualMachine.AddResources(&vm, v.cliOptions)
+	virtualMachine.AddScheduling(&vm, v.cliOptions)
+	virtualMachine.AddNetworks(&vm, templateValidations, v.cliOptions)
+	virtualMachine.AddAccessCredentials(&vm, templateValidations, v.cliOptions)
 
-	log.Logger().Debug("creating VM", zap.Reflect("vm", vm))
-	return v.virtualMachineProvider.Create(v.targetNamespace, &vm)
+	return v.createVM(&vm)
 }
 
-func (v *VMCreator) createVMFromTemplate() (*kubevirtv1.VirtualMachine, error) {
-	log.Logger().Debug("retrieving template", zap.String("name", v.cliOptions.TemplateName), zap.String("namespace", v.cliOptions.GetTemplateNamespace()))
-	template, err := v.templateProvider.Get(v.cliOptions.GetTemplateNamespace(), v.cliOptions.TemplateName)
-	if err != nil {
+// createVMFromInstancetype creates a VM with the instancetype and preference and with the boot source as its root disk.
+// The instancetype and preference are set by the VirtualMachineProvider.
+func (v *VMCreator) createVMFromInstancetype(index int) (*kubevirtv1.VirtualMachine, error) {
+	vm := virtualMachine.NewInstancetypeVM(v.cliOptions.GetVMName(v.cliOptions.GetVirtualMachineName(), index), v.targetNamespace)
+	virtualMachine.AddMetadata(vm, nil)
+
+	templateValidations := validations.NewTemplateValidations(nil) // fallback to defaults
+	virtualMachine.AddVolumes(vm, templateValidations, v.cliOptions)
+	if err := v.addBootSource(vm, templateValidations); err != nil {
 		return nil, err
 	}
+	virtualMachine.AddScheduling(vm, v.cliOptions)
+	virtualMachine.AddNetworks(vm, templateValidations, v.cliOptions)
+	virtualMachine.AddAccessCredentials(vm, templateValidations, v.cliOptions)
 
-	log.Logger().Debug("processing template", zap.String("name", v.cliOptions.TemplateName), zap.String("namespace", v.cliOptions.GetTemplateNamespace()))
-	processedTemplate, err := v.templateProvider.Process(v.targetNamespace, template, v.cliOptions.GetTemplateParams())
+	return v.createVM(vm)
+}
+
+func (v *VMCreator) createVMFromTemplate(template *templateapi.Template, index int) (*kubevirtv1.VirtualMachine, error) {
+	log.Logger().Debug("processing template", zap.String("name", template.Name), zap.String("namespace", template.Namespace), zap.Int("index", index))
+	processedTemplate, err := v.templateProvider.Process(v.targetNamespace, template, v.cliOptions.GetIndexedTemplateParams(index))
 	if err != nil {
 		return nil, err
 	}
@@ -128,12 +282,265 @@ func (v *VMCreator) createVMFromTemplate() (*kubevirtv1.VirtualMachine, error) {
 	}
 
 	vm.Namespace = v.targetNamespace
+	vm.Name = v.cliOptions.GetVMName(vm.Name, index)
 
 	virtualMachine.AddMetadata(vm, processedTemplate)
 	virtualMachine.AddVolumes(vm, templateValidations, v.cliOptions)
+	if err := v.addBootSource(vm, templateValidations); err != nil {
+		return nil, err
+	}
+	virtualMachine.AddResources(vm, v.cliOptions)
+	virtualMachine.AddScheduling(vm, v.cliOptions)
+	virtualMachine.AddNetworks(vm, templateValidations, v.cliOptions)
+	virtualMachine.AddAccessCredentials(vm, templateValidations, v.cliOptions)
+
+	log.Logger().Debug("evaluating template validations", zap.String("name", template.Name))
+	if err := templateValidations.Validate(vm); err != nil {
+		return nil, err
+	}
+
+	return v.createVM(vm)
+}
+
+// addBootSource resolves the boot source to a PVC and clones it into the root disk of the VM
+func (v *VMCreator) addBootSource(vm *kubevirtv1.VirtualMachine, templateValidations *validations.TemplateValidations) error {
+	bootSource := v.cliOptions.GetBootSource()
+	if bootSource == nil {
+		return nil
+	}
+
+	if vm.GetName() == "" {
+		return zerrors.NewSoftError("VM name is required to create a root disk from %v:%v/%v boot source", bootSource.Kind, bootSource.Namespace, bootSource.Name)
+	}
+
+	sourceNamespace, sourceName := bootSource.Namespace, bootSource.Name
+	switch bootSource.Kind {
+	case constants.DataSourceBootSourceKind:
+		log.Logger().Debug("resolving DataSource", zap.String("name", sourceName), zap.String("namespace", sourceNamespace))
+		sourcePVC, err := v.dataSourceProvider.GetSourcePVC(sourceNamespace, sourceName)
+		if err != nil {
+			return err
+		}
+		sourceNamespace, sourceName = sourcePVC.Namespace, sourcePVC.Name
+	case constants.DataVolumeBootSourceKind:
+		// the PVC of a DataVolume has the same name
+		log.Logger().Debug("resolving DataVolume", zap.String("name", sourceName), zap.String("namespace", sourceNamespace))
+		if _, err := v.dataVolumeProvider.GetByName(sourceNamespace, sourceName); err != nil {
+			return err
+		}
+	}
+
+	pvcs, err := v.pvcProvider.GetByName(sourceNamespace, sourceName)
+	if err != nil {
+		return err
+	}
+	sourcePVC := pvcs[0]
+
+	size := sourcePVC.Spec.Resources.Requests[v1.ResourceStorage]
+	if rootDiskSize := v.cliOptions.GetRootDiskSize(); rootDiskSize != nil {
+		if rootDiskSize.Cmp(size) < 0 {
+			return zerrors.NewSoftError("root disk size %v should not be lower than %v size of %v/%v PVC", rootDiskSize.String(), size.String(), sourceNamespace, sourceName)
+		}
+		size = *rootDiskSize
+	}
+
+	log.Logger().Debug("adding root disk clone", zap.String("sourceName", sourceName), zap.String("sourceNamespace", sourceNamespace), zap.String("size", size.String()))
+	virtualMachine.AddBootSource(vm, templateValidations, sourcePVC, size)
+	return nil
+}
+
+func (v *VMCreator) getTemplate() (*templateapi.Template, error) {
+	if !v.cliOptions.HasTemplateSelector() {
+		log.Logger().Debug("retrieving template", zap.String("name", v.cliOptions.TemplateName), zap.String("namespace", v.cliOptions.GetTemplateNamespace()))
+		return v.templateProvider.Get(v.cliOptions.GetTemplateNamespace(), v.cliOptions.TemplateName)
+	}
+
+	selector := v.cliOptions.GetTemplateSelector()
+	log.Logger().Debug("looking up templates", zap.String("selector", selector), zap.String("namespace", v.cliOptions.GetTemplateNamespace()))
+	matchingTemplates, err := v.templateProvider.List(v.cliOptions.GetTemplateNamespace(), selector)
+	if err != nil {
+		return nil, err
+	}
+
+	template := templates.GetNewestTemplate(matchingTemplates)
+	if template == nil {
+		return nil, zerrors.NewSoftError("no template matches %v selector", selector)
+	}
+
+	log.Logger().Info("using template", zap.String("name", template.Name), zap.String("namespace", template.Namespace), zap.Int("matchingTemplates", len(matchingTemplates)))
+	return template, nil
+}
+
+func (v *VMCreator) createVM(vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error) {
+	switch v.cliOptions.GetDryRun() {
+	case constants.DryRunClient:
+		log.Logger().Debug("skipping VM creation in client dry run", zap.Reflect("vm", vm))
+		vm.SetGroupVersionKind(kubevirtv1.VirtualMachineGroupVersionKind)
+		return vm, nil
+	case constants.DryRunServer:
+		log.Logger().Debug("creating VM in server dry run", zap.Reflect("vm", vm))
+		return v.virtualMachineProvider.CreateDryRun(v.targetNamespace, vm)
+	}
 
 	log.Logger().Debug("creating VM", zap.Reflect("vm", vm))
-	return v.virtualMachineProvider.Create(v.targetNamespace, vm)
+	newVM, err := v.virtualMachineProvider.Create(v.targetNamespace, vm)
+	if err != nil && errors.IsAlreadyExists(err) {
+		return v.handleExistingVM(vm, err)
+	}
+	if err == nil {
+		v.addCreatedVMRollback(newVM)
+		v.eventRecorder.Eventf(kubevirtv1.VirtualMachineGroupVersionKind, newVM, events.CreatedByTektonTask, "VM was created by a Tekton task")
+	}
+	return newVM, err
+}
+
+func (v *VMCreator) addCreatedVMRollback(vm *kubevirtv1.VirtualMachine) {
+	v.rollback.Add(fmt.Sprintf("creation of %v VM", vm.Name), func() error {
+		log.Logger().Debug("deleting VM", zap.String("name", vm.Name), zap.String("namespace", v.targetNamespace))
+		// the VM is gone only after its volumes are deleted, so that the rollback leaves nothing behind
+		if err := v.virtualMachineProvider.Delete(v.targetNamespace, vm.Name, metav1.DeletePropagationForeground); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return v.waitForVMDeletion(vm.Name)
+	})
+}
+
+// patchExistingVM applies the VM and records the previous spec, labels and annotations of the existing VM, which are restored on rollback.
+// The spec is read with the instancetype and the preference, so that the rollback restores them too.
+func (v *VMCreator) patchExistingVM(vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error) {
+	existingVM, err := v.virtualMachineProvider.GetWithInstancetype(v.targetNamespace, vm.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	patchedVM, err := v.virtualMachineProvider.Apply(v.targetNamespace, vm)
+	if err != nil {
+		return nil, err
+	}
+
+	v.rollback.Add(fmt.Sprintf("patch of %v VM", vm.Name), func() error {
+		currentVM, err := v.virtualMachineProvider.GetWithInstancetype(v.targetNamespace, vm.Name)
+		if err != nil {
+			return err
+		}
+		// the update fails with a conflict if the VM changes after it was fetched
+		currentVM.Labels = existingVM.Labels
+		currentVM.Annotations = existingVM.Annotations
+		currentVM.Spec = existingVM.Spec
+		_, err = v.virtualMachineProvider.Update(v.targetNamespace, currentVM)
+		return err
+	})
+	v.eventRecorder.Eventf(kubevirtv1.VirtualMachineGroupVersionKind, patchedVM, events.ModifiedByTektonTask, "VM was patched by a Tekton task")
+	return patchedVM, nil
+}
+
+// SkippedExistingVM returns true if the VM was not created because it already existed
+func (v *VMCreator) SkippedExistingVM(vm *kubevirtv1.VirtualMachine) bool {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.skippedExistingVMs[vm.Name]
+}
+
+func (v *VMCreator) handleExistingVM(vm *kubevirtv1.VirtualMachine, alreadyExistsErr error) (*kubevirtv1.VirtualMachine, error) {
+	switch v.cliOptions.GetIfExists() {
+	case constants.IfExistsSkip:
+		log.Logger().Info("VM already exists: skipping creation", zap.String("name", vm.Name), zap.String("namespace", v.targetNamespace))
+		existingVM, err := v.virtualMachineProvider.Get(v.targetNamespace, vm.Name)
+		if err != nil {
+			return nil, err
+		}
+		v.lock.Lock()
+		v.skippedExistingVMs[existingVM.Name] = true
+		v.lock.Unlock()
+		return existingVM, nil
+	case constants.IfExistsPatch:
+		log.Logger().Info("VM already exists: applying the VM", zap.String("name", vm.Name), zap.String("namespace", v.targetNamespace))
+		return v.patchExistingVM(vm)
+	case constants.IfExistsReplace:
+		log.Logger().Info("VM already exists: replacing the VM", zap.String("name", vm.Name), zap.String("namespace", v.targetNamespace))
+		if err := v.deleteExistingVM(vm.Name); err != nil {
+			return nil, err
+		}
+		log.Logger().Debug("creating VM", zap.Reflect("vm", vm))
+		newVM, err := v.virtualMachineProvider.Create(v.targetNamespace, vm)
+		if err == nil {
+			v.addCreatedVMRollback(newVM)
+			v.eventRecorder.Eventf(kubevirtv1.VirtualMachineGroupVersionKind, newVM, events.ReplacedByTektonTask, "VM was deleted and created again by a Tekton task")
+		}
+		return newVM, err
+	}
+
+	return nil, alreadyExistsErr
+}
+
+// deleteExistingVM releases the volumes owned by this task first, so they are not deleted together with the VM
+func (v *VMCreator) deleteExistingVM(name string) error {
+	existingVM, err := v.virtualMachineProvider.Get(v.targetNamespace, name)
+	if err != nil {
+		return err
+	}
+
+	if err := v.disownVolumes(existingVM); err != nil {
+		return err
+	}
+
+	log.Logger().Debug("deleting VM", zap.String("name", name), zap.String("namespace", v.targetNamespace))
+	if err := v.virtualMachineProvider.Delete(v.targetNamespace, name, metav1.DeletePropagationBackground); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return v.waitForVMDeletion(name)
+}
+
+func (v *VMCreator) waitForVMDeletion(name string) error {
+	err := wait.PollImmediate(vmDeletionPollInterval, vmDeletionTimeout, func() (bool, error) {
+		_, err := v.virtualMachineProvider.Get(v.targetNamespace, name)
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for %v VM to be deleted", name)
+	}
+	return err
+}
+
+func (v *VMCreator) disownVolumes(vm *kubevirtv1.VirtualMachine) error {
+	var multiError zerrors.MultiError
+
+	ownDVs := v.cliOptions.GetOwnDVNames()
+	log.Logger().Debug("releasing ownership of DataVolumes", zap.Strings("own-dvs", ownDVs))
+	dvs, dvsErr := v.dataVolumeProvider.GetByName(v.targetNamespace, ownDVs...)
+
+	for idx, dvName := range ownDVs {
+		if err := zerrors.GetErrorFromMultiError(dvsErr, dvName); err != nil {
+			multiError.Add(dvName, err)
+			continue
+		}
+
+		if _, err := v.dataVolumeProvider.RemoveOwnerReferences(dvs[idx], vm.UID); err != nil {
+			multiError.Add(dvName, fmt.Errorf("could not remove owner reference from %v DataVolume: %v", dvName, err.Error()))
+		}
+	}
+
+	ownPVCs := v.cliOptions.GetOwnPVCNames()
+	log.Logger().Debug("releasing ownership of PersistentVolumeClaims", zap.Strings("own-pvcs", ownPVCs))
+	pvcs, pvcsErr := v.pvcProvider.GetByName(v.targetNamespace, ownPVCs...)
+
+	for idx, pvcName := range ownPVCs {
+		if err := zerrors.GetErrorFromMultiError(pvcsErr, pvcName); err != nil {
+			multiError.Add(pvcName, err)
+			continue
+		}
+
+		if _, err := v.pvcProvider.RemoveOwnerReferences(pvcs[idx], vm.UID); err != nil {
+			multiError.Add(pvcName, fmt.Errorf("could not remove owner reference from %v PersistentVolumeClaim: %v", pvcName, err.Error()))
+		}
+	}
+
+	return multiError.AsOptional()
 }
 
 func (v *VMCreator) CheckVolumesExist() error {
@@ -173,10 +580,17 @@ func (v *VMCreator) ownDataVolumes(vm *kubevirtv1.VirtualMachine) error {
 			continue
 		}
 
-		if _, err := v.dataVolumeProvider.AddOwnerReferences(dvs[idx], virtualMachine.AsVMOwnerReference(vm)); err != nil {
+		ownedDV, err := v.dataVolumeProvider.AddOwnerReferences(dvs[idx], virtualMachine.AsVMOwnerReference(vm))
+		if err != nil {
 			multiError.Add(dvName, fmt.Errorf("could not add owner reference to %v DataVolume: %v", dvName, err.Error()))
+			continue
 		}
 
+		v.rollback.Add(fmt.Sprintf("owner reference of %v DataVolume", dvName), func() error {
+			_, err := v.dataVolumeProvider.RemoveOwnerReferences(ownedDV, vm.UID)
+			return err
+		})
+
 	}
 
 	return multiError.AsOptional()
@@ -195,9 +609,16 @@ func (v *VMCreator) ownPersistentVolumeClaims(vm *kubevirtv1.VirtualMachine) err
 			continue
 		}
 
-		if _, err := v.pvcProvider.AddOwnerReferences(pvcs[idx], virtualMachine.AsVMOwnerReference(vm)); err != nil {
+		ownedPVC, err := v.pvcProvider.AddOwnerReferences(pvcs[idx], virtualMachine.AsVMOwnerReference(vm))
+		if err != nil {
 			multiError.Add(pvcName, fmt.Errorf("could not add owner reference to %v PersistentVolumeClaim: %v", pvcName, err.Error()))
+			continue
 		}
+
+		v.rollback.Add(fmt.Sprintf("owner reference of %v PersistentVolumeClaim", pvcName), func() error {
+			_, err := v.pvcProvider.RemoveOwnerReferences(ownedPVC, vm.UID)
+			return err
+		})
 	}
 
 	return multiError.AsOptional()
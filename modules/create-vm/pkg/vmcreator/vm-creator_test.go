@@ -0,0 +1,166 @@
+package vmcreator
+
+import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/k8s"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/rollback"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
+	virtualMachine "github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vm"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	datavolumev1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+)
+
+// fakeCluster holds the volumes of a namespace and deletes the volumes which are still owned by a deleted VM, like the garbage collector
+type fakeCluster struct {
+	dvs  map[string]*datavolumev1beta1.DataVolume
+	pvcs map[string]*v1.PersistentVolumeClaim
+}
+
+func (c *fakeCluster) collectGarbage(ownerUID types.UID) {
+	for name, dv := range c.dvs {
+		if len(k8s.RemoveOwnerReferences(dv.OwnerReferences, ownerUID)) != len(dv.OwnerReferences) {
+			delete(c.dvs, name)
+		}
+	}
+	for name, pvc := range c.pvcs {
+		if len(k8s.RemoveOwnerReferences(pvc.OwnerReferences, ownerUID)) != len(pvc.OwnerReferences) {
+			delete(c.pvcs, name)
+		}
+	}
+}
+
+type fakeDataVolumeProvider struct {
+	cluster *fakeCluster
+}
+
+func (f *fakeDataVolumeProvider) GetByName(_ string, names ...string) ([]*datavolumev1beta1.DataVolume, error) {
+	var result []*datavolumev1beta1.DataVolume
+	var multiError zerrors.MultiError
+	for _, name := range names {
+		dv, ok := f.cluster.dvs[name]
+		if !ok {
+			multiError.Add(name, errors.NewNotFound(schema.GroupResource{Resource: "datavolumes"}, name))
+		}
+		result = append(result, dv)
+	}
+	return result, multiError.AsOptional()
+}
+
+func (f *fakeDataVolumeProvider) AddOwnerReferences(dv *datavolumev1beta1.DataVolume, newOwnerRefs ...metav1.OwnerReference) (*datavolumev1beta1.DataVolume, error) {
+	dv.OwnerReferences = append(dv.OwnerReferences, newOwnerRefs...)
+	return dv, nil
+}
+
+func (f *fakeDataVolumeProvider) RemoveOwnerReferences(dv *datavolumev1beta1.DataVolume, ownerUID types.UID) (*datavolumev1beta1.DataVolume, error) {
+	dv.OwnerReferences = k8s.RemoveOwnerReferences(dv.OwnerReferences, ownerUID)
+	return dv, nil
+}
+
+type fakePVCProvider struct {
+	cluster *fakeCluster
+}
+
+func (f *fakePVCProvider) GetByName(_ string, names ...string) ([]*v1.PersistentVolumeClaim, error) {
+	var result []*v1.PersistentVolumeClaim
+	var multiError zerrors.MultiError
+	for _, name := range names {
+		pvc, ok := f.cluster.pvcs[name]
+		if !ok {
+			multiError.Add(name, errors.NewNotFound(schema.GroupResource{Resource: "persistentvolumeclaims"}, name))
+		}
+		result = append(result, pvc)
+	}
+	return result, multiError.AsOptional()
+}
+
+func (f *fakePVCProvider) AddOwnerReferences(pvc *v1.PersistentVolumeClaim, newOwnerRefs ...metav1.OwnerReference) (*v1.PersistentVolumeClaim, error) {
+	pvc.OwnerReferences = append(pvc.OwnerReferences, newOwnerRefs...)
+	return pvc, nil
+}
+
+func (f *fakePVCProvider) RemoveOwnerReferences(pvc *v1.PersistentVolumeClaim, ownerUID types.UID) (*v1.PersistentVolumeClaim, error) {
+	pvc.OwnerReferences = k8s.RemoveOwnerReferences(pvc.OwnerReferences, ownerUID)
+	return pvc, nil
+}
+
+// fakeVirtualMachineProvider implements only the calls needed to delete a VM
+type fakeVirtualMachineProvider struct {
+	virtualMachine.VirtualMachineProvider
+	cluster             *fakeCluster
+	vm                  *kubevirtv1.VirtualMachine
+	propagationPolicies []metav1.DeletionPropagation
+}
+
+func (f *fakeVirtualMachineProvider) Get(_, name string) (*kubevirtv1.VirtualMachine, error) {
+	if f.vm == nil {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "virtualmachines"}, name)
+	}
+	return f.vm, nil
+}
+
+func (f *fakeVirtualMachineProvider) Delete(_, _ string, propagationPolicy metav1.DeletionPropagation) error {
+	f.propagationPolicies = append(f.propagationPolicies, propagationPolicy)
+	f.cluster.collectGarbage(f.vm.UID)
+	f.vm = nil
+	return nil
+}
+
+var _ = Describe("VMCreator", func() {
+	var cluster *fakeCluster
+	var vmProvider *fakeVirtualMachineProvider
+	var vmCreator *VMCreator
+
+	BeforeEach(func() {
+		vm := &kubevirtv1.VirtualMachine{ObjectMeta: metav1.ObjectMeta{Name: "my-vm", Namespace: "default", UID: "vm-uid"}}
+		ownerRefs := []metav1.OwnerReference{virtualMachine.AsVMOwnerReference(vm)}
+
+		cluster = &fakeCluster{
+			dvs: map[string]*datavolumev1beta1.DataVolume{
+				"own-dv":      {ObjectMeta: metav1.ObjectMeta{Name: "own-dv", OwnerReferences: ownerRefs}},
+				"template-dv": {ObjectMeta: metav1.ObjectMeta{Name: "template-dv", OwnerReferences: ownerRefs}},
+			},
+			pvcs: map[string]*v1.PersistentVolumeClaim{
+				"own-pvc": {ObjectMeta: metav1.ObjectMeta{Name: "own-pvc", OwnerReferences: ownerRefs}},
+			},
+		}
+		vmProvider = &fakeVirtualMachineProvider{cluster: cluster, vm: vm}
+		vmCreator = &VMCreator{
+			targetNamespace: "default",
+			cliOptions: &parse.CLIOptions{
+				OwnDataVolumes:            []string{"rootdisk:own-dv"},
+				OwnPersistentVolumeClaims: []string{"own-pvc"},
+			},
+			virtualMachineProvider: vmProvider,
+			dataVolumeProvider:     &fakeDataVolumeProvider{cluster: cluster},
+			pvcProvider:            &fakePVCProvider{cluster: cluster},
+			rollback:               rollback.NewRollback(),
+		}
+	})
+
+	It("keeps the disowned volumes of a replaced VM", func() {
+		Expect(vmCreator.deleteExistingVM("my-vm")).To(Succeed())
+
+		Expect(vmProvider.propagationPolicies).To(ConsistOf(metav1.DeletePropagationBackground))
+		Expect(cluster.dvs).To(HaveKey("own-dv"))
+		Expect(cluster.dvs["own-dv"].OwnerReferences).To(BeEmpty())
+		Expect(cluster.pvcs).To(HaveKey("own-pvc"))
+		Expect(cluster.pvcs["own-pvc"].OwnerReferences).To(BeEmpty())
+		Expect(cluster.dvs).ToNot(HaveKey("template-dv"))
+	})
+
+	It("deletes the volumes of a created VM on rollback", func() {
+		vmCreator.addCreatedVMRollback(vmProvider.vm)
+		Expect(vmCreator.Rollback()).To(Succeed())
+
+		Expect(vmProvider.propagationPolicies).To(ConsistOf(metav1.DeletePropagationForeground))
+		Expect(cluster.dvs).To(BeEmpty())
+		Expect(cluster.pvcs).To(BeEmpty())
+	})
+})
@@ -0,0 +1,16 @@
+package vmcreator
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utilstest"
+)
+
+func TestVmcreator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vmcreator Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
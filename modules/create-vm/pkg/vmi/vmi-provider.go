@@ -0,0 +1,25 @@
+package vmi
+
+import (
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+	kubevirtcliv1 "kubevirt.io/client-go/kubecli"
+)
+
+type virtualMachineInstanceProvider struct {
+	client kubevirtcliv1.KubevirtClient
+}
+
+type VirtualMachineInstanceProvider interface {
+	NewListWatch(namespace, name string) cache.ListerWatcher
+}
+
+func NewVirtualMachineInstanceProvider(client kubevirtcliv1.KubevirtClient) VirtualMachineInstanceProvider {
+	return &virtualMachineInstanceProvider{
+		client: client,
+	}
+}
+
+func (v *virtualMachineInstanceProvider) NewListWatch(namespace, name string) cache.ListerWatcher {
+	return cache.NewListWatchFromClient(v.client.RestClient(), "virtualmachineinstances", namespace, fields.OneTermEqualSelector("metadata.name", name))
+}
@@ -0,0 +1,58 @@
+package vmi
+
+import (
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+)
+
+const podNetworkName = "default"
+
+func IsRunning(vmi *kubevirtv1.VirtualMachineInstance) bool {
+	return vmi.Status.Phase == kubevirtv1.Running
+}
+
+func IsAgentConnected(vmi *kubevirtv1.VirtualMachineInstance) bool {
+	for _, condition := range vmi.Status.Conditions {
+		if condition.Type == kubevirtv1.VirtualMachineInstanceAgentConnected {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// GetIPAddress returns the IP of the pod network interface or of the first interface with an IP
+func GetIPAddress(vmi *kubevirtv1.VirtualMachineInstance) string {
+	var firstIP string
+
+	for _, iface := range vmi.Status.Interfaces {
+		if iface.IP == "" {
+			continue
+		}
+		if iface.Name == podNetworkName {
+			return iface.IP
+		}
+		if firstIP == "" {
+			firstIP = iface.IP
+		}
+	}
+	return firstIP
+}
+
+func GetNodeName(vmi *kubevirtv1.VirtualMachineInstance) string {
+	return vmi.Status.NodeName
+}
+
+// GetGuestOSInfo returns the guest OS info reported by the guest agent as JSON or an empty string if it was not reported
+func GetGuestOSInfo(vmi *kubevirtv1.VirtualMachineInstance) (string, error) {
+	if vmi.Status.GuestOSInfo == (kubevirtv1.VirtualMachineInstanceGuestOSInfo{}) {
+		return "", nil
+	}
+
+	guestOSInfo, err := json.Marshal(vmi.Status.GuestOSInfo)
+	if err != nil {
+		return "", err
+	}
+	return string(guestOSInfo), nil
+}
@@ -0,0 +1,16 @@
+package vmi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utilstest"
+)
+
+func TestVmi(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vmi Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
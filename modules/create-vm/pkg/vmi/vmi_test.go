@@ -0,0 +1,74 @@
+package vmi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vmi"
+	shtestobjects "github.com/kubevirt/kubevirt-tekton-tasks/modules/sharedtest/testobjects"
+)
+
+var _ = Describe("VMI", func() {
+	var virtualMachineInstance *kubevirtv1.VirtualMachineInstance
+
+	BeforeEach(func() {
+		virtualMachineInstance = shtestobjects.NewTestVMI()
+	})
+
+	It("is running", func() {
+		Expect(vmi.IsRunning(virtualMachineInstance)).To(BeFalse())
+		virtualMachineInstance.Status.Phase = kubevirtv1.Running
+		Expect(vmi.IsRunning(virtualMachineInstance)).To(BeTrue())
+	})
+
+	table.DescribeTable("has agent connected", func(conditions []kubevirtv1.VirtualMachineInstanceCondition, expected bool) {
+		virtualMachineInstance.Status.Conditions = conditions
+		Expect(vmi.IsAgentConnected(virtualMachineInstance)).To(Equal(expected))
+	},
+		table.Entry("no conditions", nil, false),
+		table.Entry("other condition", []kubevirtv1.VirtualMachineInstanceCondition{
+			{Type: kubevirtv1.VirtualMachineInstanceReady, Status: v1.ConditionTrue},
+		}, false),
+		table.Entry("disconnected", []kubevirtv1.VirtualMachineInstanceCondition{
+			{Type: kubevirtv1.VirtualMachineInstanceAgentConnected, Status: v1.ConditionFalse},
+		}, false),
+		table.Entry("connected", []kubevirtv1.VirtualMachineInstanceCondition{
+			{Type: kubevirtv1.VirtualMachineInstanceReady, Status: v1.ConditionTrue},
+			{Type: kubevirtv1.VirtualMachineInstanceAgentConnected, Status: v1.ConditionTrue},
+		}, true),
+	)
+
+	table.DescribeTable("gets IP address", func(interfaces []kubevirtv1.VirtualMachineInstanceNetworkInterface, expected string) {
+		virtualMachineInstance.Status.Interfaces = interfaces
+		Expect(vmi.GetIPAddress(virtualMachineInstance)).To(Equal(expected))
+	},
+		table.Entry("no interfaces", nil, ""),
+		table.Entry("interface without IP", []kubevirtv1.VirtualMachineInstanceNetworkInterface{{Name: "default"}}, ""),
+		table.Entry("first interface", []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+			{Name: "nic0"}, {Name: "nic1", IP: "10.0.0.2"}, {Name: "nic2", IP: "10.0.0.3"},
+		}, "10.0.0.2"),
+		table.Entry("pod network interface", []kubevirtv1.VirtualMachineInstanceNetworkInterface{
+			{Name: "nic1", IP: "10.0.0.2"}, {Name: "default", IP: "10.128.0.5"},
+		}, "10.128.0.5"),
+	)
+
+	It("gets node name", func() {
+		virtualMachineInstance.Status.NodeName = "node01"
+		Expect(vmi.GetNodeName(virtualMachineInstance)).To(Equal("node01"))
+	})
+
+	It("gets empty guest OS info", func() {
+		Expect(vmi.GetGuestOSInfo(virtualMachineInstance)).To(BeEmpty())
+	})
+
+	It("gets guest OS info", func() {
+		virtualMachineInstance.Status.GuestOSInfo = kubevirtv1.VirtualMachineInstanceGuestOSInfo{
+			Name:    "Fedora",
+			Version: "33",
+		}
+		Expect(vmi.GetGuestOSInfo(virtualMachineInstance)).To(MatchJSON(`{"name": "Fedora", "version": "33"}`))
+	})
+})
@@ -0,0 +1,133 @@
+package vmiwaiter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vmi"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/cache"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+)
+
+type VirtualMachineInstanceWaiter struct {
+	cliOptions                     *parse.CLIOptions
+	virtualMachineInstanceProvider vmi.VirtualMachineInstanceProvider
+}
+
+// waitState holds the last observed VMI, it is shared between the informer and the waiting goroutine
+type waitState struct {
+	lock     sync.Mutex
+	lastSeen *kubevirtv1.VirtualMachineInstance
+}
+
+func NewVirtualMachineInstanceWaiter(cliOptions *parse.CLIOptions, virtualMachineInstanceProvider vmi.VirtualMachineInstanceProvider) *VirtualMachineInstanceWaiter {
+	return &VirtualMachineInstanceWaiter{
+		cliOptions:                     cliOptions,
+		virtualMachineInstanceProvider: virtualMachineInstanceProvider,
+	}
+}
+
+// WaitForReady waits until the VMI is running and optionally until its guest agent is connected
+func (w *VirtualMachineInstanceWaiter) WaitForReady(namespace, name string) (*kubevirtv1.VirtualMachineInstance, error) {
+	state := &waitState{}
+
+	stop := make(chan struct{})
+	result := make(chan error, 1)
+	var once sync.Once
+
+	finish := func(err error) {
+		once.Do(func() {
+			result <- err
+			close(stop)
+		})
+	}
+
+	eventHandler := func(obj interface{}) {
+		if current, ok := obj.(*kubevirtv1.VirtualMachineInstance); ok {
+			if done, err := w.evaluate(state, current); done {
+				finish(err)
+			}
+		}
+	}
+
+	_, controller := cache.NewInformer(w.virtualMachineInstanceProvider.NewListWatch(namespace, name), &kubevirtv1.VirtualMachineInstance{}, time.Second*0, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			log.Logger().Debug("vmi added", zap.Reflect("vmi", obj))
+			eventHandler(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			log.Logger().Debug("vmi deleted", zap.Reflect("vmi", obj))
+			finish(exit.Exit{
+				Code: constants.VMIFailedExitCode,
+				Msg:  fmt.Sprintf("%v VMI was deleted before it was ready", name),
+				Soft: true,
+			})
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			log.Logger().Debug("vmi changed", zap.Reflect("vmi", newObj))
+			eventHandler(newObj)
+		},
+	})
+
+	go controller.Run(stop)
+
+	timer := time.NewTimer(w.cliOptions.GetTimeout())
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-timer.C:
+		finish(exit.Exit{
+			Code: constants.WaitForReadyTimeoutExitCode,
+			Msg:  fmt.Sprintf("timed out waiting for %v VMI to be ready", name),
+			Soft: true,
+		})
+		err = <-result
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	return state.lastSeen, err
+}
+
+// evaluate returns true when the waiting should end, together with the resulting error
+func (w *VirtualMachineInstanceWaiter) evaluate(state *waitState, current *kubevirtv1.VirtualMachineInstance) (bool, error) {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	previous := state.lastSeen
+	state.lastSeen = current
+
+	if previous == nil || previous.Status.Phase != current.Status.Phase {
+		log.Logger().Info("VMI phase changed", zap.String("name", current.Name), zap.String("phase", string(current.Status.Phase)))
+	}
+
+	if current.IsFinal() {
+		return true, exit.Exit{
+			Code: constants.VMIFailedExitCode,
+			Msg:  fmt.Sprintf("%v VMI stopped with %v phase", current.Name, current.Status.Phase),
+			Soft: true,
+		}
+	}
+
+	if !vmi.IsRunning(current) {
+		return false, nil
+	}
+
+	if w.cliOptions.GetWaitForGuestAgent() {
+		if !vmi.IsAgentConnected(current) {
+			return false, nil
+		}
+		log.Logger().Info("VMI guest agent connected", zap.String("name", current.Name))
+	}
+
+	return true, nil
+}
@@ -0,0 +1,164 @@
+package vmiwaiter_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/vmiwaiter"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+)
+
+const (
+	testNamespace = "test-ns"
+	testName      = "test-vm"
+)
+
+type fakeVMIProvider struct {
+	initial []kubevirtv1.VirtualMachineInstance
+	watcher *watch.FakeWatcher
+}
+
+func (f *fakeVMIProvider) NewListWatch(_, _ string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return &kubevirtv1.VirtualMachineInstanceList{Items: f.initial}, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return f.watcher, nil
+		},
+	}
+}
+
+func newVMI(phase kubevirtv1.VirtualMachineInstancePhase, agentConnected bool) *kubevirtv1.VirtualMachineInstance {
+	vmi := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            testName,
+			Namespace:       testNamespace,
+			ResourceVersion: "1",
+		},
+		Status: kubevirtv1.VirtualMachineInstanceStatus{
+			Phase:    phase,
+			NodeName: "node01",
+		},
+	}
+
+	if agentConnected {
+		vmi.Status.Conditions = []kubevirtv1.VirtualMachineInstanceCondition{
+			{Type: kubevirtv1.VirtualMachineInstanceAgentConnected, Status: v1.ConditionTrue},
+		}
+	}
+	return vmi
+}
+
+func expectExitCode(err error, code int, msgSubstring string) {
+	Expect(err).Should(HaveOccurred())
+	Expect(err).To(BeAssignableToTypeOf(exit.Exit{}))
+	Expect(err.(exit.Exit).Code).To(Equal(code))
+	Expect(err.Error()).To(ContainSubstring(msgSubstring))
+}
+
+var _ = Describe("VirtualMachineInstanceWaiter", func() {
+	var cliOptions *parse.CLIOptions
+	var provider *fakeVMIProvider
+
+	BeforeEach(func() {
+		cliOptions = &parse.CLIOptions{WaitForReady: "true", Timeout: "10s"}
+		provider = &fakeVMIProvider{watcher: watch.NewFakeWithChanSize(10, false)}
+	})
+
+	type waitResult struct {
+		vmi *kubevirtv1.VirtualMachineInstance
+		err error
+	}
+
+	waitAsync := func() chan waitResult {
+		waiter := vmiwaiter.NewVirtualMachineInstanceWaiter(cliOptions, provider)
+
+		result := make(chan waitResult, 1)
+		go func() {
+			defer GinkgoRecover()
+			vmi, err := waiter.WaitForReady(testNamespace, testName)
+			result <- waitResult{vmi, err}
+		}()
+		return result
+	}
+
+	It("succeeds for running VMI", func() {
+		provider.initial = []kubevirtv1.VirtualMachineInstance{*newVMI(kubevirtv1.Running, false)}
+
+		var result waitResult
+		Eventually(waitAsync(), 5*time.Second).Should(Receive(&result))
+		Expect(result.err).Should(Succeed())
+		Expect(result.vmi.Status.NodeName).To(Equal("node01"))
+	})
+
+	It("waits for VMI to be created and running", func() {
+		result := waitAsync()
+		Consistently(result).ShouldNot(Receive())
+
+		provider.watcher.Add(newVMI(kubevirtv1.Scheduling, false))
+		Consistently(result).ShouldNot(Receive())
+
+		provider.watcher.Modify(newVMI(kubevirtv1.Running, false))
+		Eventually(result, 5*time.Second).Should(Receive())
+	})
+
+	It("waits for guest agent", func() {
+		cliOptions = &parse.CLIOptions{WaitForGuestAgent: "true", Timeout: "10s"}
+		provider.initial = []kubevirtv1.VirtualMachineInstance{*newVMI(kubevirtv1.Running, false)}
+
+		result := waitAsync()
+		Consistently(result).ShouldNot(Receive())
+
+		provider.watcher.Modify(newVMI(kubevirtv1.Running, true))
+
+		var waited waitResult
+		Eventually(result, 5*time.Second).Should(Receive(&waited))
+		Expect(waited.err).Should(Succeed())
+	})
+
+	It("fails for failed VMI", func() {
+		provider.initial = []kubevirtv1.VirtualMachineInstance{*newVMI(kubevirtv1.Scheduling, false)}
+
+		result := waitAsync()
+		Consistently(result).ShouldNot(Receive())
+
+		provider.watcher.Modify(newVMI(kubevirtv1.Failed, false))
+
+		var waited waitResult
+		Eventually(result, 5*time.Second).Should(Receive(&waited))
+		expectExitCode(waited.err, constants.VMIFailedExitCode, "stopped with Failed phase")
+	})
+
+	It("fails for deleted VMI", func() {
+		provider.initial = []kubevirtv1.VirtualMachineInstance{*newVMI(kubevirtv1.Scheduling, false)}
+
+		result := waitAsync()
+		Consistently(result).ShouldNot(Receive())
+
+		provider.watcher.Delete(newVMI(kubevirtv1.Scheduling, false))
+
+		var waited waitResult
+		Eventually(result, 5*time.Second).Should(Receive(&waited))
+		expectExitCode(waited.err, constants.VMIFailedExitCode, "was deleted")
+	})
+
+	It("times out", func() {
+		cliOptions.Timeout = "1s"
+		provider.initial = []kubevirtv1.VirtualMachineInstance{*newVMI(kubevirtv1.Scheduling, false)}
+
+		var waited waitResult
+		Eventually(waitAsync(), 5*time.Second).Should(Receive(&waited))
+		expectExitCode(waited.err, constants.WaitForReadyTimeoutExitCode, "timed out")
+	})
+})
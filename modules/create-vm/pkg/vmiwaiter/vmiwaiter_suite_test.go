@@ -0,0 +1,16 @@
+package vmiwaiter_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/create-vm/pkg/utilstest"
+)
+
+func TestVmiwaiter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vmiwaiter Suite")
+}
+
+var _ = BeforeSuite(utilstest.SetupTestSuite)
@@ -2,9 +2,9 @@ package execute
 
 import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/disk-virt-customize/pkg/constants"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/disk-virt-customize/pkg/utils/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/disk-virt-customize/pkg/utils/parse"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/options"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"io/ioutil"
@@ -36,7 +36,7 @@ func (e *Executor) PrepareGuestFSAppliance() error {
 		"/mnt",
 	}
 
-	log.GetLogger().Debug("extracting guestfs appliance with tar " + strings.Join(opts, " "))
+	log.Logger().Debug("extracting guestfs appliance with tar " + strings.Join(opts, " "))
 	cmd := exec.Command("tar", opts...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -67,7 +67,7 @@ func (e *Executor) Execute() error {
 	opts.AddOptions(additionalVirtCustomizeOpts.GetAll()...)
 	SetupVirtCustomizeOptions(opts, e.cliOptions)
 
-	log.GetLogger().Debug("executing virt-customize command with options: " + strings.Join(opts.GetAll(), " "))
+	log.Logger().Debug("executing virt-customize command with options: " + strings.Join(opts.GetAll(), " "))
 	cmd := exec.Command("virt-customize", opts.GetAll()...)
 	cmd.Env = os.Environ()
 	cmd.Stdout = os.Stdout
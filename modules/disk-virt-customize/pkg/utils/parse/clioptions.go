@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
 	"go.uber.org/zap/zapcore"
 )
@@ -14,6 +15,7 @@ type CLIOptions struct {
 	CustomizeCommands              string `arg:"--customize-commands,env:CUSTOMIZE_COMMANDS" placeholder:"COMMANDS" help:"virt-customize script in --commands-from-file format to execute on target pvc."`
 	AdditionalVirtCustomizeOptions string `arg:"--additional-virt-customize-options,env:ADDITIONAL_VIRT_CUSTOMIZE_OPTIONS" placeholder:"OPTIONS" help:"additional options to pass to virt-customize."`
 	Verbose                        string `arg:"--verbose" placeholder:"true|false" help:"Enable verbose mode and tracing of libguestfs API calls."`
+	LogFormat                      string `arg:"--log-format,env:LOG_FORMAT" placeholder:"json|console" help:"Log format. One of: json|console. Defaults to json."`
 }
 
 func (c *CLIOptions) GetDebugLevel() zapcore.Level {
@@ -23,6 +25,10 @@ func (c *CLIOptions) GetDebugLevel() zapcore.Level {
 	return zapcore.InfoLevel
 }
 
+func (c *CLIOptions) GetLogFormat() log.LogFormat {
+	return log.LogFormat(c.LogFormat)
+}
+
 func (c *CLIOptions) IsVerbose() bool {
 	return zutils.IsTrue(c.Verbose)
 }
@@ -40,5 +46,9 @@ func (c *CLIOptions) Init() error {
 		return err
 	}
 
+	if err := c.validateLogFormat(); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -4,9 +4,9 @@ import (
 	goarg "github.com/alexflint/go-arg"
 	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/disk-virt-sysprep/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/disk-virt-sysprep/pkg/execute"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/disk-virt-sysprep/pkg/utils/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/disk-virt-sysprep/pkg/utils/parse"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"go.uber.org/zap"
 )
 
@@ -16,20 +16,20 @@ func main() {
 	cliOptions := &parse.CLIOptions{}
 	goarg.MustParse(cliOptions)
 
-	logger := log.InitLogger(cliOptions.GetDebugLevel())
+	logger := log.InitLoggerWithFormat(cliOptions.GetDebugLevel(), cliOptions.GetLogFormat())
 	defer logger.Sync()
 
-	log.GetLogger().Debug("parsed arguments", zap.Reflect("cliOptions", cliOptions))
+	log.Logger().Debug("parsed arguments", zap.Reflect("cliOptions", cliOptions))
 	if err := cliOptions.Init(); err != nil {
 		exit.ExitOrDieFromError(InvalidArguments, err)
 	}
 	executor := execute.NewExecutor(cliOptions, DiskImagePath)
 
-	if err := executor.PrepareGuestFSAppliance(); err != nil {
+	if err := log.Phase("PrepareGuestFSAppliance", executor.PrepareGuestFSAppliance); err != nil {
 		exit.ExitOrDieFromError(PrepareGuestFSApplianceFailed, err)
 	}
 
-	if err := executor.Execute(); err != nil {
+	if err := log.Phase("Execute", executor.Execute); err != nil {
 		exit.ExitOrDieFromError(ExecuteFailed, err)
 	}
 }
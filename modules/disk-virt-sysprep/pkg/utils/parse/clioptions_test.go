@@ -21,6 +21,10 @@ var _ = Describe("CLIOptions", func() {
 		Expect(options.Init().Error()).To(ContainSubstring(expectedErrMessage))
 	},
 		table.Entry("no sysprep commands", "sysprep-commands option or SYSPREP_COMMANDS env variable is required", &parse.CLIOptions{}),
+		table.Entry("invalid log format", "text is not a valid log format", &parse.CLIOptions{
+			SysprepCommands: "test",
+			LogFormat:       "text",
+		}),
 	)
 	table.DescribeTable("Parses and returns correct values", func(options *parse.CLIOptions, expectedOptions map[string]interface{}) {
 		Expect(options.Init()).Should(Succeed())
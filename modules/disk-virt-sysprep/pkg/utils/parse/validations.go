@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 )
 
@@ -10,3 +11,10 @@ func (c *CLIOptions) validateCommands() error {
 	}
 	return nil
 }
+
+func (c *CLIOptions) validateLogFormat() error {
+	if !log.IsLogFormat(c.LogFormat) {
+		return zerrors.NewMissingRequiredError("%v is not a valid log format", c.LogFormat)
+	}
+	return nil
+}
@@ -5,12 +5,14 @@ import (
 	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/execute"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils"
-	log "github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/parse"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/metrics"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	"time"
 )
 
@@ -20,13 +22,23 @@ func main() {
 	cliOptions := &parse.CLIOptions{}
 	goarg.MustParse(cliOptions)
 
-	logger := log.InitLogger(cliOptions.GetDebugLevel())
+	logger := log.InitLoggerWithFormat(cliOptions.GetDebugLevel(), cliOptions.GetLogFormat())
 	defer logger.Sync()
 
 	log.Logger().Debug("parsed arguments", zap.Reflect("cliOptions", cliOptions))
 	if err := cliOptions.Init(); err != nil {
 		exit.ExitOrDieFromError(InvalidArguments, err)
 	}
+	log.AddTarget(kubevirtv1.VirtualMachineGroupVersionKind.Kind, cliOptions.GetVirtualMachineNamespace(), cliOptions.VirtualMachineName)
+	defer exportMetrics(cliOptions)
+
+	metricLabels := metrics.Labels{"vm": cliOptions.VirtualMachineName}
+	timedPhase := func(name, metricName, metricHelp string, phase func() error) error {
+		start := time.Now()
+		err := log.Phase(name, phase)
+		metrics.RecordDuration(metricName, metricHelp, start, metricLabels)
+		return err
+	}
 
 	executor, executorErr := execute.NewExecutor(cliOptions, ConnectionSecretPath)
 	if executorErr != nil {
@@ -58,22 +70,34 @@ func main() {
 
 		runWithTimeout(func(timeout time.Duration, finished bool) {
 			if multiError.IsEmpty() && !finished {
-				err := executor.EnsureVMRunning(timeout)
+				err := timedPhase("EnsureVMRunning", "vm_running_duration_seconds", "Duration until the VM was running",
+					func() error { return executor.EnsureVMRunning(timeout) })
 				registerError("EnsureVMRunning", err)
 			}
 		})
 
 		runWithTimeout(func(timeout time.Duration, finished bool) {
 			if multiError.IsEmpty() && !finished {
-				err := executor.SetupConnection(timeout)
+				err := timedPhase("SetupConnection", "connection_duration_seconds", "Duration until the connection to the VM was established",
+					func() error { return executor.SetupConnection(timeout) })
 				registerError("SetupConnection", err)
 			}
 		})
 
+		if len(cliOptions.GetUploads()) > 0 {
+			runWithTimeout(func(timeout time.Duration, finished bool) {
+				if multiError.IsEmpty() && !finished {
+					err := timedPhase("UploadFiles", "upload_duration_seconds", "Duration of uploading the files to the VM", executor.UploadFiles)
+					registerError("UploadFiles", err)
+				}
+			})
+		}
+
 		runWithTimeout(func(timeout time.Duration, finished bool) {
 			if multiError.IsEmpty() {
 				if !finished {
-					err := executor.RemoteExecute(timeout)
+					err := timedPhase("RemoteExecute", "remote_execute_duration_seconds", "Duration of the command or script in the VM",
+						func() error { return executor.RemoteExecute(timeout) })
 					registerError("RemoteExecute", err)
 				} else {
 					registerError("RemoteExecute", wait.ErrWaitTimeout)
@@ -82,20 +106,42 @@ func main() {
 			}
 		})
 
+		if len(cliOptions.GetDownloads()) > 0 && multiError.IsEmpty() {
+			if err := timedPhase("DownloadFiles", "download_duration_seconds", "Duration of downloading the files from the VM", executor.DownloadFiles); err != nil {
+				multiError.Add("DownloadFiles", err)
+			}
+		}
+
+		if err := log.Phase("SaveHostPublicKey", executor.SaveHostPublicKey); err != nil {
+			multiError.Add("SaveHostPublicKey", err)
+		}
+
+		if err := log.Phase("RecordResults", executor.RecordResults); err != nil {
+			multiError.Add("RecordResults", err)
+		}
+
 	}
 
 	if cliOptions.ShouldStop() {
-		if err := executor.EnsureVMStopped(); err != nil {
+		if err := timedPhase("EnsureVMStopped", "vm_stop_duration_seconds", "Duration until the VM was stopped", executor.EnsureVMStopped); err != nil {
 			multiError.Add("VM Stop", err)
 		}
 	}
 
 	if cliOptions.ShouldDelete() {
-		if err := executor.EnsureVMDeleted(); err != nil {
+		if err := timedPhase("EnsureVMDeleted", "vm_delete_duration_seconds", "Duration until the VM was deleted", executor.EnsureVMDeleted); err != nil {
 			multiError.Add("VM Delete", err)
 		}
 	}
 
+	var outcomeErr error
+	if !multiError.IsEmpty() {
+		outcomeErr = multiError
+	} else if exitError != nil {
+		outcomeErr = *exitError
+	}
+	metrics.RecordOutcome("execute_success", "Whether all actions in the VM succeeded", outcomeErr, metricLabels)
+
 	if !multiError.IsEmpty() {
 		if exitError != nil {
 			multiError.Add("command exit", *exitError)
@@ -109,3 +155,9 @@ func main() {
 		exit.ExitOrDieFromError(exitError.Code, exitError)
 	}
 }
+
+func exportMetrics(cliOptions *parse.CLIOptions) {
+	if err := metrics.Export(cliOptions.GetMetricsFile(), cliOptions.GetMetricsPushgatewayURL()); err != nil {
+		log.Logger().Warn("could not export metrics", zap.Error(err))
+	}
+}
@@ -19,3 +19,11 @@ const PollVMItoStopInterval = 1 * time.Second
 const SetupConnectionDelay = 2 * time.Second
 
 const EmptyConnectionSecretName = "__empty__"
+
+const (
+	HostPublicKeyResultName = "hostPublicKey"
+	StdoutResultName        = "stdout"
+	StderrResultName        = "stderr"
+	ExitCodeResultName      = "exitCode"
+	OutputResultName        = "output"
+)
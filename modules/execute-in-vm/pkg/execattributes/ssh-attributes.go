@@ -3,31 +3,39 @@ package execattributes
 import (
 	"fmt"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env/fileoptions"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/options"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zconstants/connectionsecret"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"os/user"
 	"path"
 	"strings"
+	"time"
 )
 
 const (
 	sshStrictHostKeyCheckingOption = "StrictHostKeyChecking"
+	sshConnectTimeoutOption        = "ConnectTimeout"
+	sshServerAliveIntervalOption   = "ServerAliveInterval"
+	sshServerAliveCountMaxOption   = "ServerAliveCountMax"
 )
 
 const (
-	sshDirName        = ".ssh"
-	sshExecutableName = "ssh"
+	defaultConnectTimeout      = 30 * time.Second
+	defaultServerAliveInterval = 15 * time.Second
+	defaultServerAliveCountMax = 3
 )
 
+// StrictHostKeyChecking values understood by the SSH executor
 const (
-	acceptNew = "accept-new"
-	no        = "no"
-	yes       = "yes"
+	StrictHostKeyCheckingYes       = "yes"
+	StrictHostKeyCheckingNo        = "no"
+	StrictHostKeyCheckingOff       = "off"
+	StrictHostKeyCheckingAcceptNew = "accept-new"
 )
 
-// port is parsed from additionalSSHOptions to be more easier to use
+// port and -o options are parsed from additionalSSHOptions to be more easier to use
 type sshAttributes struct {
 	user                         string
 	port                         int
@@ -35,6 +43,11 @@ type sshAttributes struct {
 	privateKey                   string
 	hostPublicKey                string
 	disableStrictHostKeyChecking bool
+	trustHostKeyOnFirstUse       bool
+	strictHostKeyCheckingMode    string
+	connectTimeout               time.Duration
+	serverAliveInterval          time.Duration
+	serverAliveCountMax          int
 }
 
 type SSHAttributes interface {
@@ -46,8 +59,9 @@ type SSHAttributes interface {
 	GetPrivateKey() string
 	GetHostPublicKey() string
 	GetStrictHostKeyCheckingMode() string
-	GetSSHDir() string
-	GetSSHExecutableName() string
+	GetConnectTimeout() time.Duration
+	GetServerAliveInterval() time.Duration
+	GetServerAliveCountMax() int
 }
 
 func NewSSHAttributes() SSHAttributes {
@@ -66,6 +80,7 @@ func (s *sshAttributes) initSSH(execSecretPath string) error {
 	}
 	boolOptions := map[string]*bool{
 		connectionsecret.SSHConnectionSecretKeys.DisableStrictHostKeyChecking: &s.disableStrictHostKeyChecking,
+		connectionsecret.SSHConnectionSecretKeys.TrustHostKeyOnFirstUse:       &s.trustHostKeyOnFirstUse,
 	}
 
 	for optionName, output := range stringOptions {
@@ -92,8 +107,9 @@ func (s *sshAttributes) initSSH(execSecretPath string) error {
 		return zerrors.NewMissingRequiredError("%v secret attribute is required", connectionsecret.SSHConnectionSecretKeys.User)
 	}
 
-	if strings.TrimSpace(s.hostPublicKey) == "" && !s.disableStrictHostKeyChecking {
-		return zerrors.NewMissingRequiredError("%v or %v=true secret attribute is required", connectionsecret.SSHConnectionSecretKeys.HostPublicKey, connectionsecret.SSHConnectionSecretKeys.DisableStrictHostKeyChecking)
+	if strings.TrimSpace(s.hostPublicKey) == "" && !s.disableStrictHostKeyChecking && !s.trustHostKeyOnFirstUse {
+		return zerrors.NewMissingRequiredError("%v, %v=true or %v=true secret attribute is required", connectionsecret.SSHConnectionSecretKeys.HostPublicKey,
+			connectionsecret.SSHConnectionSecretKeys.DisableStrictHostKeyChecking, connectionsecret.SSHConnectionSecretKeys.TrustHostKeyOnFirstUse)
 	}
 	additionalSSHOptions, err := options.NewCommandOptions(additionalSSHOptionsString)
 	if err != nil {
@@ -106,6 +122,25 @@ func (s *sshAttributes) initSSH(execSecretPath string) error {
 	}
 	s.port = port
 
+	configOptions, unsupportedOptions := parseConfigOptions(additionalSSHOptions)
+	if len(unsupportedOptions) > 0 {
+		log.Logger().Warn("ignoring ssh options which are not supported", zap.Strings("options", unsupportedOptions))
+	}
+
+	if s.connectTimeout, err = parseSecondsOption(configOptions, sshConnectTimeoutOption, defaultConnectTimeout); err != nil {
+		return err
+	}
+
+	if s.serverAliveInterval, err = parseSecondsOption(configOptions, sshServerAliveIntervalOption, defaultServerAliveInterval); err != nil {
+		return err
+	}
+
+	if s.serverAliveCountMax, err = parseIntOption(configOptions, sshServerAliveCountMaxOption, defaultServerAliveCountMax); err != nil {
+		return err
+	}
+
+	s.strictHostKeyCheckingMode = configOptions[strings.ToLower(sshStrictHostKeyCheckingOption)]
+
 	if !strings.HasSuffix(s.privateKey, "\n") {
 		s.privateKey += "\n"
 	}
@@ -132,11 +167,16 @@ func (s *sshAttributes) GetAdditionalSSHOptions() []string {
 }
 
 func (s *sshAttributes) GetStrictHostKeyCheckingMode() string {
+	if s.strictHostKeyCheckingMode != "" {
+		return s.strictHostKeyCheckingMode
+	}
 	if s.disableStrictHostKeyChecking {
-		// TODO change to safer acceptNew once a newer version of ssh which supports this option is available in CI
-		return no
+		return StrictHostKeyCheckingNo
+	}
+	if s.trustHostKeyOnFirstUse {
+		return StrictHostKeyCheckingAcceptNew
 	}
-	return yes
+	return StrictHostKeyCheckingYes
 }
 
 func (s *sshAttributes) GetPrivateKey() string {
@@ -147,18 +187,16 @@ func (s *sshAttributes) GetHostPublicKey() string {
 	return s.hostPublicKey
 }
 
-func (s *sshAttributes) GetSSHDir() string {
-	current, err := user.Current()
-
-	if err != nil {
-		panic(err)
-	}
+func (s *sshAttributes) GetConnectTimeout() time.Duration {
+	return s.connectTimeout
+}
 
-	return path.Join(current.HomeDir, sshDirName)
+func (s *sshAttributes) GetServerAliveInterval() time.Duration {
+	return s.serverAliveInterval
 }
 
-func (s *sshAttributes) GetSSHExecutableName() string {
-	return sshExecutableName
+func (s *sshAttributes) GetServerAliveCountMax() int {
+	return s.serverAliveCountMax
 }
 
 func (s *sshAttributes) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
@@ -166,5 +204,6 @@ func (s *sshAttributes) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
 	encoder.AddString("user", s.user)
 	encoder.AddString("additionalSSHOptions", strings.Join(s.additionalSSHOptions, " "))
 	encoder.AddBool("disableStrictHostKeyChecking", s.disableStrictHostKeyChecking)
+	encoder.AddBool("trustHostKeyOnFirstUse", s.trustHostKeyOnFirstUse)
 	return nil
 }
@@ -2,16 +2,16 @@ package execattributes_test
 
 import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/execattributes"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/sharedtest/testconstants"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
 	"os"
-	"os/user"
 	"path"
 	"reflect"
+	"time"
 )
 
 var _ = Describe("SSHAttributes", func() {
@@ -43,7 +43,7 @@ var _ = Describe("SSHAttributes", func() {
 		table.Entry("user missing", "user secret attribute is required", map[string]string{
 			"ssh-privatekey": SSHTestPrivateKey,
 		}),
-		table.Entry("public key missing", "host-public-key or disable-strict-host-key-checking=true secret attribute is required", map[string]string{
+		table.Entry("public key missing", "host-public-key, disable-strict-host-key-checking=true or trust-host-key-on-first-use=true secret attribute is required", map[string]string{
 			"user":           "root",
 			"ssh-privatekey": SSHTestPrivateKey,
 		}),
@@ -59,6 +59,18 @@ var _ = Describe("SSHAttributes", func() {
 			"host-public-key":        SSHTestPublicKey,
 			"additional-ssh-options": "-C -p 22.0",
 		}),
+		table.Entry("bad connect timeout", "Bad ConnectTimeout 'abc'", map[string]string{
+			"user":                   "fedora",
+			"ssh-privatekey":         SSHTestPrivateKey,
+			"host-public-key":        SSHTestPublicKey,
+			"additional-ssh-options": "-o ConnectTimeout=abc",
+		}),
+		table.Entry("negative server alive count", "Bad ServerAliveCountMax '-1'", map[string]string{
+			"user":                   "fedora",
+			"ssh-privatekey":         SSHTestPrivateKey,
+			"host-public-key":        SSHTestPublicKey,
+			"additional-ssh-options": "-oServerAliveCountMax=-1",
+		}),
 	)
 
 	table.DescribeTable("test various sshAttributes", func(secretSetup map[string]string, expectedAttributes map[string]interface{}) {
@@ -90,6 +102,9 @@ var _ = Describe("SSHAttributes", func() {
 			"GetPrivateKey":                SSHTestPrivateKey,
 			"GetHostPublicKey":             SSHTestPublicKey,
 			"GetStrictHostKeyCheckingMode": "yes",
+			"GetConnectTimeout":            30 * time.Second,
+			"GetServerAliveInterval":       15 * time.Second,
+			"GetServerAliveCountMax":       3,
 		}),
 		table.Entry("minimal setup with alternative private key format", map[string]string{
 			"user":            "fedora",
@@ -110,15 +125,33 @@ var _ = Describe("SSHAttributes", func() {
 			"disable-strict-host-key-checking": "true",
 			"additional-ssh-options":           "-C -p 8022",
 		}, map[string]interface{}{
-			"GetUser": "fedora",
-			"GetPort": 8022,
-			// TODO change to safer acceptNew once a newer version of ssh which supports this option is available in CI
-			"GetAdditionalSSHOptions": []string{"-C", "-p", "8022", "-o", "StrictHostKeyChecking=no"},
-			"GetPrivateKey":           SSHTestPrivateKey,
-			"GetHostPublicKey":        "",
-			// TODO same here
+			"GetUser":                      "fedora",
+			"GetPort":                      8022,
+			"GetAdditionalSSHOptions":      []string{"-C", "-p", "8022", "-o", "StrictHostKeyChecking=no"},
+			"GetPrivateKey":                SSHTestPrivateKey,
+			"GetHostPublicKey":             "",
 			"GetStrictHostKeyCheckingMode": "no",
 		}),
+		table.Entry("trust host key on first use", map[string]string{
+			"type":                        "ssh",
+			"user":                        "fedora",
+			"ssh-privatekey":              SSHTestPrivateKey,
+			"trust-host-key-on-first-use": "true",
+		}, map[string]interface{}{
+			"GetAdditionalSSHOptions":      []string{"-o", "StrictHostKeyChecking=accept-new"},
+			"GetHostPublicKey":             "",
+			"GetStrictHostKeyCheckingMode": "accept-new",
+		}),
+		table.Entry("trust host key on first use with a known host key", map[string]string{
+			"type":                        "ssh",
+			"user":                        "fedora",
+			"ssh-privatekey":              SSHTestPrivateKey,
+			"host-public-key":             SSHTestPublicKey,
+			"trust-host-key-on-first-use": "true",
+		}, map[string]interface{}{
+			"GetHostPublicKey":             SSHTestPublicKey,
+			"GetStrictHostKeyCheckingMode": "accept-new",
+		}),
 		table.Entry("invalid disable-strict-host-key-checking value", map[string]string{
 			"type":                             "ssh",
 			"user":                             "fedora",
@@ -151,31 +184,26 @@ var _ = Describe("SSHAttributes", func() {
 			"GetPort":                 8022,
 			"GetAdditionalSSHOptions": []string{"-C", "-p8022", "-l=test", "-o", "StrictHostKeyChecking=yes"},
 		}),
+		table.Entry("connection options", map[string]string{
+			"type":                   "ssh",
+			"user":                   "fedora",
+			"ssh-privatekey":         SSHTestPrivateKey,
+			"host-public-key":        SSHTestPublicKey,
+			"additional-ssh-options": "-o ConnectTimeout=5 -oserveraliveinterval=0 -o ServerAliveCountMax=1 -o ConnectTimeout=10",
+		}, map[string]interface{}{
+			"GetConnectTimeout":      5 * time.Second,
+			"GetServerAliveInterval": time.Duration(0),
+			"GetServerAliveCountMax": 1,
+		}),
+		table.Entry("explicit strict host key checking option", map[string]string{
+			"type":                   "ssh",
+			"user":                   "fedora",
+			"ssh-privatekey":         SSHTestPrivateKey,
+			"host-public-key":        SSHTestPublicKey,
+			"additional-ssh-options": "-o StrictHostKeyChecking=no",
+		}, map[string]interface{}{
+			"GetAdditionalSSHOptions":      []string{"-o", "StrictHostKeyChecking=no"},
+			"GetStrictHostKeyCheckingMode": "no",
+		}),
 	)
-
-	It("does common operations correctly", func() {
-		PrepareTestSecret(testSecretPath, map[string]string{
-			"type":            "ssh",
-			"user":            "fedora",
-			"ssh-privatekey":  SSHTestPrivateKey,
-			"host-public-key": SSHTestPublicKey,
-		})
-		attributes := execattributes.NewExecAttributes()
-
-		err := attributes.Init(testSecretPath)
-		Expect(err).Should(Succeed())
-
-		sshAttributes := attributes.GetSSHAttributes()
-
-		// GetSSHDir
-		current, err := user.Current()
-		Expect(err).Should(Succeed())
-		homeDir := current.HomeDir
-		Expect(sshAttributes.GetSSHDir()).To(Equal(path.Join(homeDir, ".ssh")))
-
-		// GetSSHExecutableName
-		Expect(sshAttributes.GetSSHExecutableName()).To(Equal("ssh"))
-
-		log.Logger().Info(CurrentGinkgoTestDescription().FullTestText, zap.Object("execAttributes", attributes)) // test MarshalLogObject
-	})
 })
@@ -4,12 +4,21 @@ import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/options"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
 	defaultSSHPort = 22
 )
 
+var supportedConfigOptions = []string{
+	sshStrictHostKeyCheckingOption,
+	sshConnectTimeoutOption,
+	sshServerAliveIntervalOption,
+	sshServerAliveCountMaxOption,
+}
+
 func parsePort(sshOptions *options.CommandOptions) (int, error) {
 	if !sshOptions.IncludesOption("-p") {
 		return defaultSSHPort, nil
@@ -27,3 +36,86 @@ func parsePort(sshOptions *options.CommandOptions) (int, error) {
 	}
 	return port, nil
 }
+
+// parseConfigOptions returns supported -o options with lower case names (as ssh does not distinguish the case) and the remaining options
+func parseConfigOptions(sshOptions *options.CommandOptions) (map[string]string, []string) {
+	configOptions := map[string]string{}
+	var unsupportedOptions []string
+
+	all := sshOptions.GetAll()
+	for idx := 0; idx < len(all); idx++ {
+		var option string
+
+		switch {
+		case all[idx] == "-p" && idx+1 < len(all):
+			idx++
+			continue
+		case strings.HasPrefix(all[idx], "-p"):
+			continue
+		case all[idx] == "-o" && idx+1 < len(all):
+			idx++
+			option = all[idx]
+		case strings.HasPrefix(all[idx], "-o"):
+			option = strings.TrimPrefix(all[idx], "-o")
+		default:
+			unsupportedOptions = append(unsupportedOptions, all[idx])
+			continue
+		}
+
+		name, value := splitConfigOption(option)
+		if !isSupportedConfigOption(name) {
+			unsupportedOptions = append(unsupportedOptions, "-o", option)
+			continue
+		}
+
+		// the first obtained value is used
+		if _, exists := configOptions[strings.ToLower(name)]; !exists {
+			configOptions[strings.ToLower(name)] = value
+		}
+	}
+
+	return configOptions, unsupportedOptions
+}
+
+func parseSecondsOption(configOptions map[string]string, name string, defaultValue time.Duration) (time.Duration, error) {
+	value, exists := configOptions[strings.ToLower(name)]
+	if !exists {
+		return defaultValue, nil
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, zerrors.NewMissingRequiredError("Bad %v '%v'", name, value)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func parseIntOption(configOptions map[string]string, name string, defaultValue int) (int, error) {
+	value, exists := configOptions[strings.ToLower(name)]
+	if !exists {
+		return defaultValue, nil
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil || result < 0 {
+		return 0, zerrors.NewMissingRequiredError("Bad %v '%v'", name, value)
+	}
+	return result, nil
+}
+
+func splitConfigOption(option string) (string, string) {
+	separatorIdx := strings.IndexAny(option, "= ")
+	if separatorIdx < 0 {
+		return option, ""
+	}
+	return option[:separatorIdx], strings.TrimSpace(option[separatorIdx+1:])
+}
+
+func isSupportedConfigOption(name string) bool {
+	for _, supportedOption := range supportedConfigOptions {
+		if strings.EqualFold(name, supportedOption) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,4 +1,4 @@
-package cmd_test
+package execute_test
 
 import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utilstest"
@@ -8,9 +8,9 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-func TestLog(t *testing.T) {
+func TestExecute(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "cmd Suite")
+	RunSpecs(t, "Execute Suite")
 }
 
 var _ = BeforeSuite(utilstest.SetupTestSuite)
@@ -1,20 +1,34 @@
 package execute
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/execattributes"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/parse"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/vmi"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/events"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/results"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zconstants/connectionsecret"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"go.uber.org/zap"
+	"io"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/kubecli"
+	"os"
+	"sort"
+	"strconv"
 	"time"
 )
 
@@ -22,11 +36,18 @@ type Executor struct {
 	clioptions     *parse.CLIOptions
 	kubevirtClient kubecli.KubevirtClient
 	executor       RemoteExecutor
+	eventRecorder  events.Recorder
+	stdout         io.Writer
+	stderr         io.Writer
 
 	attemptedStart  bool
 	attemptedStop   bool
 	attemptedDelete bool
 	ipAddress       string
+	connected       bool
+	output          *remoteOutput
+	exitCode        *int
+	results         map[string]string
 }
 
 func NewExecutor(clioptions *parse.CLIOptions, connectionSecretPath string) (*Executor, error) {
@@ -59,7 +80,15 @@ func NewExecutor(clioptions *parse.CLIOptions, connectionSecretPath string) (*Ex
 		return nil, fmt.Errorf("%v: %v", "cannot create kubevirt client", err.Error())
 	}
 
-	return &Executor{clioptions: clioptions, kubevirtClient: kubevirtClient, executor: executor}, nil
+	return &Executor{
+		clioptions:     clioptions,
+		kubevirtClient: kubevirtClient,
+		executor:       executor,
+		eventRecorder:  events.NewRecorder(kubevirtClient.CoreV1()),
+		stdout:         os.Stdout,
+		stderr:         os.Stderr,
+		results:        map[string]string{},
+	}, nil
 }
 
 func (e *Executor) EnsureVMRunning(timeout time.Duration) error {
@@ -173,10 +202,10 @@ func (e *Executor) EnsureVMDeleted() error {
 	vmNamespace := e.clioptions.GetVirtualMachineNamespace()
 
 	return wait.PollImmediateInfinite(constants.PollVMtoDeleteInterval, func() (bool, error) {
-		_, err := e.kubevirtClient.VirtualMachine(vmNamespace).Get(vmName, &v1.GetOptions{})
+		vm, err := e.kubevirtClient.VirtualMachine(vmNamespace).Get(vmName, &v1.GetOptions{})
 
 		if err == nil {
-			if err := e.ensureVMDelete(); err != nil {
+			if err := e.ensureVMDelete(vm); err != nil {
 				return false, err
 			}
 			log.Logger().Debug(" waiting for a VM to be deleted", zap.String("name", vmName), zap.String("namespace", vmNamespace))
@@ -216,6 +245,7 @@ func (e *Executor) SetupConnection(timeout time.Duration) error {
 		err = wait.PollImmediate(constants.PollValidConnectionInterval, timeout, conditionFn)
 	}
 	time.Sleep(constants.SetupConnectionDelay)
+	e.connected = err == nil
 	return err
 }
 
@@ -223,7 +253,129 @@ func (e *Executor) RemoteExecute(timeout time.Duration) error {
 	if e.executor == nil {
 		return fmt.Errorf("executor is missing or was not initialized")
 	}
-	return e.executor.RemoteExecute(timeout)
+
+	remoteOut, err := newRemoteOutput(e.clioptions, e.stdout, e.stderr)
+	if err != nil {
+		return err
+	}
+	e.output = remoteOut
+	defer func() {
+		if err := remoteOut.close(); err != nil {
+			log.Logger().Warn("could not write the output files", zap.Error(err))
+		}
+	}()
+	e.executor.SetOutput(remoteOut.stdoutWriter, remoteOut.stderrWriter)
+
+	err = e.executor.RemoteExecute(timeout)
+	// negative codes are not returned by the command
+	if exitErr, ok := err.(exit.Exit); ok && exitErr.Code >= 0 {
+		e.exitCode = &exitErr.Code
+	}
+	return err
+}
+
+func (e *Executor) UploadFiles() error {
+	if e.executor == nil {
+		return fmt.Errorf("executor is missing or was not initialized")
+	}
+	return e.executor.UploadFiles()
+}
+
+// DownloadFiles downloads the files also when the command failed, so the logs and reports of the failure can be inspected.
+// Nothing is downloaded when the connection to the VM was not established.
+func (e *Executor) DownloadFiles() error {
+	if e.executor == nil {
+		return fmt.Errorf("executor is missing or was not initialized")
+	}
+	if !e.connected {
+		log.Logger().Debug("skipping downloads: the connection to the VM was not established")
+		return nil
+	}
+	return e.executor.DownloadFiles()
+}
+
+// SaveHostPublicKey adds the host key observed during the execution to the results.
+// The key trusted on first use is also saved to the connection secret when requested.
+func (e *Executor) SaveHostPublicKey() error {
+	if e.executor == nil {
+		return fmt.Errorf("executor is missing or was not initialized")
+	}
+
+	hostPublicKey := e.executor.GetObservedHostPublicKey()
+	if hostPublicKey == "" {
+		log.Logger().Debug("no host public key was observed")
+		return nil
+	}
+
+	e.results[constants.HostPublicKeyResultName] = hostPublicKey
+
+	if !e.clioptions.ShouldSaveHostPublicKey() || !e.executor.IsHostKeyTrustedOnFirstUse() {
+		return nil
+	}
+
+	namespace, err := env.GetActiveNamespace()
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": map[string][]byte{
+			connectionsecret.SSHConnectionSecretKeys.HostPublicKey: []byte(hostPublicKey),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Logger().Debug("saving host public key trusted on first use", zap.String("secret", e.clioptions.ConnectionSecretName), zap.String("namespace", namespace))
+	secret, err := e.kubevirtClient.CoreV1().Secrets(namespace).Patch(context.TODO(), e.clioptions.ConnectionSecretName, types.MergePatchType, patch, v1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("could not save %v to %v secret: %v", connectionsecret.SSHConnectionSecretKeys.HostPublicKey, e.clioptions.ConnectionSecretName, err)
+	}
+
+	e.eventRecorder.Eventf(corev1.SchemeGroupVersion.WithKind("Secret"), secret, events.ModifiedByTektonTask,
+		"Host public key of %v VM was trusted on first use and saved by a Tekton task", e.clioptions.VirtualMachineName)
+	return nil
+}
+
+// RecordResults records the host public key and the captured output of the command/script.
+// The results which do not fit into the Tekton size limit are truncated.
+func (e *Executor) RecordResults() error {
+	executionResults, err := e.getResults()
+	if err != nil {
+		return err
+	}
+
+	log.Logger().Debug("recording results", zap.Strings("results", sortedKeys(executionResults)))
+	return results.RecordResults(executionResults)
+}
+
+func (e *Executor) getResults() (map[string]string, error) {
+	if e.output == nil {
+		return e.results, nil
+	}
+
+	if e.clioptions.ShouldCaptureOutput() {
+		e.results[constants.StdoutResultName] = e.output.stdout.String()
+		e.results[constants.StderrResultName] = e.output.stderr.String()
+		if e.exitCode != nil {
+			e.results[constants.ExitCodeResultName] = strconv.Itoa(*e.exitCode)
+		}
+	}
+
+	if resultJSONPath := e.clioptions.GetResultJSONPath(); resultJSONPath != "" {
+		projectedOutput, err := projectJSONOutput(e.output.stdout.String(), resultJSONPath)
+		if err == nil {
+			e.results[constants.OutputResultName] = projectedOutput
+		} else if e.exitCode == nil || *e.exitCode != 0 {
+			// the output of a failed command is not expected to be valid
+			log.Logger().Warn("could not record the output result of the failed command", zap.Error(err))
+		} else {
+			return nil, err
+		}
+	}
+
+	return e.results, nil
 }
 
 func (e *Executor) ensureVMStarted() error {
@@ -235,6 +387,7 @@ func (e *Executor) ensureVMStarted() error {
 		if err := e.kubevirtClient.VirtualMachine(vmNamespace).Start(vmName); err != nil {
 			return err
 		}
+		e.recordVMEvent(events.StartedByTektonTask, "VM was started by a Tekton task to execute in it")
 	}
 	return nil
 }
@@ -249,12 +402,13 @@ func (e *Executor) ensureVMStop() error {
 		if err := e.kubevirtClient.VirtualMachine(vmNamespace).Stop(vmName); err != nil {
 			return err
 		}
+		e.recordVMEvent(events.StoppedAfterExecution, "VM was stopped by a Tekton task after the execution")
 	}
 
 	return nil
 }
 
-func (e *Executor) ensureVMDelete() error {
+func (e *Executor) ensureVMDelete(vm *kubevirtv1.VirtualMachine) error {
 	vmName := e.clioptions.VirtualMachineName
 	vmNamespace := e.clioptions.GetVirtualMachineNamespace()
 	if !e.attemptedDelete {
@@ -264,7 +418,38 @@ func (e *Executor) ensureVMDelete() error {
 		if err := e.kubevirtClient.VirtualMachine(vmNamespace).Delete(vmName, &v1.DeleteOptions{}); err != nil {
 			return err
 		}
+		e.eventRecorder.Eventf(kubevirtv1.VirtualMachineGroupVersionKind, vm, events.DeletedAfterExecution, "VM was deleted by a Tekton task after the execution")
 	}
 
 	return nil
 }
+
+// recordVMEvent fetches the VM, so the event is shown only for this VM and not for a VM of the same name created later
+func (e *Executor) recordVMEvent(reason, message string) {
+	vmName := e.clioptions.VirtualMachineName
+	vmNamespace := e.clioptions.GetVirtualMachineNamespace()
+
+	vm, err := e.kubevirtClient.VirtualMachine(vmNamespace).Get(vmName, &v1.GetOptions{})
+	if err != nil {
+		log.Logger().Debug("could not get a vm to record an event", zap.String("name", vmName), zap.String("namespace", vmNamespace), zap.Error(err))
+		return
+	}
+	e.eventRecorder.Eventf(kubevirtv1.VirtualMachineGroupVersionKind, vm, reason, message)
+}
+
+func projectJSONOutput(stdout string, expression string) (string, error) {
+	var jsonOutput interface{}
+	if err := json.Unmarshal([]byte(stdout), &jsonOutput); err != nil {
+		return "", fmt.Errorf("could not parse the stdout of the command as JSON: %v", err)
+	}
+	return output.JSONPath(jsonOutput, expression)
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package execute
+
+import (
+	"bytes"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/results"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// cappedBuffer keeps at most limit bytes of the written data. A negative limit keeps all data.
+type cappedBuffer struct {
+	buffer bytes.Buffer
+	limit  int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.limit < 0 {
+		return b.buffer.Write(p)
+	}
+
+	if free := b.limit - b.buffer.Len(); free > 0 {
+		if len(p) > free {
+			b.buffer.Write(p[:free])
+		} else {
+			b.buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buffer.String()
+}
+
+// remoteOutput tees the output of the remote command to the container output, to the output files and to the buffers for the results
+type remoteOutput struct {
+	stdout *cappedBuffer
+	stderr *cappedBuffer
+	files  []*os.File
+
+	stdoutWriter io.Writer
+	stderrWriter io.Writer
+}
+
+func newRemoteOutput(clioptions *parse.CLIOptions, stdout, stderr io.Writer) (*remoteOutput, error) {
+	// one byte over the limit, so the overflow is detected when recording the results
+	stdoutLimit := results.MaxResultsSize + 1
+	if clioptions.GetResultJSONPath() != "" {
+		stdoutLimit = -1
+	}
+
+	o := &remoteOutput{
+		stdout: &cappedBuffer{limit: stdoutLimit},
+		stderr: &cappedBuffer{limit: results.MaxResultsSize + 1},
+	}
+
+	stdoutWriters := []io.Writer{stdout, o.stdout}
+	stderrWriters := []io.Writer{stderr, o.stderr}
+
+	if path := clioptions.GetStdoutFile(); path != "" {
+		file, err := o.createFile(path)
+		if err != nil {
+			o.close()
+			return nil, err
+		}
+		stdoutWriters = append(stdoutWriters, file)
+	}
+
+	if path := clioptions.GetStderrFile(); path != "" {
+		file, err := o.createFile(path)
+		if err != nil {
+			o.close()
+			return nil, err
+		}
+		stderrWriters = append(stderrWriters, file)
+	}
+
+	o.stdoutWriter = io.MultiWriter(stdoutWriters...)
+	o.stderrWriter = io.MultiWriter(stderrWriters...)
+	return o, nil
+}
+
+// createFile creates the file, eg. in a Tekton workspace. Creates the missing parent directories.
+func (o *remoteOutput) createFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	o.files = append(o.files, file)
+	return file, nil
+}
+
+func (o *remoteOutput) close() error {
+	var firstErr error
+	for _, file := range o.files {
+		if err := file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
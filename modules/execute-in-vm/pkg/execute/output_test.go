@@ -0,0 +1,178 @@
+package execute
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/results"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Output", func() {
+	It("caps the buffer", func() {
+		buffer := &cappedBuffer{limit: 5}
+		n, err := buffer.Write([]byte("1234"))
+		Expect(n).To(Equal(4))
+		Expect(err).Should(Succeed())
+		n, err = buffer.Write([]byte("5678"))
+		Expect(n).To(Equal(4))
+		Expect(err).Should(Succeed())
+		Expect(buffer.String()).To(Equal("12345"))
+	})
+
+	Describe("Executor", func() {
+		var tempDir string
+		var remoteExecutor *fakeRemoteExecutor
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "test-output-")
+			Expect(err).Should(Succeed())
+			remoteExecutor = &fakeRemoteExecutor{stdout: `{"status": "passed", "count": 3}`, stderr: "warning", exit: exit.Exit{Code: 0, Soft: true}}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		execute := func(clioptions *parse.CLIOptions) (map[string]string, error) {
+			executor := &Executor{clioptions: clioptions, executor: remoteExecutor, stdout: ioutil.Discard, stderr: ioutil.Discard, results: map[string]string{}}
+			Expect(executor.RemoteExecute(0)).To(Equal(remoteExecutor.exit))
+			return executor.getResults()
+		}
+
+		It("does not capture the output by default", func() {
+			executionResults, err := execute(&parse.CLIOptions{})
+			Expect(err).Should(Succeed())
+			Expect(executionResults).To(BeEmpty())
+		})
+
+		It("captures the output and exit code", func() {
+			remoteExecutor.exit = exit.Exit{Code: 3, Soft: true}
+
+			executionResults, err := execute(&parse.CLIOptions{CaptureOutput: "true"})
+			Expect(err).Should(Succeed())
+			Expect(executionResults).To(Equal(map[string]string{
+				constants.StdoutResultName:   remoteExecutor.stdout,
+				constants.StderrResultName:   "warning",
+				constants.ExitCodeResultName: "3",
+			}))
+		})
+
+		It("does not record the exit code of a timed out command", func() {
+			remoteExecutor.exit = exit.Exit{Code: constants.CommandTimeout, Msg: "command timed out", Soft: true}
+
+			executionResults, err := execute(&parse.CLIOptions{CaptureOutput: "true"})
+			Expect(err).Should(Succeed())
+			Expect(executionResults).ToNot(HaveKey(constants.ExitCodeResultName))
+		})
+
+		It("keeps only the beginning of a long output for the results", func() {
+			remoteExecutor.stdout = strings.Repeat("a", 2*results.MaxResultsSize)
+			stdoutFile := filepath.Join(tempDir, "logs", "stdout.txt")
+
+			executionResults, err := execute(&parse.CLIOptions{CaptureOutput: "true", StdoutFile: stdoutFile})
+			Expect(err).Should(Succeed())
+			Expect(executionResults[constants.StdoutResultName]).To(HaveLen(results.MaxResultsSize + 1))
+
+			content, err := ioutil.ReadFile(stdoutFile)
+			Expect(err).Should(Succeed())
+			Expect(string(content)).To(Equal(remoteExecutor.stdout))
+		})
+
+		It("writes the output files", func() {
+			stdoutFile := filepath.Join(tempDir, "stdout.txt")
+			stderrFile := filepath.Join(tempDir, "stderr.txt")
+
+			executionResults, err := execute(&parse.CLIOptions{StdoutFile: stdoutFile, StderrFile: stderrFile})
+			Expect(err).Should(Succeed())
+			Expect(executionResults).To(BeEmpty())
+
+			content, err := ioutil.ReadFile(stdoutFile)
+			Expect(err).Should(Succeed())
+			Expect(string(content)).To(Equal(remoteExecutor.stdout))
+			content, err = ioutil.ReadFile(stderrFile)
+			Expect(err).Should(Succeed())
+			Expect(string(content)).To(Equal("warning"))
+		})
+
+		It("projects the JSON output", func() {
+			executionResults, err := execute(&parse.CLIOptions{ResultJSONPath: "{.status}"})
+			Expect(err).Should(Succeed())
+			Expect(executionResults).To(Equal(map[string]string{constants.OutputResultName: "passed"}))
+		})
+
+		It("fails on an invalid JSON output", func() {
+			remoteExecutor.stdout = "passed"
+
+			_, err := execute(&parse.CLIOptions{ResultJSONPath: "{.status}"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("could not parse the stdout of the command as JSON: "))
+		})
+
+		It("ignores an invalid JSON output of a failed command", func() {
+			remoteExecutor.stdout = "failed"
+			remoteExecutor.exit = exit.Exit{Code: 1, Soft: true}
+
+			executionResults, err := execute(&parse.CLIOptions{ResultJSONPath: "{.status}"})
+			Expect(err).Should(Succeed())
+			Expect(executionResults).To(BeEmpty())
+		})
+	})
+})
+
+type fakeRemoteExecutor struct {
+	stdout       string
+	stderr       string
+	exit         exit.Exit
+	stdoutWriter io.Writer
+	stderrWriter io.Writer
+}
+
+func (f *fakeRemoteExecutor) Init(string) error {
+	return nil
+}
+
+func (f *fakeRemoteExecutor) TestConnection() bool {
+	return true
+}
+
+func (f *fakeRemoteExecutor) SetOutput(stdout, stderr io.Writer) {
+	f.stdoutWriter = stdout
+	f.stderrWriter = stderr
+}
+
+func (f *fakeRemoteExecutor) RemoteExecute(time.Duration) error {
+	if _, err := io.Copy(f.stdoutWriter, bytes.NewBufferString(f.stdout)); err != nil {
+		return err
+	}
+	if _, err := io.Copy(f.stderrWriter, bytes.NewBufferString(f.stderr)); err != nil {
+		return err
+	}
+	return f.exit
+}
+
+func (f *fakeRemoteExecutor) UploadFiles() error {
+	return nil
+}
+
+func (f *fakeRemoteExecutor) DownloadFiles() error {
+	return nil
+}
+
+func (f *fakeRemoteExecutor) GetObservedHostPublicKey() string {
+	return ""
+}
+
+func (f *fakeRemoteExecutor) IsHostKeyTrustedOnFirstUse() bool {
+	return false
+}
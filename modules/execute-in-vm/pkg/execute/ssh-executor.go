@@ -1,69 +1,104 @@
 package execute
 
 import (
+	"bytes"
 	"fmt"
-	cmd2 "github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/cmd"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/execattributes"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/parse"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/options"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zconstants/connectionsecret"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"io"
 	"net"
 	"os"
-	"os/exec"
-	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	knownHostsFilename = "known_hosts"
-	idRSAFilename      = "id_rsa"
-)
-
-const (
-	defaultFileMode = 0600
-	defaultDirMode  = 0700
+	keepAliveRequestName = "keepalive@openssh.com"
 )
 
 type sshExecutor struct {
 	clioptions *parse.CLIOptions
 	ssh        execattributes.SSHAttributes
 	ipAddress  string
+	config     *ssh.ClientConfig
+	stdout     io.Writer
+	stderr     io.Writer
+
+	hostKeyLock       sync.Mutex
+	hostKey           ssh.PublicKey
+	trustedOnFirstUse bool
 }
 
 func newSSHExecutor(clioptions *parse.CLIOptions, execAttributes execattributes.ExecAttributes) *sshExecutor {
-	return &sshExecutor{clioptions: clioptions, ssh: execAttributes.GetSSHAttributes()}
+	return &sshExecutor{clioptions: clioptions, ssh: execAttributes.GetSSHAttributes(), stdout: os.Stdout, stderr: os.Stderr}
 }
 
 func (e *sshExecutor) Init(ipAddress string) error {
 	e.ipAddress = ipAddress
 
-	log.Logger().Debug("preparing ssh files")
-	if err := os.MkdirAll(e.ssh.GetSSHDir(), defaultDirMode); err != nil {
-		return err
+	log.Logger().Debug("preparing ssh client config")
+	signer, err := ssh.ParsePrivateKey([]byte(e.ssh.GetPrivateKey()))
+	if err != nil {
+		return zerrors.NewMissingRequiredError("could not parse %v: %v", connectionsecret.SSHConnectionSecretKeys.PrivateKey, err)
 	}
 
-	if privateKey := e.ssh.GetPrivateKey(); privateKey != "" {
-		if err := writeToUserFile(path.Join(e.ssh.GetSSHDir(), idRSAFilename), privateKey, false); err != nil {
-			return err
-		}
+	mode := e.ssh.GetStrictHostKeyCheckingMode()
+	hostKeyCallback, err := newHostKeyCallback(mode, e.ssh.GetHostPublicKey())
+	if err != nil {
+		return err
 	}
+	e.trustedOnFirstUse = mode == execattributes.StrictHostKeyCheckingAcceptNew && strings.TrimSpace(e.ssh.GetHostPublicKey()) == ""
 
-	if hostPublicKey := e.ssh.GetHostPublicKey(); hostPublicKey != "" {
-		knownHost := fmt.Sprintf("%v %v", ipAddress, hostPublicKey)
-		if err := writeToUserFile(path.Join(e.ssh.GetSSHDir(), knownHostsFilename), knownHost, true); err != nil {
-			return err
-		}
+	e.config = &ssh.ClientConfig{
+		User: e.ssh.GetUser(),
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if err := hostKeyCallback(hostname, remote, key); err != nil {
+				return err
+			}
+			e.hostKeyLock.Lock()
+			defer e.hostKeyLock.Unlock()
+			e.hostKey = key
+			return nil
+		},
+		Timeout: e.ssh.GetConnectTimeout(),
 	}
 
 	return nil
+}
+
+// GetObservedHostPublicKey returns the verified key of the host in the authorized_keys format or an empty string if there was no connection
+func (e *sshExecutor) GetObservedHostPublicKey() string {
+	e.hostKeyLock.Lock()
+	defer e.hostKeyLock.Unlock()
+
+	if e.hostKey == nil {
+		return ""
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(e.hostKey)))
+}
 
+// IsHostKeyTrustedOnFirstUse returns true if the observed host key was not known before the connection
+func (e *sshExecutor) IsHostKeyTrustedOnFirstUse() bool {
+	return e.trustedOnFirstUse
+}
+
+func (e *sshExecutor) SetOutput(stdout, stderr io.Writer) {
+	e.stdout = stdout
+	e.stderr = stderr
 }
 
 func (e *sshExecutor) TestConnection() bool {
-	address := net.JoinHostPort(e.ipAddress, strconv.Itoa(e.ssh.GetPort()))
+	address := e.getAddress()
 	conn, err := net.DialTimeout("tcp", address, constants.CheckSSHConnectionTimeout)
 	if conn != nil {
 		defer conn.Close()
@@ -75,41 +110,219 @@ func (e *sshExecutor) TestConnection() bool {
 }
 
 func (e *sshExecutor) RemoteExecute(timeout time.Duration) error {
-	opts := options.NewCommandOptionsFromArray(e.ssh.GetAdditionalSSHOptions())
+	client, keepAlive, err := e.connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	defer keepAlive.stop()
 
-	destination := e.ssh.GetUser() + "@" + e.ipAddress
-	opts.AddValue(destination)
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
 
-	log.Logger().Debug("executing ssh command with options: " + strings.Join(opts.GetAll(), " "))
+	session.Stdout = e.stdout
+	session.Stderr = e.stderr
 
 	// do not log script
-	opts.AddValue("--")
-	opts.AddValue(e.clioptions.GetScript())
+	log.Logger().Debug("executing command over ssh")
+	if err := session.Start(e.clioptions.GetScript()); err != nil {
+		return err
+	}
 
-	cmd := exec.Command(e.ssh.GetSSHExecutableName(), opts.GetAll()...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
 
-	return cmd2.RunCmdWithTimeout(timeout, cmd)
-}
+	var timeoutChan <-chan time.Time
+	if timeout > 0 {
+		timeoutChan = time.After(timeout)
+	}
 
-func writeToUserFile(filename string, content string, append bool) error {
-	flags := os.O_CREATE | os.O_WRONLY
+	select {
+	case <-timeoutChan:
+		if err := session.Signal(ssh.SIGKILL); err != nil {
+			log.Logger().Debug("could not kill the remote command", zap.Error(err))
+		}
+		return exit.Exit{
+			Code: constants.CommandTimeout,
+			Msg:  "command timed out",
+			Soft: true,
+		}
+	case err := <-done:
+		if keepAliveErr := keepAlive.getError(); keepAliveErr != nil {
+			return keepAliveErr
+		}
+		return toExit(err)
+	}
+}
 
-	if append {
-		flags |= os.O_APPEND
-	} else {
-		flags |= os.O_TRUNC
+// connect dials the VM. The returned client and keepAlive should be closed and stopped by the caller.
+func (e *sshExecutor) connect() (*ssh.Client, *keepAlive, error) {
+	if e.config == nil {
+		return nil, nil, fmt.Errorf("ssh executor was not initialized")
 	}
 
-	f, err := os.OpenFile(filename, flags, defaultFileMode)
+	log.Logger().Debug("connecting over ssh", zap.String("user", e.config.User), zap.String("address", e.getAddress()))
+	client, err := ssh.Dial("tcp", e.getAddress(), e.config)
 	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, newKeepAlive(client, e.ssh.GetServerAliveInterval(), e.ssh.GetServerAliveCountMax()), nil
+}
+
+func (e *sshExecutor) getAddress() string {
+	return net.JoinHostPort(e.ipAddress, strconv.Itoa(e.ssh.GetPort()))
+}
+
+// toExit converts the result of the remote command to the exit of this task. The remote exit status is used as the exit code.
+func toExit(err error) error {
+	switch t := err.(type) {
+	case nil:
+		return exit.Exit{
+			Code: 0,
+			Soft: true,
+		}
+	case *ssh.ExitError:
+		var msg string
+		if t.Signal() != "" {
+			msg = fmt.Sprintf("remote command was terminated by signal %v", t.Signal())
+			if t.Msg() != "" {
+				msg += ": " + t.Msg()
+			}
+		}
+		return exit.Exit{
+			Code: t.ExitStatus(),
+			Msg:  msg,
+			Soft: true,
+		}
+	case *ssh.ExitMissingError:
+		return fmt.Errorf("remote command exited without exit status or exit signal")
+	default:
 		return err
 	}
-	defer f.Close()
+}
+
+func newHostKeyCallback(strictHostKeyCheckingMode string, hostPublicKey string) (ssh.HostKeyCallback, error) {
+	if strictHostKeyCheckingMode == execattributes.StrictHostKeyCheckingNo || strictHostKeyCheckingMode == execattributes.StrictHostKeyCheckingOff {
+		log.Logger().Debug("host key verification is disabled")
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	if strictHostKeyCheckingMode == execattributes.StrictHostKeyCheckingAcceptNew && strings.TrimSpace(hostPublicKey) == "" {
+		log.Logger().Debug("host key will be trusted on first use")
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	knownKeys, err := parseHostPublicKeys(hostPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		for _, knownKey := range knownKeys {
+			if bytes.Equal(knownKey.Marshal(), key.Marshal()) {
+				return nil
+			}
+		}
+		return fmt.Errorf("host key verification failed: %v host key %v of %v does not match %v", key.Type(), ssh.FingerprintSHA256(key), hostname, connectionsecret.SSHConnectionSecretKeys.HostPublicKey)
+	}, nil
+}
+
+// parseHostPublicKeys accepts one or more keys in the authorized_keys format
+func parseHostPublicKeys(hostPublicKey string) ([]ssh.PublicKey, error) {
+	var keys []ssh.PublicKey
+
+	rest := []byte(hostPublicKey)
+	for len(bytes.TrimSpace(rest)) > 0 {
+		key, _, _, nextRest, err := ssh.ParseAuthorizedKey(rest)
+		if err != nil {
+			return nil, zerrors.NewMissingRequiredError("could not parse %v: %v", connectionsecret.SSHConnectionSecretKeys.HostPublicKey, err)
+		}
+		keys = append(keys, key)
+		rest = nextRest
+	}
+
+	if len(keys) == 0 {
+		return nil, zerrors.NewMissingRequiredError("%v secret attribute is required for host key verification", connectionsecret.SSHConnectionSecretKeys.HostPublicKey)
+	}
+	return keys, nil
+}
+
+// keepAlive closes the client when the server does not respond to countMax consecutive keepalive requests
+type keepAlive struct {
+	done chan struct{}
+	lock sync.Mutex
+	err  error
+}
+
+func newKeepAlive(client *ssh.Client, interval time.Duration, countMax int) *keepAlive {
+	k := &keepAlive{done: make(chan struct{})}
+
+	if interval <= 0 {
+		return k
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		missed := 0
+
+		for {
+			select {
+			case <-k.done:
+				return
+			case <-ticker.C:
+				if err := k.ping(client, interval); err != nil {
+					missed++
+					log.Logger().Debug("ssh keepalive failed", zap.Int("missed", missed), zap.Error(err))
+					if missed >= countMax {
+						k.setError(fmt.Errorf("ssh connection lost: server did not respond to %v keepalive requests", missed))
+						client.Close()
+						return
+					}
+				} else {
+					missed = 0
+				}
+			}
+		}
+	}()
 
-	if _, err := f.Write([]byte(content)); err != nil {
+	return k
+}
+
+// ping sends a keepalive request and waits for the reply at most for the timeout
+func (k *keepAlive) ping(client *ssh.Client, timeout time.Duration) error {
+	result := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest(keepAliveRequestName, true, nil)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
 		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("no reply in %v", timeout)
+	case <-k.done:
+		return nil
 	}
-	return f.Sync()
+}
+
+func (k *keepAlive) stop() {
+	close(k.done)
+}
+
+func (k *keepAlive) setError(err error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.err = err
+}
+
+func (k *keepAlive) getError() error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	return k.err
 }
@@ -0,0 +1,451 @@
+package execute
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/constants"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/execattributes"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/sharedtest/testconstants"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+var _ = Describe("SSHExecutor", func() {
+	var server *testSSHServer
+	var secretPath string
+	var stdout, stderr bytes.Buffer
+
+	BeforeEach(func() {
+		var err error
+		server, err = newTestSSHServer(SSHTestPrivateKey2, SSHTestPublicKey)
+		Expect(err).Should(Succeed())
+
+		secretPath, err = ioutil.TempDir("", "test-ssh-executor-")
+		Expect(err).Should(Succeed())
+
+		stdout.Reset()
+		stderr.Reset()
+	})
+
+	AfterEach(func() {
+		server.close()
+		Expect(os.RemoveAll(secretPath)).To(Succeed())
+	})
+
+	newExecutor := func(script string, secret map[string]string) *sshExecutor {
+		executor := newTestSSHExecutor(server, secretPath, &parse.CLIOptions{Script: script}, secret)
+		executor.stdout = &stdout
+		executor.stderr = &stderr
+		return executor
+	}
+
+	It("executes a command", func() {
+		executor := newExecutor("echo hello", map[string]string{"host-public-key": SSHTestPublicKey2})
+
+		Expect(executor.RemoteExecute(0)).To(Equal(exit.Exit{Code: 0, Soft: true}))
+		Expect(stdout.String()).To(Equal("hello\n"))
+		Expect(stderr.String()).To(Equal("hello to stderr\n"))
+		Expect(server.getUser()).To(Equal("fedora"))
+		Expect(executor.GetObservedHostPublicKey()).To(Equal(hostPublicKeyWithoutComment))
+		Expect(executor.IsHostKeyTrustedOnFirstUse()).To(BeFalse())
+	})
+
+	It("accepts one of multiple host public keys", func() {
+		executor := newExecutor("echo hello", map[string]string{"host-public-key": SSHTestPublicKey + "\n" + SSHTestPublicKey2 + "\n"})
+		Expect(executor.RemoteExecute(0)).To(Equal(exit.Exit{Code: 0, Soft: true}))
+	})
+
+	It("skips host key verification when disabled", func() {
+		executor := newExecutor("echo hello", map[string]string{"disable-strict-host-key-checking": "true"})
+		Expect(executor.RemoteExecute(0)).To(Equal(exit.Exit{Code: 0, Soft: true}))
+	})
+
+	It("rejects an unknown host key", func() {
+		executor := newExecutor("echo hello", map[string]string{"host-public-key": SSHTestPublicKey})
+
+		err := executor.RemoteExecute(0)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("host key verification failed: ssh-rsa host key SHA256:"))
+		Expect(err.Error()).To(ContainSubstring("does not match host-public-key"))
+		Expect(stdout.String()).To(BeEmpty())
+		Expect(executor.GetObservedHostPublicKey()).To(BeEmpty())
+	})
+
+	It("trusts the host key on first use", func() {
+		executor := newExecutor("echo hello", map[string]string{"trust-host-key-on-first-use": "true"})
+
+		Expect(executor.GetObservedHostPublicKey()).To(BeEmpty())
+		Expect(executor.RemoteExecute(0)).To(Equal(exit.Exit{Code: 0, Soft: true}))
+		Expect(executor.GetObservedHostPublicKey()).To(Equal(hostPublicKeyWithoutComment))
+		Expect(executor.IsHostKeyTrustedOnFirstUse()).To(BeTrue())
+	})
+
+	It("verifies the known host key when trusting on first use", func() {
+		executor := newExecutor("echo hello", map[string]string{
+			"host-public-key":             SSHTestPublicKey,
+			"trust-host-key-on-first-use": "true",
+		})
+
+		err := executor.RemoteExecute(0)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("host key verification failed"))
+		Expect(executor.IsHostKeyTrustedOnFirstUse()).To(BeFalse())
+	})
+
+	table.DescribeTable("reports the remote exit", func(script string, expectedExit exit.Exit) {
+		executor := newExecutor(script, map[string]string{"host-public-key": SSHTestPublicKey2})
+		Expect(executor.RemoteExecute(0)).To(Equal(expectedExit))
+	},
+		table.Entry("exit status", "exit 3", exit.Exit{Code: 3, Soft: true}),
+		table.Entry("exit signal", "kill", exit.Exit{Code: 128 + 15, Msg: "remote command was terminated by signal TERM: terminated", Soft: true}),
+	)
+
+	It("fails when the exit status is missing", func() {
+		executor := newExecutor("no-status", map[string]string{"host-public-key": SSHTestPublicKey2})
+
+		err := executor.RemoteExecute(0)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("remote command exited without exit status or exit signal"))
+	})
+
+	It("kills the command on timeout", func() {
+		executor := newExecutor("sleep", map[string]string{"host-public-key": SSHTestPublicKey2})
+
+		Expect(executor.RemoteExecute(200 * time.Millisecond)).To(Equal(exit.Exit{Code: constants.CommandTimeout, Msg: "command timed out", Soft: true}))
+		Eventually(server.getSignal).Should(Equal("KILL"))
+	})
+
+	It("closes the connection when keepalives are not answered", func() {
+		server.ignoreKeepAlive = true
+		executor := newExecutor("sleep", map[string]string{
+			"host-public-key":        SSHTestPublicKey2,
+			"additional-ssh-options": "-o ServerAliveInterval=1 -o ServerAliveCountMax=1",
+		})
+
+		err := executor.RemoteExecute(0)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("ssh connection lost: server did not respond to 1 keepalive requests"))
+	})
+})
+
+var hostPublicKeyWithoutComment = strings.Join(strings.Fields(SSHTestPublicKey2)[:2], " ")
+
+// newTestSSHExecutor returns an initialized executor connecting to the server with the client key of the secret
+func newTestSSHExecutor(server *testSSHServer, secretPath string, clioptions *parse.CLIOptions, secret map[string]string) *sshExecutor {
+	secret["type"] = "ssh"
+	secret["user"] = "fedora"
+	secret["ssh-privatekey"] = SSHTestPrivateKey
+	secret["additional-ssh-options"] = "-p " + strconv.Itoa(server.port()) + " " + secret["additional-ssh-options"]
+	for key, value := range secret {
+		Expect(ioutil.WriteFile(path.Join(secretPath, key), []byte(value), 0600)).To(Succeed())
+	}
+
+	attributes := execattributes.NewExecAttributes()
+	Expect(attributes.Init(secretPath)).To(Succeed())
+
+	executor := newSSHExecutor(clioptions, attributes)
+	Expect(executor.Init("127.0.0.1")).To(Succeed())
+	Expect(executor.TestConnection()).To(BeTrue())
+	return executor
+}
+
+// testSSHServer executes these commands: "echo hello", "exit 3", "kill", "no-status" and "sleep".
+// It also keeps files transferred with "scp -t", "scp -f" and checked with "sha256sum" and directories checked with "test -d" in memory.
+type testSSHServer struct {
+	listener         net.Listener
+	config           *ssh.ServerConfig
+	ignoreKeepAlive  bool
+	corruptChecksums bool
+
+	lock   sync.Mutex
+	user   string
+	signal string
+	files  map[string][]byte
+	dirs   map[string]bool
+}
+
+func newTestSSHServer(hostPrivateKey, authorizedKey string) (*testSSHServer, error) {
+	hostSigner, err := ssh.ParsePrivateKey([]byte(hostPrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	authorized, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &testSSHServer{listener: listener, files: map[string][]byte{}, dirs: map[string]bool{}}
+	s.config = &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if !bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, ssh.ErrNoAuth
+			}
+			s.lock.Lock()
+			defer s.lock.Unlock()
+			s.user = conn.User()
+			return nil, nil
+		},
+	}
+	s.config.AddHostKey(hostSigner)
+
+	go s.serve()
+	return s, nil
+}
+
+func (s *testSSHServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *testSSHServer) close() {
+	s.listener.Close()
+}
+
+func (s *testSSHServer) getUser() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.user
+}
+
+func (s *testSSHServer) getSignal() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.signal
+}
+
+func (s *testSSHServer) getFile(name string) ([]byte, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	content, exists := s.files[name]
+	return content, exists
+}
+
+func (s *testSSHServer) setFile(name string, content []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.files[name] = content
+}
+
+func (s *testSSHServer) isDir(name string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.dirs[name]
+}
+
+func (s *testSSHServer) setDir(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dirs[name] = true
+}
+
+func (s *testSSHServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *testSSHServer) handleConn(conn net.Conn) {
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer serverConn.Close()
+
+	go func() {
+		for request := range requests {
+			if s.ignoreKeepAlive {
+				continue
+			}
+			if request.WantReply {
+				request.Reply(false, nil)
+			}
+		}
+	}()
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleSession(channel, channelRequests)
+	}
+}
+
+func (s *testSSHServer) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	signals := make(chan string, 1)
+	defer close(signals)
+
+	for request := range requests {
+		switch request.Type {
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(request.Payload, &payload); err != nil {
+				request.Reply(false, nil)
+				continue
+			}
+			request.Reply(true, nil)
+			go s.runCommand(channel, payload.Command, signals)
+		case "signal":
+			var payload struct{ Signal string }
+			if err := ssh.Unmarshal(request.Payload, &payload); err == nil {
+				s.lock.Lock()
+				s.signal = payload.Signal
+				s.lock.Unlock()
+				signals <- payload.Signal
+			}
+		default:
+			if request.WantReply {
+				request.Reply(false, nil)
+			}
+		}
+	}
+}
+
+func (s *testSSHServer) runCommand(channel ssh.Channel, command string, signals <-chan string) {
+	defer channel.Close()
+
+	if strings.HasPrefix(command, "scp -t -- ") {
+		s.receiveFile(channel, unquote(strings.TrimPrefix(command, "scp -t -- ")))
+		return
+	}
+	if strings.HasPrefix(command, "scp -f -- ") {
+		s.sendFile(channel, unquote(strings.TrimPrefix(command, "scp -f -- ")))
+		return
+	}
+	if strings.HasPrefix(command, "test -d -- ") {
+		if s.isDir(unquote(strings.TrimPrefix(command, "test -d -- "))) {
+			sendExitStatus(channel, 0)
+		} else {
+			sendExitStatus(channel, 1)
+		}
+		return
+	}
+	if strings.HasPrefix(command, "sha256sum -- ") {
+		name := unquote(strings.TrimPrefix(command, "sha256sum -- "))
+		if s.isDir(name) {
+			fmt.Fprintf(channel.Stderr(), "sha256sum: %v: Is a directory\n", name)
+			sendExitStatus(channel, 1)
+			return
+		}
+		content, _ := s.getFile(name)
+		if s.corruptChecksums {
+			content = append(content, '!')
+		}
+		fmt.Fprintf(channel, "%x  %v\n", sha256.Sum256(content), name)
+		sendExitStatus(channel, 0)
+		return
+	}
+
+	switch command {
+	case "echo hello":
+		channel.Write([]byte("hello\n"))
+		channel.Stderr().Write([]byte("hello to stderr\n"))
+		sendExitStatus(channel, 0)
+	case "exit 3":
+		sendExitStatus(channel, 3)
+	case "kill":
+		sendExitSignal(channel, "TERM", "terminated")
+	case "sleep":
+		if signal, ok := <-signals; ok {
+			sendExitSignal(channel, signal, "")
+		}
+	case "no-status":
+	default:
+		channel.Stderr().Write([]byte("command not found\n"))
+		sendExitStatus(channel, 127)
+	}
+}
+
+func (s *testSSHServer) receiveFile(channel ssh.Channel, name string) {
+	reader := bufio.NewReader(channel)
+	channel.Write([]byte{0})
+
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		sendExitStatus(channel, 1)
+		return
+	}
+	fields := strings.Fields(header)
+	size, _ := strconv.Atoi(fields[1])
+	if s.isDir(name) {
+		name = path.Join(name, fields[2])
+	}
+	channel.Write([]byte{0})
+
+	content := make([]byte, size+1)
+	if _, err := io.ReadFull(reader, content); err != nil {
+		sendExitStatus(channel, 1)
+		return
+	}
+	s.setFile(name, content[:size])
+	channel.Write([]byte{0})
+	sendExitStatus(channel, 0)
+}
+
+func (s *testSSHServer) sendFile(channel ssh.Channel, name string) {
+	reader := bufio.NewReader(channel)
+	reader.ReadByte()
+
+	content, exists := s.getFile(name)
+	if !exists {
+		fmt.Fprintf(channel, "\x01scp: %v: No such file or directory\n", name)
+		sendExitStatus(channel, 1)
+		return
+	}
+
+	fmt.Fprintf(channel, "C0640 %v %v\n", len(content), path.Base(name))
+	if response, _ := reader.ReadByte(); response != 0 {
+		sendExitStatus(channel, 1)
+		return
+	}
+	channel.Write(append(content, 0))
+	reader.ReadByte()
+	sendExitStatus(channel, 0)
+}
+
+func unquote(value string) string {
+	return strings.ReplaceAll(strings.Trim(value, "'"), `'\''`, "'")
+}
+
+func sendExitStatus(channel ssh.Channel, status uint32) {
+	channel.SendRequest("exit-status", false, ssh.Marshal(&struct{ Status uint32 }{status}))
+}
+
+func sendExitSignal(channel ssh.Channel, signal, message string) {
+	channel.SendRequest("exit-signal", false, ssh.Marshal(&struct {
+		Signal     string
+		CoreDumped bool
+		Error      string
+		Lang       string
+	}{signal, false, message, ""}))
+}
@@ -0,0 +1,324 @@
+package execute
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	scpOK      = 0
+	scpWarning = 1
+	scpError   = 2
+)
+
+// UploadFiles copies the files to the VM with the scp protocol and verifies their sha256 checksums
+func (e *sshExecutor) UploadFiles() error {
+	return e.transferFiles(e.clioptions.GetUploads(), e.upload)
+}
+
+// DownloadFiles copies the files from the VM with the scp protocol and verifies their sha256 checksums.
+// The local file is replaced only when the whole file was received.
+func (e *sshExecutor) DownloadFiles() error {
+	return e.transferFiles(e.clioptions.GetDownloads(), e.download)
+}
+
+func (e *sshExecutor) transferFiles(transfers []parse.FileTransfer, transfer func(*ssh.Client, parse.FileTransfer) error) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	client, keepAlive, err := e.connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	defer keepAlive.stop()
+
+	for _, fileTransfer := range transfers {
+		if err := transfer(client, fileTransfer); err != nil {
+			if keepAliveErr := keepAlive.getError(); keepAliveErr != nil {
+				return keepAliveErr
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *sshExecutor) upload(client *ssh.Client, transfer parse.FileTransfer) error {
+	file, err := os.Open(transfer.Local)
+	if err != nil {
+		return fmt.Errorf("could not upload %v: %v", transfer.Local, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("could not upload %v: %v", transfer.Local, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("could not upload %v: only regular files can be transferred", transfer.Local)
+	}
+	if err := e.checkSizeLimit(transfer.Local, info.Size()); err != nil {
+		return err
+	}
+
+	// like scp, upload into a directory under the local name, so the checksum of the uploaded file can be verified
+	isDir, err := isRemoteDirectory(client, transfer.Remote)
+	if err != nil {
+		return fmt.Errorf("could not upload %v to %v: %v", transfer.Local, transfer.Remote, err)
+	}
+	if isDir {
+		transfer.Remote = path.Join(transfer.Remote, filepath.Base(transfer.Local))
+	}
+
+	log.Logger().Debug("uploading a file", zap.String("local", transfer.Local), zap.String("remote", transfer.Remote), zap.Int64("size", info.Size()))
+	checksum := sha256.New()
+	err = runSCP(client, "-t", transfer.Remote, func(stdin io.Writer, stdout *bufio.Reader) error {
+		if err := readSCPResponse(stdout); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(stdin, "C%04o %d %s\n", info.Mode().Perm(), info.Size(), path.Base(transfer.Remote)); err != nil {
+			return err
+		}
+		if err := readSCPResponse(stdout); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(stdin, io.TeeReader(file, checksum), info.Size()); err != nil {
+			return fmt.Errorf("%v was changed during the upload: %v", transfer.Local, err)
+		}
+		if _, err := stdin.Write([]byte{scpOK}); err != nil {
+			return err
+		}
+		return readSCPResponse(stdout)
+	})
+	if err != nil {
+		return fmt.Errorf("could not upload %v to %v: %v", transfer.Local, transfer.Remote, err)
+	}
+
+	if err := verifyRemoteChecksum(client, transfer.Remote, checksum); err != nil {
+		return err
+	}
+	log.Logger().Info("uploaded a file", zap.String("local", transfer.Local), zap.String("remote", transfer.Remote),
+		zap.Int64("size", info.Size()), zap.String("sha256", hex.EncodeToString(checksum.Sum(nil))))
+	return nil
+}
+
+func (e *sshExecutor) download(client *ssh.Client, transfer parse.FileTransfer) error {
+	if err := os.MkdirAll(filepath.Dir(transfer.Local), 0755); err != nil {
+		return fmt.Errorf("could not download to %v: %v", transfer.Local, err)
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(transfer.Local), "."+filepath.Base(transfer.Local)+".download-")
+	if err != nil {
+		return fmt.Errorf("could not download to %v: %v", transfer.Local, err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	log.Logger().Debug("downloading a file", zap.String("remote", transfer.Remote), zap.String("local", transfer.Local))
+	var size int64
+	var mode os.FileMode
+	checksum := sha256.New()
+	err = runSCP(client, "-f", transfer.Remote, func(stdin io.Writer, stdout *bufio.Reader) error {
+		if _, err := stdin.Write([]byte{scpOK}); err != nil {
+			return err
+		}
+
+		var headerErr error
+		if mode, size, headerErr = readSCPFileHeader(stdout); headerErr != nil {
+			return headerErr
+		}
+		if err := e.checkSizeLimit(transfer.Remote, size); err != nil {
+			return err
+		}
+
+		if _, err := stdin.Write([]byte{scpOK}); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(io.MultiWriter(tmpFile, checksum), stdout, size); err != nil {
+			return err
+		}
+		if err := readSCPResponse(stdout); err != nil {
+			return err
+		}
+		_, writeErr := stdin.Write([]byte{scpOK})
+		return writeErr
+	})
+	if err != nil {
+		return fmt.Errorf("could not download %v to %v: %v", transfer.Remote, transfer.Local, err)
+	}
+
+	if err := verifyRemoteChecksum(client, transfer.Remote, checksum); err != nil {
+		return err
+	}
+
+	if err := tmpFile.Chmod(mode); err != nil {
+		return fmt.Errorf("could not download to %v: %v", transfer.Local, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("could not download to %v: %v", transfer.Local, err)
+	}
+	if err := os.Rename(tmpFile.Name(), transfer.Local); err != nil {
+		return fmt.Errorf("could not download to %v: %v", transfer.Local, err)
+	}
+
+	log.Logger().Info("downloaded a file", zap.String("remote", transfer.Remote), zap.String("local", transfer.Local),
+		zap.Int64("size", size), zap.String("sha256", hex.EncodeToString(checksum.Sum(nil))))
+	return nil
+}
+
+func (e *sshExecutor) checkSizeLimit(name string, size int64) error {
+	if sizeLimit := e.clioptions.GetTransferSizeLimit(); size > sizeLimit {
+		return fmt.Errorf("%v has %v bytes which exceeds the transfer size limit of %v bytes", name, size, sizeLimit)
+	}
+	return nil
+}
+
+// runSCP runs scp in the source (-f) or sink (-t) mode in the VM and talks to it with the transfer function
+func runSCP(client *ssh.Client, mode string, remotePath string, transfer func(stdin io.Writer, stdout *bufio.Reader) error) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+
+	if err := session.Start(fmt.Sprintf("scp %v -- %v", mode, shellQuote(remotePath))); err != nil {
+		return err
+	}
+
+	if err := transfer(stdin, bufio.NewReader(stdout)); err != nil {
+		return err
+	}
+	// scp may have already exited
+	if err := stdin.Close(); err != nil && err != io.EOF {
+		return err
+	}
+
+	if err := session.Wait(); err != nil {
+		if stderr.Len() > 0 {
+			return fmt.Errorf("%v: %v", err, strings.TrimSpace(stderr.String()))
+		}
+		return err
+	}
+	return nil
+}
+
+func readSCPResponse(reader *bufio.Reader) error {
+	response, err := reader.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch response {
+	case scpOK:
+		return nil
+	case scpWarning, scpError:
+		message, _ := reader.ReadString('\n')
+		return fmt.Errorf("scp: %v", strings.TrimSpace(message))
+	default:
+		return fmt.Errorf("unexpected scp response %q", response)
+	}
+}
+
+// readSCPFileHeader reads the "C<mode> <size> <name>" header of a single file
+func readSCPFileHeader(reader *bufio.Reader) (os.FileMode, int64, error) {
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch header[0] {
+	case 'C':
+	case scpWarning, scpError:
+		return 0, 0, fmt.Errorf("scp: %v", strings.TrimSpace(header[1:]))
+	case 'D':
+		return 0, 0, fmt.Errorf("only regular files can be transferred")
+	default:
+		return 0, 0, fmt.Errorf("unexpected scp header %q", strings.TrimSpace(header))
+	}
+
+	fields := strings.SplitN(strings.TrimSpace(header[1:]), " ", 3)
+	if len(fields) != 3 {
+		return 0, 0, fmt.Errorf("unexpected scp header %q", strings.TrimSpace(header))
+	}
+
+	mode, err := strconv.ParseUint(fields[0], 8, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected scp header %q", strings.TrimSpace(header))
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || size < 0 {
+		return 0, 0, fmt.Errorf("unexpected scp header %q", strings.TrimSpace(header))
+	}
+
+	return os.FileMode(mode).Perm(), size, nil
+}
+
+// isRemoteDirectory checks with test -d whether the path is a directory in the VM
+func isRemoteDirectory(client *ssh.Client, remotePath string) (bool, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return false, err
+	}
+	defer session.Close()
+
+	if err := session.Run("test -d -- " + shellQuote(remotePath)); err != nil {
+		if exitErr, ok := err.(*ssh.ExitError); ok && exitErr.ExitStatus() == 1 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// verifyRemoteChecksum compares the checksum of the transferred data with the output of sha256sum in the VM
+func verifyRemoteChecksum(client *ssh.Client, remotePath string, checksum hash.Hash) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	output, err := session.Output("sha256sum -- " + shellQuote(remotePath))
+	if err != nil {
+		return fmt.Errorf("could not compute sha256 checksum of %v in the VM: %v", remotePath, err)
+	}
+
+	fields := strings.Fields(string(output))
+	localChecksum := hex.EncodeToString(checksum.Sum(nil))
+	if len(fields) == 0 || !strings.EqualFold(fields[0], localChecksum) {
+		return fmt.Errorf("checksum mismatch of %v: transferred data has sha256 %v, but the file in the VM has %v", remotePath, localChecksum, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,147 @@
+package execute
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/utils/parse"
+	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/sharedtest/testconstants"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SSHExecutor file transfers", func() {
+	var server *testSSHServer
+	var secretPath, filesPath string
+
+	BeforeEach(func() {
+		var err error
+		server, err = newTestSSHServer(SSHTestPrivateKey2, SSHTestPublicKey)
+		Expect(err).Should(Succeed())
+
+		secretPath, err = ioutil.TempDir("", "test-ssh-transfer-secret-")
+		Expect(err).Should(Succeed())
+		filesPath, err = ioutil.TempDir("", "test-ssh-transfer-files-")
+		Expect(err).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		server.close()
+		Expect(os.RemoveAll(secretPath)).To(Succeed())
+		Expect(os.RemoveAll(filesPath)).To(Succeed())
+	})
+
+	newExecutor := func(uploads, downloads []string, transferSizeLimit string) *sshExecutor {
+		clioptions := &parse.CLIOptions{
+			VirtualMachineName:      "vm",
+			VirtualMachineNamespace: "default",
+			Script:                  "echo hello",
+			ConnectionSecretName:    "my-secret",
+			Uploads:                 uploads,
+			Downloads:               downloads,
+			TransferSizeLimit:       transferSizeLimit,
+		}
+		Expect(clioptions.Init()).To(Succeed())
+		return newTestSSHExecutor(server, secretPath, clioptions, map[string]string{"host-public-key": SSHTestPublicKey2})
+	}
+
+	It("uploads files", func() {
+		fixture := filepath.Join(filesPath, "fixture.json")
+		Expect(ioutil.WriteFile(fixture, []byte(`{"key": "value"}`), 0644)).To(Succeed())
+
+		executor := newExecutor([]string{fixture + ":/tmp/fixture.json", fixture + ":fixture's copy.json"}, nil, "")
+		Expect(executor.UploadFiles()).To(Succeed())
+
+		content, exists := server.getFile("/tmp/fixture.json")
+		Expect(exists).To(BeTrue())
+		Expect(string(content)).To(Equal(`{"key": "value"}`))
+		content, exists = server.getFile("fixture's copy.json")
+		Expect(exists).To(BeTrue())
+		Expect(string(content)).To(Equal(`{"key": "value"}`))
+	})
+
+	It("uploads a file into a directory", func() {
+		fixture := filepath.Join(filesPath, "fixture.json")
+		Expect(ioutil.WriteFile(fixture, []byte(`{"key": "value"}`), 0644)).To(Succeed())
+		server.setDir("/tmp")
+
+		executor := newExecutor([]string{fixture + ":/tmp"}, nil, "")
+		Expect(executor.UploadFiles()).To(Succeed())
+
+		content, exists := server.getFile("/tmp/fixture.json")
+		Expect(exists).To(BeTrue())
+		Expect(string(content)).To(Equal(`{"key": "value"}`))
+		_, exists = server.getFile("/tmp")
+		Expect(exists).To(BeFalse())
+	})
+
+	It("downloads files", func() {
+		server.setFile("/var/log/test:1.log", []byte("test passed\n"))
+		report := filepath.Join(filesPath, "reports", "test.log")
+
+		executor := newExecutor(nil, []string{"/var/log/test:1.log:" + report}, "")
+		Expect(executor.DownloadFiles()).To(Succeed())
+
+		content, err := ioutil.ReadFile(report)
+		Expect(err).Should(Succeed())
+		Expect(string(content)).To(Equal("test passed\n"))
+
+		info, err := os.Stat(report)
+		Expect(err).Should(Succeed())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0640)))
+
+		files, err := ioutil.ReadDir(filepath.Join(filesPath, "reports"))
+		Expect(err).Should(Succeed())
+		Expect(files).To(HaveLen(1))
+	})
+
+	It("does nothing without transfers", func() {
+		executor := newExecutor(nil, nil, "")
+		Expect(executor.UploadFiles()).To(Succeed())
+		Expect(executor.DownloadFiles()).To(Succeed())
+	})
+
+	It("rejects an upload over the size limit", func() {
+		fixture := filepath.Join(filesPath, "fixture.txt")
+		Expect(ioutil.WriteFile(fixture, []byte("12345"), 0644)).To(Succeed())
+
+		executor := newExecutor([]string{fixture + ":fixture.txt"}, nil, "4")
+		err := executor.UploadFiles()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(fixture + " has 5 bytes which exceeds the transfer size limit of 4 bytes"))
+
+		_, exists := server.getFile("fixture.txt")
+		Expect(exists).To(BeFalse())
+	})
+
+	It("rejects a download over the size limit", func() {
+		server.setFile("big.log", []byte("12345"))
+		report := filepath.Join(filesPath, "big.log")
+
+		executor := newExecutor(nil, []string{"big.log:" + report}, "4")
+		err := executor.DownloadFiles()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("could not download big.log to " + report + ": big.log has 5 bytes which exceeds the transfer size limit of 4 bytes"))
+		Expect(report).ToNot(BeAnExistingFile())
+	})
+
+	It("fails on a checksum mismatch", func() {
+		server.corruptChecksums = true
+		server.setFile("test.log", []byte("test passed\n"))
+		report := filepath.Join(filesPath, "test.log")
+
+		executor := newExecutor(nil, []string{"test.log:" + report}, "")
+		err := executor.DownloadFiles()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("checksum mismatch of test.log: transferred data has sha256 "))
+		Expect(report).ToNot(BeAnExistingFile())
+	})
+
+	It("fails when the remote file is missing", func() {
+		executor := newExecutor(nil, []string{"missing.log:" + filepath.Join(filesPath, "missing.log")}, "")
+		err := executor.DownloadFiles()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("scp: missing.log: No such file or directory"))
+	})
+})
@@ -1,9 +1,19 @@
 package execute
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 type RemoteExecutor interface {
 	Init(ipAddress string) error
 	TestConnection() bool
+	// SetOutput sets the writers of the remote stdout and stderr
+	SetOutput(stdout, stderr io.Writer)
 	RemoteExecute(timeout time.Duration) error
+	UploadFiles() error
+	DownloadFiles() error
+	// GetObservedHostPublicKey returns the verified key of the host or an empty string if the executor does not use host keys
+	GetObservedHostPublicKey() string
+	IsHostKeyTrustedOnFirstUse() bool
 }
@@ -1,21 +1,37 @@
 package parse
 
 import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
 	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"time"
 )
 
 const (
-	vmNameOptionName      = "vm-name"
-	vmNamespaceOptionName = "vm-namespace"
-	stopOptionName        = "stop"
-	deleteOptionName      = "delete"
-	commandOptionName     = "command"
-	commandArgsOptionName = "command-args"
-	scriptOptionName      = "script"
+	vmNameOptionName            = "vm-name"
+	vmNamespaceOptionName       = "vm-namespace"
+	stopOptionName              = "stop"
+	deleteOptionName            = "delete"
+	commandOptionName           = "command"
+	commandArgsOptionName       = "command-args"
+	scriptOptionName            = "script"
+	saveHostPublicKeyOptionName = "save-host-public-key"
+	uploadOptionName            = "upload"
+	downloadOptionName          = "download"
+	transferSizeLimitOptionName = "transfer-size-limit"
+	captureOutputOptionName     = "capture-output"
+	resultJSONPathOptionName    = "result-jsonpath"
 )
 
+var defaultTransferSizeLimit = resource.MustParse("1Gi")
+
+// FileTransfer is a copy of a single regular file between the task and the VM
+type FileTransfer struct {
+	Local  string
+	Remote string
+}
+
 type CLIOptions struct {
 	VirtualMachineName      string   `arg:"--vm-name,env:VM_NAME,required" placeholder:"NAME" help:"Name of a VM to execute the action in"`
 	VirtualMachineNamespace string   `arg:"--vm-namespace,env:VM_NAMESPACE" placeholder:"NAMESPACE" help:"Namespace of a VM to execute the action in"`
@@ -23,9 +39,24 @@ type CLIOptions struct {
 	Delete                  string   `arg:"--delete" placeholder:"true|false" help:"Deletes the VM after executing the action"`
 	Timeout                 string   `arg:"--timeout" help:"Timeout for the command/script (includes potential VM start). The VM will be stoped or deleted accordingly once the timout expires. Should be in a 3h2m1s format."`
 	Script                  string   `arg:"--script,env:EXECUTE_SCRIPT" placeholder:"SCRIPT" help:"Script to execute in a VM (can be set by EXECUTE_SCRIPT env variable)"`
-	ConnectionSecretName    string   `arg:"--connectionSecretName,env:CONNECTION_SECRET_NAME" placeholder:"NAME" help:"Name of the connection secret (used only for validation)"`
+	ConnectionSecretName    string   `arg:"--connectionSecretName,env:CONNECTION_SECRET_NAME" placeholder:"NAME" help:"Name of the connection secret (used for validation and for saving the host public key)"`
+	SaveHostPublicKey       string   `arg:"--save-host-public-key,env:SAVE_HOST_PUBLIC_KEY" placeholder:"true|false" help:"Saves the host public key trusted on first use to the connection secret as host-public-key"`
+	Uploads                 []string `arg:"--upload" placeholder:"LOCAL:REMOTE" help:"Files to upload to a VM before executing the command/script"`
+	Downloads               []string `arg:"--download" placeholder:"REMOTE:LOCAL" help:"Files to download from a VM after executing the command/script, eg. to a workspace"`
+	TransferSizeLimit       string   `arg:"--transfer-size-limit,env:TRANSFER_SIZE_LIMIT" placeholder:"QUANTITY" help:"Maximum size of each uploaded or downloaded file, eg. 100Mi. Defaults to 1Gi."`
+	CaptureOutput           string   `arg:"--capture-output,env:CAPTURE_OUTPUT" placeholder:"true|false" help:"Records the stdout, stderr and exit code of the command/script as results. Results over the Tekton size limit are truncated."`
+	StdoutFile              string   `arg:"--stdout-file,env:STDOUT_FILE" placeholder:"PATH" help:"Write the full stdout of the command/script to this file, eg. in a workspace"`
+	StderrFile              string   `arg:"--stderr-file,env:STDERR_FILE" placeholder:"PATH" help:"Write the full stderr of the command/script to this file, eg. in a workspace"`
+	ResultJSONPath          string   `arg:"--result-jsonpath,env:RESULT_JSONPATH" placeholder:"JSONPATH" help:"Record the stdout of the command/script parsed as JSON and projected by this JSONPath expression into the output result, eg. {.status}"`
 	Debug                   bool     `arg:"--debug" help:"Sets DEBUG log level"`
+	LogFormat               string   `arg:"--log-format,env:LOG_FORMAT" placeholder:"json|console" help:"Log format. One of: json|console. Defaults to json."`
+	MetricsFile             string   `arg:"--metrics-file,env:METRICS_FILE" placeholder:"PATH" help:"Write the durations and outcomes of the task to this file in OpenMetrics text format, eg. in a workspace."`
+	MetricsPushgatewayURL   string   `arg:"--metrics-pushgateway-url,env:METRICS_PUSHGATEWAY_URL" placeholder:"URL" help:"Push the durations and outcomes of the task to this Pushgateway, eg. http://pushgateway:9091"`
 	Command                 []string `arg:"positional" placeholder:"COMMAND" help:"Command to execute in a VM"`
+
+	uploads           []FileTransfer
+	downloads         []FileTransfer
+	transferSizeLimit int64
 }
 
 func (c *CLIOptions) GetDebugLevel() zapcore.Level {
@@ -35,6 +66,18 @@ func (c *CLIOptions) GetDebugLevel() zapcore.Level {
 	return zapcore.InfoLevel
 }
 
+func (c *CLIOptions) GetLogFormat() log.LogFormat {
+	return log.LogFormat(c.LogFormat)
+}
+
+func (c *CLIOptions) GetMetricsFile() string {
+	return c.MetricsFile
+}
+
+func (c *CLIOptions) GetMetricsPushgatewayURL() string {
+	return c.MetricsPushgatewayURL
+}
+
 func (c *CLIOptions) GetVirtualMachineNamespace() string {
 	return c.VirtualMachineNamespace
 }
@@ -54,6 +97,35 @@ func (c *CLIOptions) GetScriptTimeout() time.Duration {
 	return 0
 }
 
+func (c *CLIOptions) GetUploads() []FileTransfer {
+	return c.uploads
+}
+
+func (c *CLIOptions) GetDownloads() []FileTransfer {
+	return c.downloads
+}
+
+// GetTransferSizeLimit returns the maximum size of a transferred file in bytes
+func (c *CLIOptions) GetTransferSizeLimit() int64 {
+	return c.transferSizeLimit
+}
+
+func (c *CLIOptions) GetStdoutFile() string {
+	return c.StdoutFile
+}
+
+func (c *CLIOptions) GetStderrFile() string {
+	return c.StderrFile
+}
+
+func (c *CLIOptions) GetResultJSONPath() string {
+	return c.ResultJSONPath
+}
+
+func (c *CLIOptions) ShouldCaptureOutput() bool {
+	return zutils.IsTrue(c.CaptureOutput)
+}
+
 func (c *CLIOptions) ShouldStop() bool {
 	return zutils.IsTrue(c.Stop)
 }
@@ -62,6 +134,10 @@ func (c *CLIOptions) ShouldDelete() bool {
 	return zutils.IsTrue(c.Delete)
 }
 
+func (c *CLIOptions) ShouldSaveHostPublicKey() bool {
+	return zutils.IsTrue(c.SaveHostPublicKey)
+}
+
 func (c *CLIOptions) Init() error {
 	c.trimSpaces()
 
@@ -89,5 +165,21 @@ func (c *CLIOptions) Init() error {
 		return err
 	}
 
+	if err := c.resolveFileTransfers(); err != nil {
+		return err
+	}
+
+	if err := c.validateResultJSONPath(); err != nil {
+		return err
+	}
+
+	if err := c.validateLogFormat(); err != nil {
+		return err
+	}
+
+	if err := c.validateMetricsPushgatewayURL(); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -77,6 +77,68 @@ var _ = Describe("CLIOptions", func() {
 			Delete:                  "yes",
 			ConnectionSecretName:    "my-secret",
 		}),
+		table.Entry("invalid save host public key", "invalid option save-host-public-key maybe, only true|false is allowed", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			SaveHostPublicKey:       "maybe",
+			ConnectionSecretName:    "my-secret",
+		}),
+		table.Entry("invalid upload", "invalid option upload fixture.json, LOCAL:REMOTE format is expected", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			Uploads:                 []string{"fixture.json"},
+		}),
+		table.Entry("invalid download", "invalid option download report.xml:, REMOTE:LOCAL format is expected", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			Downloads:               []string{"report.xml:"},
+		}),
+		table.Entry("download without command", "upload|download options require command or script option", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Stop:                    "true",
+			Downloads:               []string{"report.xml:/workspace/report.xml"},
+		}),
+		table.Entry("invalid transfer size limit", "invalid option transfer-size-limit -1Mi, a positive quantity is expected, eg. 100Mi", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			TransferSizeLimit:       "-1Mi",
+		}),
+		table.Entry("invalid capture output", "invalid option capture-output all, only true|false is allowed", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			CaptureOutput:           "all",
+		}),
+		table.Entry("invalid result jsonpath", "invalid result-jsonpath: could not parse {.status JSONPath: unclosed action", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			ResultJSONPath:          "{.status",
+		}),
+		table.Entry("invalid log format", "text is not a valid log format", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			LogFormat:               "text",
+		}),
+		table.Entry("invalid Pushgateway URL", "pushgateway:9091 is not a valid Pushgateway URL", &parse.CLIOptions{
+			VirtualMachineName:      "test",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			MetricsPushgatewayURL:   "pushgateway:9091",
+		}),
 	)
 	//
 	table.DescribeTable("Parses and returns correct values", func(options *parse.CLIOptions, expectedOptions map[string]interface{}) {
@@ -99,6 +161,29 @@ var _ = Describe("CLIOptions", func() {
 			"GetScriptTimeout":           0 * time.Second,
 			"ShouldStop":                 false,
 			"ShouldDelete":               false,
+			"ShouldSaveHostPublicKey":    false,
+			"GetUploads":                 []parse.FileTransfer(nil),
+			"GetDownloads":               []parse.FileTransfer(nil),
+			"GetTransferSizeLimit":       int64(1024 * 1024 * 1024),
+			"ShouldCaptureOutput":        false,
+			"GetStdoutFile":              "",
+			"GetStderrFile":              "",
+			"GetResultJSONPath":          "",
+		}),
+		table.Entry("handles output cli arguments", &parse.CLIOptions{
+			VirtualMachineName:      "vm",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			CaptureOutput:           "true",
+			StdoutFile:              "/workspace/stdout.txt ",
+			StderrFile:              " /workspace/stderr.txt",
+			ResultJSONPath:          " {.status}",
+		}, map[string]interface{}{
+			"ShouldCaptureOutput": true,
+			"GetStdoutFile":       "/workspace/stdout.txt",
+			"GetStderrFile":       "/workspace/stderr.txt",
+			"GetResultJSONPath":   "{.status}",
 		}),
 		table.Entry("handles Script cli arguments", &parse.CLIOptions{
 			VirtualMachineName:      "vm",
@@ -108,6 +193,7 @@ var _ = Describe("CLIOptions", func() {
 			Timeout:                 "5m10s",
 			Stop:                    "true",
 			Delete:                  "false",
+			SaveHostPublicKey:       "true",
 			ConnectionSecretName:    "my-secret",
 		}, map[string]interface{}{
 			"GetVirtualMachineNamespace": defaultNS,
@@ -116,6 +202,7 @@ var _ = Describe("CLIOptions", func() {
 			"GetScriptTimeout":           5*time.Minute + 10*time.Second,
 			"ShouldStop":                 true,
 			"ShouldDelete":               false,
+			"ShouldSaveHostPublicKey":    true,
 		}),
 		table.Entry("handles simple Command cli arguments", &parse.CLIOptions{
 			VirtualMachineName:      "vm",
@@ -126,6 +213,19 @@ var _ = Describe("CLIOptions", func() {
 			"GetVirtualMachineNamespace": defaultNS,
 			"GetScript":                  "ls",
 		}),
+		table.Entry("handles file transfer cli arguments", &parse.CLIOptions{
+			VirtualMachineName:      "vm",
+			VirtualMachineNamespace: defaultNS,
+			Script:                  script,
+			ConnectionSecretName:    "my-secret",
+			Uploads:                 []string{"/workspace/fixture.json:/tmp/fixture.json", " "},
+			Downloads:               []string{"C:/logs/test.log:/workspace/test.log"},
+			TransferSizeLimit:       " 100Mi ",
+		}, map[string]interface{}{
+			"GetUploads":           []parse.FileTransfer{{Local: "/workspace/fixture.json", Remote: "/tmp/fixture.json"}},
+			"GetDownloads":         []parse.FileTransfer{{Local: "/workspace/test.log", Remote: "C:/logs/test.log"}},
+			"GetTransferSizeLimit": int64(100 * 1024 * 1024),
+		}),
 		table.Entry("handles Command cli arguments", &parse.CLIOptions{
 			VirtualMachineName:      "vm",
 			VirtualMachineNamespace: defaultNS,
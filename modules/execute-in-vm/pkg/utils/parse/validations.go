@@ -3,15 +3,22 @@ package parse
 import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/execute-in-vm/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/metrics"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zconstants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"strings"
 	"time"
 )
 
 func (c *CLIOptions) trimSpaces() {
-	c.VirtualMachineNamespace = strings.TrimSpace(c.VirtualMachineNamespace)
+	for _, strVariablePtr := range []*string{&c.VirtualMachineNamespace, &c.MetricsFile, &c.MetricsPushgatewayURL, &c.TransferSizeLimit,
+		&c.StdoutFile, &c.StderrFile, &c.ResultJSONPath} {
+		*strVariablePtr = strings.TrimSpace(*strVariablePtr)
+	}
 }
 
 func (c *CLIOptions) validateName() error {
@@ -105,6 +112,99 @@ func (c *CLIOptions) validateValues() error {
 		return zerrors.NewSoftError("invalid option delete %v, only true|false is allowed", c.Delete)
 	}
 
+	if !allowedValues[c.SaveHostPublicKey] {
+		return zerrors.NewSoftError("invalid option %v %v, only true|false is allowed", saveHostPublicKeyOptionName, c.SaveHostPublicKey)
+	}
+
+	if !allowedValues[c.CaptureOutput] {
+		return zerrors.NewSoftError("invalid option %v %v, only true|false is allowed", captureOutputOptionName, c.CaptureOutput)
+	}
+
+	return nil
+
+}
+
+func (c *CLIOptions) resolveFileTransfers() error {
+	var err error
+
+	// remote paths can contain colons more likely than local ones
+	if c.uploads, err = parseFileTransfers(uploadOptionName, c.Uploads, true); err != nil {
+		return err
+	}
+
+	if c.downloads, err = parseFileTransfers(downloadOptionName, c.Downloads, false); err != nil {
+		return err
+	}
+
+	if (len(c.uploads) > 0 || len(c.downloads) > 0) && c.GetScript() == "" {
+		return zerrors.NewMissingRequiredError("%v|%v options require %v or %v option", uploadOptionName, downloadOptionName, commandOptionName, scriptOptionName)
+	}
+
+	c.transferSizeLimit = defaultTransferSizeLimit.Value()
+	if c.TransferSizeLimit != "" {
+		sizeLimit, err := resource.ParseQuantity(c.TransferSizeLimit)
+		if err != nil || sizeLimit.Sign() <= 0 {
+			return zerrors.NewMissingRequiredError("invalid option %v %v, a positive quantity is expected, eg. 100Mi", transferSizeLimitOptionName, c.TransferSizeLimit)
+		}
+		c.transferSizeLimit = sizeLimit.Value()
+	}
+
 	return nil
+}
 
+func parseFileTransfers(optionName string, values []string, localFirst bool) ([]FileTransfer, error) {
+	var transfers []FileTransfer
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		var separatorIdx int
+		if localFirst {
+			separatorIdx = strings.Index(value, ":")
+		} else {
+			separatorIdx = strings.LastIndex(value, ":")
+		}
+
+		if separatorIdx <= 0 || separatorIdx == len(value)-1 {
+			if localFirst {
+				return nil, zerrors.NewMissingRequiredError("invalid option %v %v, LOCAL:REMOTE format is expected", optionName, value)
+			}
+			return nil, zerrors.NewMissingRequiredError("invalid option %v %v, REMOTE:LOCAL format is expected", optionName, value)
+		}
+
+		first, second := value[:separatorIdx], value[separatorIdx+1:]
+		if localFirst {
+			transfers = append(transfers, FileTransfer{Local: first, Remote: second})
+		} else {
+			transfers = append(transfers, FileTransfer{Local: second, Remote: first})
+		}
+	}
+
+	return transfers, nil
+}
+
+func (c *CLIOptions) validateResultJSONPath() error {
+	if c.ResultJSONPath != "" {
+		if _, err := output.ParseJSONPath(c.ResultJSONPath); err != nil {
+			return zerrors.NewMissingRequiredError("invalid %v: %v", resultJSONPathOptionName, err.Error())
+		}
+	}
+	return nil
+}
+
+func (c *CLIOptions) validateLogFormat() error {
+	if !log.IsLogFormat(c.LogFormat) {
+		return zerrors.NewMissingRequiredError("%v is not a valid log format", c.LogFormat)
+	}
+	return nil
+}
+
+func (c *CLIOptions) validateMetricsPushgatewayURL() error {
+	if !metrics.IsPushgatewayURL(c.MetricsPushgatewayURL) {
+		return zerrors.NewMissingRequiredError("%v is not a valid Pushgateway URL", c.MetricsPushgatewayURL)
+	}
+	return nil
 }
@@ -5,9 +5,10 @@ import (
 	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/generate"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/secret"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/utils/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/types"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/utils/parse"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	res "github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/results"
 	"go.uber.org/zap"
 )
@@ -18,7 +19,7 @@ func main() {
 	cliOptions := &parse.CLIOptions{}
 	goarg.MustParse(cliOptions)
 
-	logger := log.InitLogger(cliOptions.GetDebugLevel())
+	logger := log.InitLoggerWithFormat(cliOptions.GetDebugLevel(), cliOptions.GetLogFormat())
 	defer logger.Sync()
 
 	log.Logger().Debug("parsed arguments", zap.Reflect("cliOptions", cliOptions))
@@ -26,7 +27,11 @@ func main() {
 		exit.ExitOrDieFromError(InvalidArguments, err)
 	}
 
-	keys, err := generate.GenerateSshKeys(*cliOptions)
+	var keys *types.SshKeys
+	err := log.Phase("GenerateSshKeys", func() (err error) {
+		keys, err = generate.GenerateSshKeys(*cliOptions)
+		return err
+	})
 	if err != nil {
 		exit.ExitOrDieFromError(SshKeysGenerationFailed, err)
 	}
@@ -7,8 +7,9 @@ import (
 	"fmt"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/constants"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/types"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/utils/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/generate-ssh-keys/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/events"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -22,9 +23,10 @@ import (
 )
 
 type SecretFacade struct {
-	clioptions *parse.CLIOptions
-	kubeClient *kubernetes.Clientset
-	keys       types.SshKeys
+	clioptions    *parse.CLIOptions
+	kubeClient    *kubernetes.Clientset
+	eventRecorder events.Recorder
+	keys          types.SshKeys
 }
 
 func NewSecretFacade(clioptions *parse.CLIOptions, keys types.SshKeys) (*SecretFacade, error) {
@@ -35,7 +37,7 @@ func NewSecretFacade(clioptions *parse.CLIOptions, keys types.SshKeys) (*SecretF
 
 	kubeClient := kubernetes.NewForConfigOrDie(config)
 
-	return &SecretFacade{clioptions: clioptions, kubeClient: kubeClient, keys: keys}, nil
+	return &SecretFacade{clioptions: clioptions, kubeClient: kubeClient, eventRecorder: events.NewRecorder(kubeClient.CoreV1()), keys: keys}, nil
 }
 
 func (s SecretFacade) CheckPrivateKeySecretExistence() error {
@@ -89,7 +91,8 @@ func (s *SecretFacade) CreatePrivateKeySecret() (*corev1.Secret, error) {
 	}
 
 	log.Logger().Debug("creating private key secret")
-	return s.kubeClient.CoreV1().Secrets(s.clioptions.GetPrivateKeySecretNamespace()).Create(context.TODO(), secret, v1.CreateOptions{})
+	secret, err := s.kubeClient.CoreV1().Secrets(s.clioptions.GetPrivateKeySecretNamespace()).Create(context.TODO(), secret, v1.CreateOptions{})
+	return s.recordEvent(secret, err, events.CreatedByTektonTask, "Secret with a private key was created by a Tekton task")
 
 }
 
@@ -120,7 +123,8 @@ func (s *SecretFacade) AppendPublicKeySecret(secret *corev1.Secret) (*corev1.Sec
 	}
 
 	log.Logger().Debug("appending public key secret")
-	return s.kubeClient.CoreV1().Secrets(s.clioptions.GetPublicKeySecretNamespace()).Patch(context.TODO(), secret.Name, machinerytypes.JSONPatchType, patchBytes, v1.PatchOptions{})
+	secret, err = s.kubeClient.CoreV1().Secrets(s.clioptions.GetPublicKeySecretNamespace()).Patch(context.TODO(), secret.Name, machinerytypes.JSONPatchType, patchBytes, v1.PatchOptions{})
+	return s.recordEvent(secret, err, events.ModifiedByTektonTask, "Public key %v was added to the Secret by a Tekton task", publicKeyId)
 }
 
 func (s *SecretFacade) CreatePublicKeySecret() (*corev1.Secret, error) {
@@ -139,7 +143,8 @@ func (s *SecretFacade) CreatePublicKeySecret() (*corev1.Secret, error) {
 	}
 
 	log.Logger().Debug("creating public key secret")
-	return s.kubeClient.CoreV1().Secrets(s.clioptions.GetPublicKeySecretNamespace()).Create(context.TODO(), secret, v1.CreateOptions{})
+	secret, err := s.kubeClient.CoreV1().Secrets(s.clioptions.GetPublicKeySecretNamespace()).Create(context.TODO(), secret, v1.CreateOptions{})
+	return s.recordEvent(secret, err, events.CreatedByTektonTask, "Secret with a public key was created by a Tekton task")
 }
 
 func (s *SecretFacade) DeleteSecret(secret *corev1.Secret) error {
@@ -150,6 +155,13 @@ func (s *SecretFacade) DeleteSecret(secret *corev1.Secret) error {
 	return s.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(context.TODO(), secret.Name, v1.DeleteOptions{})
 }
 
+func (s *SecretFacade) recordEvent(secret *corev1.Secret, err error, reason, messageFmt string, args ...interface{}) (*corev1.Secret, error) {
+	if err == nil {
+		s.eventRecorder.Eventf(corev1.SchemeGroupVersion.WithKind("Secret"), secret, reason, messageFmt, args...)
+	}
+	return secret, err
+}
+
 func generatePublicKeyId() string {
 	return fmt.Sprintf("id-rsa-%v.pub", rand.String(5))
 }
@@ -2,6 +2,7 @@ package parse
 
 import (
 	"fmt"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
 	"go.uber.org/zap/zapcore"
@@ -24,6 +25,7 @@ type CLIOptions struct {
 	SshKeygenOptions            string   `arg:"--additional-ssh-keygen-options,env:ADDITIONAL_SSH_KEYGEN_OPTIONS" placeholder:"OPTIONS" help:"Additional options to pass to the ssh-keygen command."`
 	Debug                       bool     `arg:"--debug" help:"Sets DEBUG log level"`
 	PrivateKeyConnectionOptions []string `arg:"positional" placeholder:"KEY1:VAL1 KEY2:VAL2" help:"Additional private-key connection options to use in SSH client. Please see execute-in-vm task SSH section for more details. Eg [\"host-public-key:ssh-rsa AAAAB...\", \"additional-ssh-options:-p 8022\"]."`
+	LogFormat                   string   `arg:"--log-format,env:LOG_FORMAT" placeholder:"json|console" help:"Log format. One of: json|console. Defaults to json."`
 }
 
 func (c *CLIOptions) GetDebugLevel() zapcore.Level {
@@ -33,6 +35,10 @@ func (c *CLIOptions) GetDebugLevel() zapcore.Level {
 	return zapcore.InfoLevel
 }
 
+func (c *CLIOptions) GetLogFormat() log.LogFormat {
+	return log.LogFormat(c.LogFormat)
+}
+
 func (c *CLIOptions) GetPublicKeySecretName() string {
 	return c.PublicKeySecretName
 }
@@ -74,5 +80,10 @@ func (c *CLIOptions) Init() error {
 	if err := c.resolveDefaultNamespaces(); err != nil {
 		return err
 	}
+
+	if err := c.validateLogFormat(); err != nil {
+		return err
+	}
+
 	return nil
 }
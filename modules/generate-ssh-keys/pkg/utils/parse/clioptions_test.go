@@ -35,6 +35,11 @@ var _ = Describe("CLIOptions", func() {
 		table.Entry("invalid connection options 2", "invalid private-key connection options: no key found before \":root\"; pair should be in \"KEY:VAL\" format", &parse.CLIOptions{
 			PrivateKeyConnectionOptions: []string{":root"},
 		}),
+		table.Entry("invalid log format", "text is not a valid log format", &parse.CLIOptions{
+			PublicKeySecretNamespace:  defaultNS,
+			PrivateKeySecretNamespace: defaultNS,
+			LogFormat:                 "text",
+		}),
 	)
 
 	table.DescribeTable("Parses and returns correct values", func(options *parse.CLIOptions, expectedOptions map[string]interface{}) {
@@ -2,6 +2,7 @@ package parse
 
 import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"strings"
@@ -54,3 +55,10 @@ func (c *CLIOptions) resolveDefaultNamespaces() error {
 	}
 	return nil
 }
+
+func (c *CLIOptions) validateLogFormat() error {
+	if !log.IsLogFormat(c.LogFormat) {
+		return zerrors.NewMissingRequiredError("%v is not a valid log format", c.LogFormat)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
 	res "github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/results"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+	templatev1 "github.com/openshift/api/template/v1"
 	"go.uber.org/zap"
 )
 
@@ -21,7 +22,7 @@ func main() {
 	cliOptions := &parse.CLIOptions{}
 	goarg.MustParse(cliOptions)
 
-	logger := log.InitLogger(cliOptions.GetDebugLevel())
+	logger := log.InitLoggerWithFormat(cliOptions.GetDebugLevel(), cliOptions.GetLogFormat())
 	defer logger.Sync()
 
 	err := cliOptions.Init()
@@ -36,13 +37,19 @@ func main() {
 		exit.ExitOrDieFromError(ModifyTemplateErrorExitCode, err)
 	}
 
-	updatedTemplate, err := templateUpdator.ModifyTemplate()
+	var updatedTemplate *templatev1.Template
+	err = log.Phase("ModifyTemplate", func() (err error) {
+		updatedTemplate, err = templateUpdator.ModifyTemplate()
+		return err
+	})
 	if err != nil {
 		exit.ExitOrDieFromError(TemplateUpdateErrorExitCode, err,
 			zerrors.IsStatusError(err, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
 		)
 	}
 
+	log.AddTarget("Template", updatedTemplate.Namespace, updatedTemplate.Name)
+
 	results := map[string]string{
 		NameResultName:      updatedTemplate.Name,
 		NamespaceResultName: updatedTemplate.Namespace,
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/modify-vm-template/pkg/utils/parse"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/events"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
 	k8sv1 "k8s.io/api/core/v1"
@@ -16,6 +17,7 @@ import (
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 )
@@ -50,6 +52,7 @@ func (t *templateProvider) Patch(template *v1.Template) (*templatev1.Template, e
 type TemplateUpdator struct {
 	cliOptions       *parse.CLIOptions
 	templateProvider TemplateProvider
+	eventRecorder    events.Recorder
 }
 
 func NewTemplateUpdator(cliOptions *parse.CLIOptions) (*TemplateUpdator, error) {
@@ -63,6 +66,7 @@ func NewTemplateUpdator(cliOptions *parse.CLIOptions) (*TemplateUpdator, error)
 	return &TemplateUpdator{
 		cliOptions:       cliOptions,
 		templateProvider: NewTemplateProvider(templateclientset.NewForConfigOrDie(config)),
+		eventRecorder:    events.NewRecorder(kubernetes.NewForConfigOrDie(config).CoreV1()),
 	}, nil
 }
 
@@ -78,7 +82,13 @@ func (t *TemplateUpdator) ModifyTemplate() (*v1.Template, error) {
 		return nil, err
 	}
 
-	return t.templateProvider.Patch(updatedTemplate)
+	patchedTemplate, err := t.templateProvider.Patch(updatedTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	t.eventRecorder.Eventf(templatev1.SchemeGroupVersion.WithKind("Template"), patchedTemplate, events.ModifiedByTektonTask, "Template was modified by a Tekton task")
+	return patchedTemplate, nil
 }
 
 func (t *TemplateUpdator) UpdateTemplate(template *v1.Template) (*v1.Template, error) {
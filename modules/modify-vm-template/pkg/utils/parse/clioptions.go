@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zutils"
@@ -42,6 +43,7 @@ type CLIOptions struct {
 	vmAnnotations       map[string]string
 	disks               []kubevirtv1.Disk
 	volumes             []kubevirtv1.Volume
+	LogFormat           string `arg:"--log-format,env:LOG_FORMAT" placeholder:"json|console" help:"Log format. One of: json|console. Defaults to json."`
 }
 
 func (c *CLIOptions) GetDebugLevel() zapcore.Level {
@@ -51,6 +53,10 @@ func (c *CLIOptions) GetDebugLevel() zapcore.Level {
 	return zapcore.InfoLevel
 }
 
+func (c *CLIOptions) GetLogFormat() log.LogFormat {
+	return log.LogFormat(c.LogFormat)
+}
+
 func (c *CLIOptions) GetCPUSockets() uint32 {
 	res, _ := strconv.ParseUint(c.CPUSockets, 10, 32)
 	return uint32(res)
@@ -277,5 +283,9 @@ func (c *CLIOptions) assertValidTypes() error {
 	if !output.IsOutputType(string(c.Output)) {
 		return zerrors.NewMissingRequiredError("%v is not a valid output type", c.Output)
 	}
+
+	if !log.IsLogFormat(c.LogFormat) {
+		return zerrors.NewMissingRequiredError("%v is not a valid log format", c.LogFormat)
+	}
 	return nil
 }
@@ -39,6 +39,7 @@ var _ = Describe("CLIOptions", func() {
 		},
 			table.Entry("no template-name", "template-name param has to be specified", &parse.CLIOptions{}),
 			table.Entry("wrong output type", "non-existing is not a valid output type", &parse.CLIOptions{TemplateName: testString, Output: "non-existing"}),
+			table.Entry("wrong log format", "text is not a valid log format", &parse.CLIOptions{TemplateName: testString, LogFormat: "text"}),
 			table.Entry("wrong cpu sockets", "parsing \"wrong cpu sockets\": invalid syntax", &parse.CLIOptions{TemplateName: testString, CPUCores: testNumberOfCPU, CPUThreads: "wrong cpu sockets"}),
 			table.Entry("wrong cpu cores", "parsing \"wrong cpu cores\": invalid syntax", &parse.CLIOptions{TemplateName: testString, CPUCores: "wrong cpu cores"}),
 			table.Entry("wrong cpu threads", "parsing \"wrong cpu threads\": invalid syntax", &parse.CLIOptions{TemplateName: testString, CPUCores: testNumberOfCPU, CPUThreads: "wrong cpu threads"}),
@@ -11,6 +11,7 @@ require (
 	go.uber.org/zap v1.16.0
 	k8s.io/api v0.20.2
 	k8s.io/apimachinery v0.20.2
+	k8s.io/client-go v12.0.0+incompatible
 	kubevirt.io/client-go v0.45.0
 	sigs.k8s.io/yaml v1.2.0
 )
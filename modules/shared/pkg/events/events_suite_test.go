@@ -0,0 +1,22 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"go.uber.org/zap"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEvents(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Events Suite")
+}
+
+func SetupTestSuite() {
+	log.InitLogger(zap.InfoLevel)
+}
+
+var _ = BeforeSuite(SetupTestSuite)
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	CreatedByTektonTask   = "CreatedByTektonTask"
+	ModifiedByTektonTask  = "ModifiedByTektonTask"
+	ReplacedByTektonTask  = "ReplacedByTektonTask"
+	StartedByTektonTask   = "StartedByTektonTask"
+	StoppedAfterExecution = "StoppedAfterExecution"
+	DeletedAfterExecution = "DeletedAfterExecution"
+)
+
+// DefaultSource is the source of the events outside of a TaskRun
+const DefaultSource = "kubevirt-tekton-tasks"
+
+type Recorder interface {
+	// Eventf records a Normal event on the object. Failures are only logged, so the task does not fail because of them.
+	Eventf(kind schema.GroupVersionKind, object metav1.Object, reason, messageFmt string, args ...interface{})
+}
+
+type recorder struct {
+	client corev1client.EventsGetter
+	source string
+}
+
+// NewRecorder returns a recorder with the TaskRun name as the source of the events
+func NewRecorder(client corev1client.EventsGetter) Recorder {
+	return &recorder{
+		client: client,
+		source: getSource(),
+	}
+}
+
+func (r *recorder) Eventf(kind schema.GroupVersionKind, object metav1.Object, reason, messageFmt string, args ...interface{}) {
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", object.GetName(), now.UnixNano()),
+			Namespace: object.GetNamespace(),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:            kind.Kind,
+			APIVersion:      kind.GroupVersion().String(),
+			Namespace:       object.GetNamespace(),
+			Name:            object.GetName(),
+			UID:             object.GetUID(),
+			ResourceVersion: object.GetResourceVersion(),
+		},
+		Reason:         reason,
+		Message:        fmt.Sprintf(messageFmt, args...),
+		Source:         corev1.EventSource{Component: r.source},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           corev1.EventTypeNormal,
+	}
+
+	if _, err := r.client.Events(event.Namespace).Create(context.TODO(), event, metav1.CreateOptions{}); err != nil {
+		log.Logger().Warn("could not record event", zap.String("reason", reason), zap.String("kind", kind.Kind),
+			zap.String("name", object.GetName()), zap.String("namespace", object.GetNamespace()), zap.Error(err))
+		return
+	}
+	log.Logger().Debug("recorded event", zap.String("reason", reason), zap.String("kind", kind.Kind),
+		zap.String("name", object.GetName()), zap.String("namespace", object.GetNamespace()))
+}
+
+func getSource() string {
+	if taskRunName := os.Getenv(log.TaskRunNameEnv); taskRunName != "" {
+		return taskRunName
+	}
+	return DefaultSource
+}
@@ -0,0 +1,82 @@
+package events_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/events"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("Recorder", func() {
+	var client *fakecorev1.FakeCoreV1
+	var secret *corev1.Secret
+
+	BeforeEach(func() {
+		client = &fakecorev1.FakeCoreV1{Fake: &k8stesting.Fake{}}
+		client.AddReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, action.(k8stesting.CreateAction).GetObject(), nil
+		})
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "my-secret",
+				Namespace:       "my-namespace",
+				UID:             types.UID("c0ffee"),
+				ResourceVersion: "42",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(log.TaskRunNameEnv)).To(Succeed())
+	})
+
+	It("records event on the object", func() {
+		Expect(os.Setenv(log.TaskRunNameEnv, "my-taskrun")).To(Succeed())
+
+		events.NewRecorder(client).Eventf(corev1.SchemeGroupVersion.WithKind("Secret"), secret, events.CreatedByTektonTask, "created %v secret", "private key")
+
+		Expect(client.Actions()).To(HaveLen(1))
+		event := client.Actions()[0].(k8stesting.CreateAction).GetObject().(*corev1.Event)
+		Expect(event.Namespace).To(Equal("my-namespace"))
+		Expect(event.Name).To(HavePrefix("my-secret."))
+		Expect(event.InvolvedObject).To(Equal(corev1.ObjectReference{
+			Kind:            "Secret",
+			APIVersion:      "v1",
+			Namespace:       "my-namespace",
+			Name:            "my-secret",
+			UID:             types.UID("c0ffee"),
+			ResourceVersion: "42",
+		}))
+		Expect(event.Reason).To(Equal(events.CreatedByTektonTask))
+		Expect(event.Message).To(Equal("created private key secret"))
+		Expect(event.Type).To(Equal(corev1.EventTypeNormal))
+		Expect(event.Source.Component).To(Equal("my-taskrun"))
+		Expect(event.Count).To(Equal(int32(1)))
+	})
+
+	It("uses default source outside of a TaskRun", func() {
+		events.NewRecorder(client).Eventf(corev1.SchemeGroupVersion.WithKind("Secret"), secret, events.ModifiedByTektonTask, "modified")
+
+		event := client.Actions()[0].(k8stesting.CreateAction).GetObject().(*corev1.Event)
+		Expect(event.Source.Component).To(Equal(events.DefaultSource))
+	})
+
+	It("does not fail when the event cannot be recorded", func() {
+		client.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("forbidden")
+		})
+
+		events.NewRecorder(client).Eventf(corev1.SchemeGroupVersion.WithKind("Secret"), secret, events.ModifiedByTektonTask, "modified")
+
+		Expect(client.Actions()).To(HaveLen(1))
+	})
+})
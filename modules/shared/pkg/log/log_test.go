@@ -1,10 +1,17 @@
 package log_test
 
 import (
+	"errors"
+	"io/ioutil"
+	"os"
+
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 var _ = Describe("Log", func() {
@@ -17,4 +24,104 @@ var _ = Describe("Log", func() {
 		Expect(log.Logger()).ToNot(BeNil())
 		Expect(log.Logger()).To(Equal(first))
 	})
+
+	table.DescribeTable("checks log format", func(value string, expectedValue bool) {
+		Expect(log.IsLogFormat(value)).To(Equal(expectedValue))
+	},
+		table.Entry("empty", "", true),
+		table.Entry("json", "json", true),
+		table.Entry("console", "console", true),
+		table.Entry("invalid", "text", false),
+	)
+
+	Describe("with a new logger", func() {
+		var originalLogger *zap.Logger
+
+		BeforeEach(func() {
+			originalLogger = log.Logger()
+			log.SetLogger(nil)
+		})
+
+		AfterEach(func() {
+			log.SetLogger(originalLogger)
+			Expect(os.Unsetenv(log.LogLevelEnv)).To(Succeed())
+			Expect(os.Unsetenv(log.TaskNameEnv)).To(Succeed())
+			Expect(os.Unsetenv(log.TaskRunNameEnv)).To(Succeed())
+		})
+
+		table.DescribeTable("uses the more verbose level", func(level zapcore.Level, envLevel string, expectedLevel zapcore.Level) {
+			Expect(os.Setenv(log.LogLevelEnv, envLevel)).To(Succeed())
+			logger := log.InitLoggerWithFormat(level, log.ConsoleFormat)
+			Expect(logger.Core().Enabled(expectedLevel)).To(BeTrue())
+			Expect(logger.Core().Enabled(expectedLevel - 1)).To(BeFalse())
+		},
+			table.Entry("no env", zapcore.InfoLevel, "", zapcore.InfoLevel),
+			table.Entry("debug env", zapcore.InfoLevel, "debug", zapcore.DebugLevel),
+			table.Entry("debug flag", zapcore.DebugLevel, "warn", zapcore.DebugLevel),
+			table.Entry("invalid env", zapcore.InfoLevel, "verbose", zapcore.InfoLevel),
+		)
+
+		It("adds task fields", func() {
+			Expect(os.Setenv(log.TaskNameEnv, "execute-in-vm")).To(Succeed())
+			Expect(os.Setenv(log.TaskRunNameEnv, "execute-in-vm-run-1")).To(Succeed())
+
+			r, w, _ := os.Pipe()
+			stderr := os.Stderr
+			os.Stderr = w
+			logger := log.InitLoggerWithFormat(zapcore.InfoLevel, log.JSONFormat)
+			os.Stderr = stderr
+
+			logger.Info("test")
+			_ = logger.Sync()
+			Expect(w.Close()).To(Succeed())
+			output, err := ioutil.ReadAll(r)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring(`"task":"execute-in-vm","taskRun":"execute-in-vm-run-1"`))
+		})
+	})
+
+	Describe("with an observed logger", func() {
+		var originalLogger *zap.Logger
+		var logs *observer.ObservedLogs
+
+		BeforeEach(func() {
+			var core zapcore.Core
+			core, logs = observer.New(zapcore.DebugLevel)
+			originalLogger = log.Logger()
+			log.SetLogger(zap.New(core))
+		})
+
+		AfterEach(func() {
+			log.SetLogger(originalLogger)
+		})
+
+		It("adds target fields", func() {
+			log.AddTarget("VirtualMachine", "default", "my-vm")
+			log.Logger().Info("test")
+			Expect(logs.All()).To(HaveLen(1))
+			Expect(logs.All()[0].ContextMap()).To(Equal(map[string]interface{}{
+				"targetKind":      "VirtualMachine",
+				"targetNamespace": "default",
+				"targetName":      "my-vm",
+			}))
+		})
+
+		It("logs phase duration", func() {
+			Expect(log.Phase("SetupConnection", func() error { return nil })).To(Succeed())
+			finished := logs.FilterMessage("phase finished").All()
+			Expect(finished).To(HaveLen(1))
+			Expect(finished[0].ContextMap()).To(HaveKeyWithValue("phase", "SetupConnection"))
+			Expect(finished[0].ContextMap()).To(HaveKeyWithValue("success", true))
+			Expect(finished[0].ContextMap()).To(HaveKey("duration"))
+		})
+
+		It("logs failed phase", func() {
+			err := errors.New("connection refused")
+			Expect(log.Phase("SetupConnection", func() error { return err })).To(Equal(err))
+			finished := logs.FilterMessage("phase finished").All()
+			Expect(finished).To(HaveLen(1))
+			Expect(finished[0].ContextMap()).To(HaveKeyWithValue("success", false))
+			Expect(finished[0].ContextMap()).To(HaveKeyWithValue("error", "connection refused"))
+		})
+	})
 })
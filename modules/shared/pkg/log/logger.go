@@ -1,13 +1,42 @@
 package log
 
 import (
+	"os"
+	"strings"
+	"time"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+type LogFormat string
+
+const (
+	JSONFormat    LogFormat = "json"
+	ConsoleFormat LogFormat = "console"
+)
+
+const (
+	// LogLevelEnv sets the log level, eg. debug. The more verbose of this and the level of the task is used.
+	LogLevelEnv = "LOG_LEVEL"
+	// TaskNameEnv and TaskRunNameEnv are filled from the pod labels by the downward API
+	TaskNameEnv    = "TASK_NAME"
+	TaskRunNameEnv = "TASKRUN_NAME"
+)
+
 var logger *zap.Logger
 
+func IsLogFormat(value string) bool {
+	val := LogFormat(value)
+	return val == "" || val == JSONFormat || val == ConsoleFormat
+}
+
 func InitLogger(level zapcore.Level) *zap.Logger {
+	return InitLoggerWithFormat(level, JSONFormat)
+}
+
+// InitLoggerWithFormat creates the logger with the task and TaskRun name fields. Falls back to json for an unknown format.
+func InitLoggerWithFormat(level zapcore.Level, format LogFormat) *zap.Logger {
 	if logger != nil {
 		return logger
 	}
@@ -18,9 +47,14 @@ func InitLogger(level zapcore.Level) *zap.Logger {
 	// set opinionated presets
 	config = zap.NewProductionConfig()
 
-	config.Level.SetLevel(level)
+	if format == ConsoleFormat {
+		config.Encoding = string(ConsoleFormat)
+		config.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	config.Level.SetLevel(getLevel(level))
 
-	logger, err = config.Build()
+	logger, err = config.Build(zap.Fields(getStandardFields()...))
 	if err != nil {
 		panic(err)
 	}
@@ -31,3 +65,55 @@ func InitLogger(level zapcore.Level) *zap.Logger {
 func Logger() *zap.Logger {
 	return logger
 }
+
+// SetLogger replaces the logger, eg. with an observed logger in tests
+func SetLogger(newLogger *zap.Logger) {
+	logger = newLogger
+}
+
+// AddTarget adds the object the task operates on to all following logs
+func AddTarget(kind, namespace, name string) {
+	logger = logger.With(zap.String("targetKind", kind), zap.String("targetNamespace", namespace), zap.String("targetName", name))
+}
+
+// Phase runs the phase and logs its duration, eg. for dashboards of how long each phase of a task takes
+func Phase(name string, phase func() error) error {
+	logger.Debug("phase started", zap.String("phase", name))
+	start := time.Now()
+
+	err := phase()
+
+	fields := []zap.Field{zap.String("phase", name), zap.Duration("duration", time.Since(start)), zap.Bool("success", err == nil)}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	logger.Info("phase finished", fields...)
+
+	return err
+}
+
+func getLevel(level zapcore.Level) zapcore.Level {
+	value := strings.TrimSpace(os.Getenv(LogLevelEnv))
+	if value == "" {
+		return level
+	}
+
+	var envLevel zapcore.Level
+	if err := envLevel.Set(value); err == nil && envLevel < level {
+		return envLevel
+	}
+	return level
+}
+
+func getStandardFields() []zap.Field {
+	var fields []zap.Field
+
+	if taskName := os.Getenv(TaskNameEnv); taskName != "" {
+		fields = append(fields, zap.String("task", taskName))
+	}
+	if taskRunName := os.Getenv(TaskRunNameEnv); taskRunName != "" {
+		fields = append(fields, zap.String("taskRun", taskRunName))
+	}
+
+	return fields
+}
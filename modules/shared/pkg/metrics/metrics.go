@@ -0,0 +1,242 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
+)
+
+const (
+	// Namespace prefixes the names of all metrics
+	Namespace = "kubevirt_tekton_tasks"
+	// OpenMetricsContentType is used for the metrics file and the Pushgateway requests
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	pushTimeout            = 10 * time.Second
+)
+
+type Labels map[string]string
+
+type sample struct {
+	labels Labels
+	value  float64
+}
+
+type family struct {
+	help    string
+	samples []*sample
+}
+
+var (
+	families = map[string]*family{}
+	lock     sync.Mutex
+)
+
+func IsPushgatewayURL(value string) bool {
+	if value == "" {
+		return true
+	}
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// SetGauge sets the gauge with the labels to the value. The name is prefixed with Namespace.
+func SetGauge(name, help string, value float64, labels Labels) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	fullName := Namespace + "_" + name
+	fam, ok := families[fullName]
+	if !ok {
+		fam = &family{help: help}
+		families[fullName] = fam
+	}
+
+	for _, s := range fam.samples {
+		if formatLabels(s.labels) == formatLabels(labels) {
+			s.value = value
+			return
+		}
+	}
+	fam.samples = append(fam.samples, &sample{labels: labels, value: value})
+}
+
+// RecordDuration sets the gauge to the seconds elapsed since start
+func RecordDuration(name, help string, start time.Time, labels Labels) {
+	SetGauge(name, help, time.Since(start).Seconds(), labels)
+}
+
+// RecordOutcome sets the gauge to 1 if err is nil and to 0 otherwise
+func RecordOutcome(name, help string, err error, labels Labels) {
+	var value float64
+	if err == nil {
+		value = 1
+	}
+	SetGauge(name, help, value, labels)
+}
+
+// Reset removes all recorded metrics
+func Reset() {
+	lock.Lock()
+	defer lock.Unlock()
+	families = map[string]*family{}
+}
+
+// Write writes the recorded metrics in the OpenMetrics text format. The task and TaskRun names are added to the labels.
+func Write(writer io.Writer) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	var names []string
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	standardLabels := getStandardLabels()
+	var out strings.Builder
+
+	for _, name := range names {
+		fam := families[name]
+		out.WriteString(fmt.Sprintf("# TYPE %v gauge\n", name))
+		if fam.help != "" {
+			out.WriteString(fmt.Sprintf("# HELP %v %v\n", name, escape(fam.help, false)))
+		}
+		for _, s := range fam.samples {
+			labels := make(Labels, len(standardLabels)+len(s.labels))
+			for key, value := range standardLabels {
+				labels[key] = value
+			}
+			for key, value := range s.labels {
+				labels[key] = value
+			}
+			out.WriteString(fmt.Sprintf("%v%v %v\n", name, formatLabels(labels), strconv.FormatFloat(s.value, 'g', -1, 64)))
+		}
+	}
+	out.WriteString("# EOF\n")
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+func WriteToFile(path string) error {
+	var buffer bytes.Buffer
+	if err := Write(&buffer); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+// Push replaces the metrics of the job and TaskRun group in a Pushgateway. The job is the task name or the binary name.
+func Push(pushgatewayURL string) error {
+	var buffer bytes.Buffer
+	if err := Write(&buffer); err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPut, getPushURL(pushgatewayURL), &buffer)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", OpenMetricsContentType)
+
+	response, err := (&http.Client{Timeout: pushTimeout}).Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("unexpected status %v from the Pushgateway: %v", response.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Export writes the metrics to the file and pushes them to the Pushgateway. Empty destinations are skipped.
+func Export(file, pushgatewayURL string) error {
+	multiError := zerrors.NewMultiError()
+
+	if file != "" {
+		if err := WriteToFile(file); err != nil {
+			multiError.Add("file", fmt.Errorf("could not write metrics to %v: %v", file, err))
+		}
+	}
+
+	if pushgatewayURL != "" {
+		if err := Push(pushgatewayURL); err != nil {
+			multiError.Add("push", fmt.Errorf("could not push metrics: %v", err))
+		}
+	}
+
+	return multiError.AsOptional()
+}
+
+func getPushURL(pushgatewayURL string) string {
+	job := os.Getenv(log.TaskNameEnv)
+	if job == "" {
+		job = filepath.Base(os.Args[0])
+	}
+
+	pushURL := strings.TrimSuffix(pushgatewayURL, "/") + "/metrics/job/" + url.PathEscape(job)
+	if taskRunName := os.Getenv(log.TaskRunNameEnv); taskRunName != "" {
+		pushURL += "/taskRun/" + url.PathEscape(taskRunName)
+	}
+	return pushURL
+}
+
+func getStandardLabels() Labels {
+	labels := Labels{}
+
+	if taskName := os.Getenv(log.TaskNameEnv); taskName != "" {
+		labels["task"] = taskName
+	}
+	if taskRunName := os.Getenv(log.TaskRunNameEnv); taskRunName != "" {
+		labels["taskRun"] = taskRunName
+	}
+
+	return labels
+}
+
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var keys []string
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%v=\"%v\"", key, escape(labels[key], true)))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escape(value string, quoted bool) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	if quoted {
+		value = strings.ReplaceAll(value, `"`, `\"`)
+	}
+	return value
+}
@@ -1,4 +1,4 @@
-package log_test
+package metrics_test
 
 import (
 	"testing"
@@ -7,7 +7,7 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-func TestLog(t *testing.T) {
+func TestMetrics(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "Log Suite")
+	RunSpecs(t, "Metrics Suite")
 }
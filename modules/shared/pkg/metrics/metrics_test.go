@@ -0,0 +1,133 @@
+package metrics_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/metrics"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	BeforeEach(func() {
+		metrics.Reset()
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(log.TaskNameEnv)).To(Succeed())
+		Expect(os.Unsetenv(log.TaskRunNameEnv)).To(Succeed())
+	})
+
+	table.DescribeTable("checks Pushgateway URL", func(value string, expectedValue bool) {
+		Expect(metrics.IsPushgatewayURL(value)).To(Equal(expectedValue))
+	},
+		table.Entry("empty", "", true),
+		table.Entry("http", "http://pushgateway:9091", true),
+		table.Entry("https with path", "https://pushgateway.example.com/prefix/", true),
+		table.Entry("no scheme", "pushgateway:9091", false),
+		table.Entry("unsupported scheme", "ftp://pushgateway", false),
+		table.Entry("no host", "http://", false),
+	)
+
+	It("writes no metrics", func() {
+		var out bytes.Buffer
+		Expect(metrics.Write(&out)).To(Succeed())
+		Expect(out.String()).To(Equal("# EOF\n"))
+	})
+
+	It("writes metrics in OpenMetrics format", func() {
+		Expect(os.Setenv(log.TaskNameEnv, "execute-in-vm")).To(Succeed())
+		Expect(os.Setenv(log.TaskRunNameEnv, "run-1")).To(Succeed())
+
+		metrics.SetGauge("ssh_connect_duration_seconds", "SSH connect latency", 1.5, metrics.Labels{"vm": "my-vm"})
+		metrics.RecordOutcome("execute_success", "Whether the execution succeeded", errors.New("failed"), nil)
+		metrics.SetGauge("ssh_connect_duration_seconds", "SSH connect latency", 2, metrics.Labels{"vm": "my-vm"})
+		metrics.SetGauge("ssh_connect_duration_seconds", "SSH connect latency", 0.25, metrics.Labels{"vm": "other\"vm"})
+
+		var out bytes.Buffer
+		Expect(metrics.Write(&out)).To(Succeed())
+		Expect(out.String()).To(Equal(`# TYPE kubevirt_tekton_tasks_execute_success gauge
+# HELP kubevirt_tekton_tasks_execute_success Whether the execution succeeded
+kubevirt_tekton_tasks_execute_success{task="execute-in-vm",taskRun="run-1"} 0
+# TYPE kubevirt_tekton_tasks_ssh_connect_duration_seconds gauge
+# HELP kubevirt_tekton_tasks_ssh_connect_duration_seconds SSH connect latency
+kubevirt_tekton_tasks_ssh_connect_duration_seconds{task="execute-in-vm",taskRun="run-1",vm="my-vm"} 2
+kubevirt_tekton_tasks_ssh_connect_duration_seconds{task="execute-in-vm",taskRun="run-1",vm="other\"vm"} 0.25
+# EOF
+`))
+	})
+
+	It("records duration and outcome", func() {
+		metrics.RecordDuration("wait_duration_seconds", "", time.Now().Add(-2*time.Second), nil)
+		metrics.RecordOutcome("wait_success", "", nil, nil)
+
+		var out bytes.Buffer
+		Expect(metrics.Write(&out)).To(Succeed())
+		Expect(out.String()).To(MatchRegexp(`kubevirt_tekton_tasks_wait_duration_seconds 2\.\d+\n`))
+		Expect(out.String()).To(ContainSubstring("kubevirt_tekton_tasks_wait_success 1\n"))
+	})
+
+	Describe("exports metrics", func() {
+		var tempDir string
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "test-metrics-")
+			Expect(err).Should(Succeed())
+			metrics.SetGauge("vm_ready_duration_seconds", "VM boot time", 30, nil)
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		It("writes to a file", func() {
+			file := filepath.Join(tempDir, "nested", "metrics.txt")
+			Expect(metrics.Export(file, "")).To(Succeed())
+
+			content, err := ioutil.ReadFile(file)
+			Expect(err).Should(Succeed())
+			Expect(string(content)).To(ContainSubstring("kubevirt_tekton_tasks_vm_ready_duration_seconds 30\n"))
+		})
+
+		It("pushes to a Pushgateway", func() {
+			Expect(os.Setenv(log.TaskNameEnv, "create-vm-from-manifest")).To(Succeed())
+			Expect(os.Setenv(log.TaskRunNameEnv, "run-1")).To(Succeed())
+
+			var method, path, contentType, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method, path, contentType = r.Method, r.URL.Path, r.Header.Get("Content-Type")
+				bodyBytes, _ := ioutil.ReadAll(r.Body)
+				body = string(bodyBytes)
+			}))
+			defer server.Close()
+
+			Expect(metrics.Export("", server.URL+"/")).To(Succeed())
+			Expect(method).To(Equal(http.MethodPut))
+			Expect(path).To(Equal("/metrics/job/create-vm-from-manifest/taskRun/run-1"))
+			Expect(contentType).To(Equal(metrics.OpenMetricsContentType))
+			Expect(body).To(ContainSubstring(`kubevirt_tekton_tasks_vm_ready_duration_seconds{task="create-vm-from-manifest",taskRun="run-1"} 30`))
+		})
+
+		It("fails on a Pushgateway error", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "invalid metrics", http.StatusBadRequest)
+			}))
+			defer server.Close()
+
+			err := metrics.Export(filepath.Join(tempDir, "metrics.txt"), server.URL)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("could not push metrics: unexpected status 400 Bad Request from the Pushgateway: invalid metrics\n"))
+			Expect(filepath.Join(tempDir, "metrics.txt")).To(BeAnExistingFile())
+		})
+	})
+})
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ParseJSONPath parses a kubectl style JSONPath expression, eg. {.metadata.name}. Braces are optional.
+func ParseJSONPath(expression string) (*jsonpath.JSONPath, error) {
+	expression = strings.TrimSpace(expression)
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+
+	path := jsonpath.New("output")
+	if err := path.Parse(expression); err != nil {
+		return nil, fmt.Errorf("could not parse %v JSONPath: %v", expression, err.Error())
+	}
+	return path, nil
+}
+
+// JSONPath projects the object by the expression. The object is matched by its json field names.
+func JSONPath(object interface{}, expression string) (string, error) {
+	path, err := ParseJSONPath(expression)
+	if err != nil {
+		return "", err
+	}
+
+	objectBytes, err := json.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+
+	var jsonObject interface{}
+	if err := json.Unmarshal(objectBytes, &jsonObject); err != nil {
+		return "", err
+	}
+
+	var result bytes.Buffer
+	if err := path.Execute(&result, jsonObject); err != nil {
+		return "", fmt.Errorf("could not find %v in the output: %v", expression, err.Error())
+	}
+	return result.String(), nil
+}
@@ -3,6 +3,11 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"sigs.k8s.io/yaml"
 )
 
@@ -18,13 +23,50 @@ func IsOutputType(value string) bool {
 	return val == "" || val == YamlOutput || val == JsonOutput
 }
 
-func PrettyPrint(object interface{}, outputType OutputType) {
+// GetOutputTypeFromPath returns json for .json files and yaml otherwise
+func GetOutputTypeFromPath(path string) OutputType {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return JsonOutput
+	}
+	return YamlOutput
+}
+
+// Marshal returns the object in the given format. Returns an empty output for an empty output type.
+func Marshal(object interface{}, outputType OutputType) ([]byte, error) {
 	switch outputType {
 	case YamlOutput:
-		outBytes, _ := yaml.Marshal(object)
-		fmt.Print(string(outBytes))
+		return yaml.Marshal(object)
 	case JsonOutput:
-		outBytes, _ := json.MarshalIndent(object, "", "    ")
-		fmt.Println(string(outBytes))
+		outBytes, err := json.MarshalIndent(object, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+		return append(outBytes, '\n'), nil
+	case "":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown output type %v", outputType)
+}
+
+func PrettyPrint(object interface{}, outputType OutputType) {
+	outBytes, _ := Marshal(object, outputType)
+	fmt.Print(string(outBytes))
+}
+
+// WriteToFile writes the object to the path, eg. in a Tekton workspace. Creates the missing parent directories.
+func WriteToFile(object interface{}, outputType OutputType, path string) error {
+	if outputType == "" {
+		outputType = YamlOutput
+	}
+
+	outBytes, err := Marshal(object, outputType)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
 	}
+
+	return ioutil.WriteFile(path, outBytes, 0644)
 }
@@ -3,6 +3,7 @@ package output_test
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/output"
 	. "github.com/onsi/ginkgo"
@@ -46,4 +47,76 @@ var _ = Describe("Output", func() {
 		table.Entry("struct to yaml", TestStruct{Data: "test"}, output.YamlOutput, "Data: test\n"),
 		table.Entry("struct to json", TestStruct{Data: "test"}, output.JsonOutput, "{\n    \"Data\": \"test\"\n}\n"),
 	)
+
+	table.DescribeTable("gets output type from path", func(path string, expectedValue output.OutputType) {
+		Expect(output.GetOutputTypeFromPath(path)).To(Equal(expectedValue))
+	},
+		table.Entry("json", "/workspace/vm.json", output.JsonOutput),
+		table.Entry("upper case json", "/workspace/vm.JSON", output.JsonOutput),
+		table.Entry("yaml", "/workspace/vm.yaml", output.YamlOutput),
+		table.Entry("no extension", "/workspace/vm", output.YamlOutput),
+	)
+
+	It("fails to marshal unknown output type", func() {
+		_, err := output.Marshal(TestStruct{Data: "test"}, output.OutputType("xml"))
+		Expect(err).To(MatchError("unknown output type xml"))
+	})
+
+	Describe("writes to file", func() {
+		var tempDir string
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "test-output-")
+			Expect(err).Should(Succeed())
+		})
+		AfterEach(func() {
+			_ = os.RemoveAll(tempDir)
+		})
+
+		table.DescribeTable("writes correct content", func(outputType output.OutputType, expectedValue string) {
+			path := filepath.Join(tempDir, "nested", "output")
+			Expect(output.WriteToFile(TestStruct{Data: "test"}, outputType, path)).Should(Succeed())
+
+			content, err := ioutil.ReadFile(path)
+			Expect(err).Should(Succeed())
+			Expect(string(content)).To(Equal(expectedValue))
+		},
+			table.Entry("defaults to yaml", output.OutputType(""), "Data: test\n"),
+			table.Entry("yaml", output.YamlOutput, "Data: test\n"),
+			table.Entry("json", output.JsonOutput, "{\n    \"Data\": \"test\"\n}\n"),
+		)
+	})
+
+	table.DescribeTable("projects object by JSONPath", func(expression string, expectedValue string) {
+		object := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":   "my-vm",
+				"labels": map[string]string{"app": "test"},
+			},
+			"items": []string{"a", "b"},
+		}
+		Expect(output.JSONPath(object, expression)).To(Equal(expectedValue))
+	},
+		table.Entry("with braces", "{.metadata.name}", "my-vm"),
+		table.Entry("without braces", ".metadata.name", "my-vm"),
+		table.Entry("map", "{.metadata.labels}", "{\"app\":\"test\"}"),
+		table.Entry("array", "{.items[*]}", "a b"),
+	)
+
+	It("uses json names of struct fields", func() {
+		object := struct {
+			Name string `json:"name"`
+		}{Name: "my-vm"}
+		Expect(output.JSONPath(object, "{.name}")).To(Equal("my-vm"))
+	})
+
+	table.DescribeTable("fails on invalid JSONPath", func(expression string, expectedErr string) {
+		_, err := output.JSONPath(map[string]string{"name": "my-vm"}, expression)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expectedErr))
+	},
+		table.Entry("invalid expression", "{.name", "could not parse {.name JSONPath"),
+		table.Entry("missing key", "{.namespace}", "could not find {.namespace} in the output"),
+	)
 })
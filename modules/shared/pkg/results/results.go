@@ -1,21 +1,41 @@
 package results
 
 import (
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxResultsSize is the size of a termination message of a step, which Tekton uses to pass the results
+	MaxResultsSize = 4096
+	// resultOverhead approximates the size of a result in the termination message without its name and value
+	resultOverhead = 40
 )
 
 func RecordResults(results map[string]string) error {
 	return RecordResultsIn(env.GetTektonResultsDir(), results)
 }
 
+// RecordResultsIn writes the results limited to MaxResultsSize. The overflowing results are truncated with a warning.
 func RecordResultsIn(destination string, results map[string]string) error {
 	if results == nil || len(results) == 0 {
 		return nil
 	}
 
-	for resKey, resVal := range results {
+	limitedResults, overflowedNames := LimitResults(results, MaxResultsSize)
+	if len(overflowedNames) > 0 {
+		warnTruncated(overflowedNames)
+	}
+
+	for resKey, resVal := range limitedResults {
 		filename := filepath.Join(destination, resKey)
 		err := ioutil.WriteFile(filename, []byte(resVal), 0644)
 		if err != nil {
@@ -24,3 +44,64 @@ func RecordResultsIn(destination string, results map[string]string) error {
 	}
 	return nil
 }
+
+// LimitResults fits the results into maxSize. Each result gets an equal share of the free space and the results
+// bigger than their share are truncated. Returns the limited results and sorted names of the truncated results.
+func LimitResults(results map[string]string, maxSize int) (map[string]string, []string) {
+	limitedResults := make(map[string]string, len(results))
+	freeSize := maxSize
+	var names []string
+
+	for name, value := range results {
+		limitedResults[name] = value
+		names = append(names, name)
+		freeSize -= len(name) + resultOverhead
+	}
+
+	// smaller results first, so their unused share is given to the bigger ones
+	sort.Slice(names, func(i, j int) bool {
+		if len(results[names[i]]) == len(results[names[j]]) {
+			return names[i] < names[j]
+		}
+		return len(results[names[i]]) < len(results[names[j]])
+	})
+
+	var overflowedNames []string
+	for idx, name := range names {
+		share := 0
+		if freeSize > 0 {
+			share = freeSize / (len(names) - idx)
+		}
+
+		value := results[name]
+		if len(value) > share {
+			value = truncate(value, share)
+			limitedResults[name] = value
+			overflowedNames = append(overflowedNames, name)
+		}
+		freeSize -= len(value)
+	}
+
+	sort.Strings(overflowedNames)
+	return limitedResults, overflowedNames
+}
+
+// warnTruncated falls back to stderr when the logger is not initialized, so the truncation is never silent
+func warnTruncated(names []string) {
+	if log.Logger() == nil {
+		fmt.Fprintf(os.Stderr, "warning: results %v exceed the Tekton size limit of %v bytes and were truncated\n", names, MaxResultsSize)
+		return
+	}
+	log.Logger().Warn("results exceed the Tekton size limit and were truncated", zap.Strings("results", names), zap.Int("limit", MaxResultsSize))
+}
+
+func truncate(value string, size int) string {
+	if size <= 0 {
+		return ""
+	}
+	// do not cut a multi-byte character
+	for size > 0 && !utf8.RuneStart(value[size]) {
+		size--
+	}
+	return value[:size]
+}
@@ -1,13 +1,18 @@
 package results_test
 
 import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	results2 "github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/results"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const (
@@ -58,4 +63,67 @@ var _ = Describe("Results", func() {
 		})
 	})
 
+	Describe("Limits results", func() {
+		It("keeps results which fit", func() {
+			results := map[string]string{"name": "my-vm", "namespace": "default"}
+			limitedResults, overflowedNames := results2.LimitResults(results, results2.MaxResultsSize)
+			Expect(limitedResults).To(Equal(results))
+			Expect(overflowedNames).To(BeEmpty())
+		})
+
+		It("truncates the overflowing result and keeps the small ones", func() {
+			results := map[string]string{
+				"name": "my-vm",
+				"big":  strings.Repeat("a", 500),
+			}
+			limitedResults, overflowedNames := results2.LimitResults(results, 200)
+			Expect(overflowedNames).To(Equal([]string{"big"}))
+			Expect(limitedResults["name"]).To(Equal("my-vm"))
+			// 200 - (4 + 40) - (3 + 40) - 5
+			Expect(limitedResults["big"]).To(Equal(strings.Repeat("a", 108)))
+		})
+
+		It("shares the free space between the overflowing results", func() {
+			results := map[string]string{
+				"a": strings.Repeat("a", 500),
+				"b": strings.Repeat("b", 500),
+			}
+			limitedResults, overflowedNames := results2.LimitResults(results, 282)
+			Expect(overflowedNames).To(Equal([]string{"a", "b"}))
+			Expect(limitedResults["a"]).To(HaveLen(100))
+			Expect(limitedResults["b"]).To(HaveLen(100))
+		})
+
+		It("does not cut a multi-byte character", func() {
+			results := map[string]string{"a": strings.Repeat("č", 100)}
+			limitedResults, _ := results2.LimitResults(results, 46)
+			Expect(limitedResults["a"]).To(Equal("čč"))
+		})
+
+		It("records limited results", func() {
+			tempDir, err := ioutil.TempDir("", "test-record-results-")
+			Expect(err).Should(Succeed())
+			defer os.RemoveAll(tempDir)
+
+			Expect(results2.RecordResultsIn(tempDir, map[string]string{filenameA: strings.Repeat("a", 5000)})).Should(Succeed())
+			content, err := ioutil.ReadFile(filepath.Join(tempDir, filenameA))
+			Expect(err).Should(Succeed())
+			Expect(len(content)).To(Equal(results2.MaxResultsSize - len(filenameA) - 40))
+		})
+
+		It("warns about the truncated results", func() {
+			tempDir, err := ioutil.TempDir("", "test-record-results-")
+			Expect(err).Should(Succeed())
+			defer os.RemoveAll(tempDir)
+
+			core, logs := observer.New(zapcore.DebugLevel)
+			originalLogger := log.Logger()
+			log.SetLogger(zap.New(core))
+			defer log.SetLogger(originalLogger)
+
+			Expect(results2.RecordResultsIn(tempDir, map[string]string{filenameA: strings.Repeat("a", 5000), filenameB: contentB})).Should(Succeed())
+			Expect(logs.FilterMessage("results exceed the Tekton size limit and were truncated").All()).To(HaveLen(1))
+			Expect(logs.All()[0].ContextMap()).To(HaveKeyWithValue("results", []interface{}{filenameA}))
+		})
+	})
 })
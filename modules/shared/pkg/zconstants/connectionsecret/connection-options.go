@@ -15,6 +15,7 @@ type sshConnectionSecretKeys struct {
 	PrivateKeyAlternativeFormat  string
 	HostPublicKey                string
 	DisableStrictHostKeyChecking string
+	TrustHostKeyOnFirstUse       string
 	AdditionalSSHOptions         string
 }
 
@@ -24,5 +25,6 @@ var SSHConnectionSecretKeys = sshConnectionSecretKeys{
 	PrivateKeyAlternativeFormat:  "ssh-private-key",
 	HostPublicKey:                "host-public-key",
 	DisableStrictHostKeyChecking: "disable-strict-host-key-checking",
+	TrustHostKeyOnFirstUse:       "trust-host-key-on-first-use",
 	AdditionalSSHOptions:         "additional-ssh-options",
 }
@@ -37,7 +37,7 @@ var _ = Describe("Create DataVolume", func() {
 		table.Entry("empty dv", &testconfigs.CreateDVTestConfig{
 			TaskRunTestConfig: testconfigs.TaskRunTestConfig{
 				ServiceAccount: CreateDataVolumeFromManifestServiceAccountName,
-				ExpectedLogs:   "manifest does not contain DataVolume kind",
+				ExpectedLogs:   "one of dv-manifest, source-pvc, source-snapshot, source-registry-url, source-http-url, source-upload should be specified",
 			},
 			TaskData: testconfigs.CreateDVTaskData{
 				Datavolume: nil,
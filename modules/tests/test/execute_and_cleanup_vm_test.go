@@ -193,7 +193,7 @@ var _ = Describe("Execute in VM / Cleanup VM", func() {
 			}),
 			table.Entry("no secret host-key", &testconfigs.ExecuteOrCleanupVMTestConfig{
 				TaskRunTestConfig: testconfigs.TaskRunTestConfig{
-					ExpectedLogs: "host-public-key or disable-strict-host-key-checking=true secret attribute is required",
+					ExpectedLogs: "host-public-key, disable-strict-host-key-checking=true or trust-host-key-on-first-use=true secret attribute is required",
 				},
 				TaskData: testconfigs.ExecuteOrCleanupVMTaskData{
 					VM: testobjects.NewTestFedoraCloudVM("no-secret-host-key").Build(),
@@ -355,11 +355,10 @@ var _ = Describe("Execute in VM / Cleanup VM", func() {
 				TaskData: testconfigs.ExecuteOrCleanupVMTaskData{
 					VM: testobjects.NewTestFedoraCloudVM("execute-script-with-options").WithCloudConfig(fedoraCloudConfig).Build(),
 					Secret: testobjects.NewTestSecret(map[string]string{
-						"type":           "ssh",
-						"user":           "fedora",
-						"ssh-privatekey": testconstants.SSHTestPrivateKey,
-						// TODO change to safer accept-new once a newer version of ssh which supports this option is available in CI
-						"additional-ssh-options":           "-oStrictHostKeyChecking=no -C",
+						"type":                             "ssh",
+						"user":                             "fedora",
+						"ssh-privatekey":                   testconstants.SSHTestPrivateKey,
+						"additional-ssh-options":           "-oStrictHostKeyChecking=accept-new -C",
 						"disable-strict-host-key-checking": "true",
 					}),
 					Script: helloWorldScript,
@@ -3,12 +3,16 @@ package main
 import (
 	goarg "github.com/alexflint/go-arg"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/exit"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/metrics"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	. "github.com/kubevirt/kubevirt-tekton-tasks/modules/wait-for-vmi-status/pkg/constants"
-	"github.com/kubevirt/kubevirt-tekton-tasks/modules/wait-for-vmi-status/pkg/utils/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/wait-for-vmi-status/pkg/utils/parse"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/wait-for-vmi-status/pkg/watch"
 	"go.uber.org/zap"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	"os"
+	"time"
 )
 
 func main() {
@@ -17,7 +21,7 @@ func main() {
 	cliOptions := &parse.CLIOptions{}
 	goarg.MustParse(cliOptions)
 
-	logger := log.InitLogger(cliOptions.GetDebugLevel())
+	logger := log.InitLoggerWithFormat(cliOptions.GetDebugLevel(), cliOptions.GetLogFormat())
 	defer logger.Sync()
 
 	log.Logger().Debug("parsed arguments", zap.Reflect("cliOptions", cliOptions))
@@ -30,9 +34,27 @@ func main() {
 		exit.ExitOrDieFromError(WatchFacadeInitFailed, err)
 	}
 
-	success := watchFacade.WaitForVMIConditions()
+	log.AddTarget(kubevirtv1.VirtualMachineInstanceGroupVersionKind.Kind, cliOptions.GetVirtualMachineInstanceNamespace(), cliOptions.GetVirtualMachineInstanceName())
 
-	if !success {
+	metricLabels := metrics.Labels{"vmi": cliOptions.GetVirtualMachineInstanceName()}
+	start := time.Now()
+	err = log.Phase("WaitForVMIConditions", func() error {
+		if !watchFacade.WaitForVMIConditions() {
+			return zerrors.NewSoftError("failure condition was fulfilled")
+		}
+		return nil
+	})
+	metrics.RecordDuration("vmi_wait_duration_seconds", "Duration until a success or failure condition was fulfilled", start, metricLabels)
+	metrics.RecordOutcome("vmi_wait_success", "Whether the success condition was fulfilled", err, metricLabels)
+	exportMetrics(cliOptions)
+
+	if err != nil {
 		os.Exit(FailureConditionFulfilled)
 	}
 }
+
+func exportMetrics(cliOptions *parse.CLIOptions) {
+	if err := metrics.Export(cliOptions.GetMetricsFile(), cliOptions.GetMetricsPushgatewayURL()); err != nil {
+		log.Logger().Warn("could not export metrics", zap.Error(err))
+	}
+}
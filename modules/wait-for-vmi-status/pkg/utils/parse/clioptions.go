@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/wait-for-vmi-status/pkg/requirements"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/labels"
@@ -19,6 +20,9 @@ type CLIOptions struct {
 	SuccessCondition                string `arg:"--success-condition,env:SUCCESS_CONDITION" placeholder:"CONDITION" help:" A label selector expression to decide if the VirtualMachineInstance (VMI) is in a success state. Eg. \"status.phase == Succeeded\". It is evaluated on each VMI update and will result in this task succeeding if true."`
 	FailureCondition                string `arg:"--failure-condition,env:FAILURE_CONDITION" placeholder:"CONDITION" help:"A label selector expression to decide if the VirtualMachineInstance (VMI) is in a failed state. Eg. \"status.phase in (Failed, Unknown)\". It is evaluated on each VMI update and will result in this task failing if true."`
 	Debug                           bool   `arg:"--debug" help:"Sets DEBUG log level"`
+	LogFormat                       string `arg:"--log-format,env:LOG_FORMAT" placeholder:"json|console" help:"Log format. One of: json|console. Defaults to json."`
+	MetricsFile                     string `arg:"--metrics-file,env:METRICS_FILE" placeholder:"PATH" help:"Write the durations and outcomes of the task to this file in OpenMetrics text format, eg. in a workspace."`
+	MetricsPushgatewayURL           string `arg:"--metrics-pushgateway-url,env:METRICS_PUSHGATEWAY_URL" placeholder:"URL" help:"Push the durations and outcomes of the task to this Pushgateway, eg. http://pushgateway:9091"`
 }
 
 func (c *CLIOptions) GetDebugLevel() zapcore.Level {
@@ -28,6 +32,18 @@ func (c *CLIOptions) GetDebugLevel() zapcore.Level {
 	return zapcore.InfoLevel
 }
 
+func (c *CLIOptions) GetLogFormat() log.LogFormat {
+	return log.LogFormat(c.LogFormat)
+}
+
+func (c *CLIOptions) GetMetricsFile() string {
+	return c.MetricsFile
+}
+
+func (c *CLIOptions) GetMetricsPushgatewayURL() string {
+	return c.MetricsPushgatewayURL
+}
+
 func (c *CLIOptions) GetVirtualMachineInstanceName() string {
 	return c.VirtualMachineInstanceName
 }
@@ -75,5 +91,13 @@ func (c *CLIOptions) Init() error {
 		return err
 	}
 
+	if err := c.validateLogFormat(); err != nil {
+		return err
+	}
+
+	if err := c.validateMetricsPushgatewayURL(); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -48,6 +48,16 @@ var _ = Describe("CLIOptions", func() {
 			VirtualMachineInstanceNamespace: defaultNS,
 			FailureCondition:                "test.....test",
 		}),
+		table.Entry("invalid log format", "text is not a valid log format", &parse.CLIOptions{
+			VirtualMachineInstanceName:      "test",
+			VirtualMachineInstanceNamespace: defaultNS,
+			LogFormat:                       "text",
+		}),
+		table.Entry("invalid Pushgateway URL", "pushgateway:9091 is not a valid Pushgateway URL", &parse.CLIOptions{
+			VirtualMachineInstanceName:      "test",
+			VirtualMachineInstanceNamespace: defaultNS,
+			MetricsPushgatewayURL:           "pushgateway:9091",
+		}),
 	)
 
 	table.DescribeTable("Parses and returns correct values", func(options *parse.CLIOptions, expectedOptions map[string]interface{}) {
@@ -1,7 +1,7 @@
 package parse_test
 
 import (
-	log2 "github.com/kubevirt/kubevirt-tekton-tasks/modules/wait-for-vmi-status/pkg/utils/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"go.uber.org/zap"
 	"testing"
 
@@ -15,5 +15,5 @@ func TestParse(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
-	log2.InitLogger(zap.DebugLevel)
+	log.InitLogger(zap.DebugLevel)
 })
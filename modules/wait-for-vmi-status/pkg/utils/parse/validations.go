@@ -2,6 +2,8 @@ package parse
 
 import (
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/env"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/metrics"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/zerrors"
 	"github.com/kubevirt/kubevirt-tekton-tasks/modules/wait-for-vmi-status/pkg/requirements"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -9,7 +11,7 @@ import (
 )
 
 func (c *CLIOptions) trimSpaces() {
-	for _, strVariablePtr := range []*string{&c.VirtualMachineInstanceName, &c.VirtualMachineInstanceNamespace, &c.SuccessCondition, &c.FailureCondition} {
+	for _, strVariablePtr := range []*string{&c.VirtualMachineInstanceName, &c.VirtualMachineInstanceNamespace, &c.SuccessCondition, &c.FailureCondition, &c.MetricsFile, &c.MetricsPushgatewayURL} {
 		*strVariablePtr = strings.TrimSpace(*strVariablePtr)
 	}
 }
@@ -55,3 +57,17 @@ func (c *CLIOptions) validateConditions() error {
 	}
 	return nil
 }
+
+func (c *CLIOptions) validateLogFormat() error {
+	if !log.IsLogFormat(c.LogFormat) {
+		return zerrors.NewMissingRequiredError("%v is not a valid log format", c.LogFormat)
+	}
+	return nil
+}
+
+func (c *CLIOptions) validateMetricsPushgatewayURL() error {
+	if !metrics.IsPushgatewayURL(c.MetricsPushgatewayURL) {
+		return zerrors.NewMissingRequiredError("%v is not a valid Pushgateway URL", c.MetricsPushgatewayURL)
+	}
+	return nil
+}
@@ -1,7 +1,7 @@
 package utilstest
 
 import (
-	log2 "github.com/kubevirt/kubevirt-tekton-tasks/modules/wait-for-vmi-status/pkg/utils/log"
+	"github.com/kubevirt/kubevirt-tekton-tasks/modules/shared/pkg/log"
 	"github.com/onsi/gomega"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/labels"
@@ -9,7 +9,7 @@ import (
 )
 
 func SetupTestSuite() {
-	log2.InitLogger(zap.InfoLevel)
+	log.InitLogger(zap.InfoLevel)
 }
 
 func GetRequirement(key string, op selection.Operator, vals []string) labels.Requirement {
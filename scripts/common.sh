@@ -28,6 +28,10 @@ IMAGE_MODULE_NAME_TO_ENV_NAME["create-vm"]="CREATE_VM_IMAGE"
 TASK_NAME_TO_IMAGE["create-vm-from-manifest"]="${CREATE_VM_IMAGE}"
 TASK_NAME_TO_IMAGE["create-vm-from-template"]="${CREATE_VM_IMAGE}"
 
+export CREATE_DATAVOLUME_IMAGE="${CREATE_DATAVOLUME_IMAGE:-}"
+IMAGE_MODULE_NAME_TO_ENV_NAME["create-datavolume"]="CREATE_DATAVOLUME_IMAGE"
+TASK_NAME_TO_IMAGE["create-datavolume-from-manifest"]="${CREATE_DATAVOLUME_IMAGE}"
+
 export EXECUTE_IN_VM_IMAGE="${EXECUTE_IN_VM_IMAGE:-}"
 IMAGE_MODULE_NAME_TO_ENV_NAME["execute-in-vm"]="EXECUTE_IN_VM_IMAGE"
 TASK_NAME_TO_IMAGE["execute-in-vm"]="${EXECUTE_IN_VM_IMAGE}"